@@ -0,0 +1,332 @@
+// Package archive packs a profile directory into a single .tar.gz or
+// .zip file and unpacks such files again, so profiles can be shared as
+// one attachment.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/acchapm1/ocmgr/internal/copier"
+)
+
+// Format is an archive file format.
+type Format string
+
+const (
+	// TarGz is a gzip-compressed tarball.
+	TarGz Format = "tar.gz"
+	// Zip is a zip file.
+	Zip Format = "zip"
+)
+
+// ParseFormat validates a --format value.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case TarGz, Zip:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("invalid archive format %q; must be one of: tar.gz, zip", s)
+}
+
+// Ext returns the file extension for f, including the leading dot.
+func (f Format) Ext() string {
+	return "." + string(f)
+}
+
+// Detect returns the format of the archive at path, judged by its file
+// extension, and whether it is an archive at all.
+func Detect(path string) (Format, bool) {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return TarGz, true
+	case strings.HasSuffix(lower, ".zip"):
+		return Zip, true
+	}
+	return "", false
+}
+
+// entry is one file or directory to be archived.
+type entry struct {
+	name string // slash-separated path inside the archive
+	path string // path on disk; empty for directories
+	info os.FileInfo
+}
+
+// Write archives the directory src into a new file at dst. Every entry
+// is stored below a top-level directory called root, so extracting the
+// archive yields a single directory. Like github.CopyDirRecursive, .git
+// directories and the hash manifest are skipped and symlinks are stored
+// as the files they point to.
+func Write(src, dst, root string, format Format) error {
+	entries, err := collect(src, root)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case TarGz:
+		err = writeTarGz(f, entries)
+	case Zip:
+		err = writeZip(f, entries)
+	default:
+		err = fmt.Errorf("unsupported archive format %q", format)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return fmt.Errorf("writing %s: %w", dst, err)
+	}
+	return nil
+}
+
+// collect lists the entries below src in walk order.
+func collect(src, root string) ([]entry, error) {
+	var entries []entry
+	err := filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && info.Name() == copier.ManifestFile {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(root, filepath.ToSlash(rel))
+
+		if info.IsDir() {
+			entries = append(entries, entry{name: name + "/", info: info})
+			return nil
+		}
+
+		// Follow symlinks; anything that is not a regular file in the
+		// end (sockets, links to directories, ...) is left out.
+		if info.Mode()&os.ModeSymlink != 0 {
+			if info, err = os.Stat(p); err != nil {
+				return err
+			}
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		entries = append(entries, entry{name: name, path: p, info: info})
+		return nil
+	})
+	return entries, err
+}
+
+func writeTarGz(w io.Writer, entries []entry) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr, err := tar.FileInfoHeader(e.info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = e.name
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if e.path == "" {
+			continue
+		}
+		if err := copyFrom(tw, e.path); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeZip(w io.Writer, entries []entry) error {
+	zw := zip.NewWriter(w)
+	for _, e := range entries {
+		hdr, err := zip.FileInfoHeader(e.info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = e.name
+		if e.path != "" {
+			hdr.Method = zip.Deflate
+		}
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if e.path == "" {
+			continue
+		}
+		if err := copyFrom(fw, e.path); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// copyFrom streams the file at path into w.
+func copyFrom(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Extract unpacks the archive at src into the directory dst, which is
+// created if needed. Entries that would land outside dst, and anything
+// other than regular files and directories, are rejected.
+func Extract(src, dst string) error {
+	format, ok := Detect(src)
+	if !ok {
+		return fmt.Errorf("%s: unrecognised archive type; expected .tar.gz, .tgz or .zip", src)
+	}
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return err
+	}
+
+	var err error
+	switch format {
+	case TarGz:
+		err = extractTarGz(src, dst)
+	case Zip:
+		err = extractZip(src, dst)
+	}
+	if err != nil {
+		return fmt.Errorf("extracting %s: %w", src, err)
+	}
+	return nil
+}
+
+func extractTarGz(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := safeJoin(dst, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, hdr.FileInfo()); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			// Metadata written by e.g. "git archive"; nothing to extract.
+		default:
+			return fmt.Errorf("%s: unsupported entry type", hdr.Name)
+		}
+	}
+}
+
+func extractZip(src, dst string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		target, err := safeJoin(dst, zf.Name)
+		if err != nil {
+			return err
+		}
+		info := zf.FileInfo()
+		switch {
+		case info.IsDir():
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			err = writeFile(target, rc, info)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: unsupported entry type", zf.Name)
+		}
+	}
+	return nil
+}
+
+// safeJoin resolves an archive entry name below dst, refusing absolute
+// names and names that climb out of dst with "..".
+func safeJoin(dst, name string) (string, error) {
+	clean := path.Clean(filepath.ToSlash(name))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%s: entry path escapes the archive", name)
+	}
+	return filepath.Join(dst, filepath.FromSlash(clean)), nil
+}
+
+// writeFile writes r to target with the permission bits and
+// modification time from info.
+func writeFile(target string, r io.Reader, info os.FileInfo) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm()|0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(target, info.ModTime(), info.ModTime())
+}
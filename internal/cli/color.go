@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+)
+
+// noColor is set by the persistent --no-color flag.
+var noColor bool
+
+// colorEnabled reports whether ANSI colour codes may be written to f:
+// colour has not been turned off with --no-color or the NO_COLOR
+// environment variable, and f is a terminal rather than a pipe or file.
+func colorEnabled(f *os.File) bool {
+	return !noColor && os.Getenv("NO_COLOR") == "" && term.IsTerminal(f.Fd())
+}
+
+// disableColorIfNeeded turns off lipgloss styling for command output
+// that is not going to a colour-capable terminal.
+func disableColorIfNeeded() {
+	if !colorEnabled(os.Stdout) {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/acchapm1/ocmgr/internal/config"
+	"github.com/acchapm1/ocmgr/internal/github"
+	"github.com/acchapm1/ocmgr/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for the given shell and print it to
+stdout. Profile names are completed for commands that take one.
+
+  bash:        source <(ocmgr completion bash)
+  zsh:         ocmgr completion zsh > "${fpath[1]}/_ocmgr"
+  fish:        ocmgr completion fish > ~/.config/fish/completions/ocmgr.fish
+  powershell:  ocmgr completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		return fmt.Errorf("unsupported shell %q; must be one of: bash, zsh, fish, powershell", args[0])
+	},
+}
+
+// completeProfileNames completes the names of profiles in the local
+// store.
+func completeProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	s, err := store.NewStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	profiles, err := s.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, p := range profiles {
+		if strings.HasPrefix(p.Name, toComplete) {
+			names = append(names, p.Name+"\t"+p.Description)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileSets completes the names of the profile sets in the
+// config, each described by the profiles it applies.
+func completeProfileSets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for name, profiles := range cfg.ProfileSets {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name+"\t"+strings.Join(profiles, ", "))
+		}
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRemoteProfileNames completes the names of profiles in the sync
+// cache. It does not contact the remote, so the list is as of the last
+// sync.
+func completeRemoteProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	remote, err := github.CachedProfileNames()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, n := range remote {
+		if strings.HasPrefix(n, toComplete) {
+			names = append(names, n)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// firstArg restricts a completion function to the first positional
+// argument; later arguments fall back to file completion.
+func firstArg(complete cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+func init() {
+	// Replace cobra's implicit completion command with ours.
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+
+	for _, cmd := range []*cobra.Command{
+		profileShowCmd,
+		profileDeleteCmd,
+		profileExportCmd,
+		profileCloneCmd,
+		profileTagCmd,
+		profileLockCmd,
+		profileUnlockCmd,
+		profileTreeCmd,
+		syncPushCmd,
+		syncDiffCmd,
+		syncLogCmd,
+		diffCmd,
+	} {
+		cmd.ValidArgsFunction = firstArg(completeProfileNames)
+	}
+	syncPullCmd.ValidArgsFunction = firstArg(completeRemoteProfileNames)
+	profileBumpCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return []string{"major", "minor", "patch"}, cobra.ShellCompDirectiveNoFileComp
+		}
+		return firstArg(completeProfileNames)(cmd, args, toComplete)
+	}
+}
@@ -4,12 +4,44 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/acchapm1/ocmgr/internal/config"
+	"github.com/acchapm1/ocmgr/internal/copier"
+	"github.com/acchapm1/ocmgr/internal/github"
+	"github.com/acchapm1/ocmgr/internal/profile"
 	"github.com/spf13/cobra"
 )
 
+// validAuthMethods lists the accepted values of github.auth.
+var validAuthMethods = map[string]bool{"gh": true, "env": true, "ssh": true, "token": true}
+
+// validStrategies lists the accepted values of defaults.merge_strategy.
+var validStrategies = map[copier.Strategy]bool{
+	copier.StrategyPrompt:    true,
+	copier.StrategyOverwrite: true,
+	copier.StrategyMerge:     true,
+	copier.StrategySkip:      true,
+}
+
+// validUpdateChannels lists the accepted values of defaults.update_channel.
+var validUpdateChannels = map[string]bool{"stable": true, "prerelease": true}
+
+// configKeys lists the keys accepted by "config get" and "config set".
+var configKeys = []string{
+	"github.repo", "github.host", "github.auth", "github.branch", "github.protected_branches",
+	"defaults.merge_strategy", "defaults.editor", "defaults.max_files", "defaults.max_bytes",
+	"defaults.update_channel", "defaults.content_dirs",
+	"store.path", "store.cache_dir",
+	"theme.name", "theme.primary", "theme.secondary", "theme.success", "theme.warning",
+	"theme.error", "theme.muted", "theme.text", "theme.background",
+	"profile_sets.<name>",
+}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage ocmgr configuration",
@@ -24,31 +56,199 @@ var configShowCmd = &cobra.Command{
 			return fmt.Errorf("loading config: %w", err)
 		}
 
-		fmt.Printf("Configuration (~/.ocmgr/config.toml):\n\n")
+		fmt.Printf("Configuration (%s):\n", config.ConfigPath())
+		if wd, err := os.Getwd(); err == nil {
+			if project := config.FindProjectFile(wd); project != "" {
+				fmt.Printf("Overridden by %s\n", project)
+			}
+		}
+		fmt.Println()
 		fmt.Printf("[github]\n")
-		fmt.Printf("  %-16s = %s\n", "repo", cfg.GitHub.Repo)
-		fmt.Printf("  %-16s = %s\n", "auth", cfg.GitHub.Auth)
+		fmt.Printf("  %-18s = %s\n", "repo", cfg.GitHub.Repo)
+		fmt.Printf("  %-18s = %s\n", "host", cfg.GitHub.Host)
+		fmt.Printf("  %-18s = %s\n", "auth", cfg.GitHub.Auth)
+		fmt.Printf("  %-18s = %s\n", "branch", cfg.GitHub.Branch)
+		fmt.Printf("  %-18s = %s\n", "protected_branches", strings.Join(cfg.GitHub.ProtectedBranches, ", "))
 		fmt.Printf("\n")
 		fmt.Printf("[defaults]\n")
-		fmt.Printf("  %-16s = %s\n", "merge_strategy", cfg.Defaults.MergeStrategy)
-		fmt.Printf("  %-16s = %s\n", "editor", cfg.Defaults.Editor)
+		fmt.Printf("  %-18s = %s\n", "merge_strategy", cfg.Defaults.MergeStrategy)
+		fmt.Printf("  %-18s = %s\n", "editor", cfg.Defaults.Editor)
+		fmt.Printf("  %-18s = %d\n", "max_files", cfg.Defaults.MaxFiles)
+		fmt.Printf("  %-18s = %d\n", "max_bytes", cfg.Defaults.MaxBytes)
+		fmt.Printf("  %-18s = %s\n", "update_channel", cfg.Defaults.UpdateChannel)
+		fmt.Printf("  %-18s = %s\n", "content_dirs", strings.Join(cfg.Defaults.ContentDirs, ", "))
 		fmt.Printf("\n")
 		fmt.Printf("[store]\n")
-		fmt.Printf("  %-16s = %s\n", "path", cfg.Store.Path)
+		fmt.Printf("  %-18s = %s\n", "path", cfg.Store.Path)
+		fmt.Printf("  %-18s = %s\n", "cache_dir", cfg.Store.CacheDir)
+		if env := os.Getenv("OCMGR_CACHE_DIR"); env != "" {
+			fmt.Printf("  %-18s   (overridden by OCMGR_CACHE_DIR=%s)\n", "", env)
+		}
+		fmt.Printf("\n")
+		fmt.Printf("[theme]\n")
+		fmt.Printf("  %-18s = %s\n", "name", cfg.Theme.Name)
+		for _, key := range themeColorKeys {
+			if value := *themeColor(&cfg.Theme, key); value != "" {
+				fmt.Printf("  %-18s = %s\n", key, value)
+			}
+		}
+		if len(cfg.ProfileSets) > 0 {
+			fmt.Printf("\n")
+			fmt.Printf("[profile_sets]\n")
+			names := make([]string, 0, len(cfg.ProfileSets))
+			for name := range cfg.ProfileSets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("  %-18s = %s\n", name, strings.Join(cfg.ProfileSets[name], ", "))
+			}
+		}
 
 		return nil
 	},
 }
 
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a single configuration value",
+	Long: `Print the value of one configuration key to stdout, with no other
+output, for use in scripts. It accepts the same keys as "config set".
+Lists (github.protected_branches, defaults.content_dirs,
+profile_sets.<name>) are printed comma-separated.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		value, ok := configValue(cfg, args[0])
+		if !ok {
+			return fmt.Errorf("unrecognized key %q\nValid keys: %s", args[0], strings.Join(configKeys, ", "))
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+// configValue returns the value of key in cfg formatted as "config set"
+// accepts it, and false if key is not recognised.
+func configValue(cfg *config.Config, key string) (string, bool) {
+	switch key {
+	case "github.repo":
+		return cfg.GitHub.Repo, true
+	case "github.host":
+		return cfg.GitHub.Host, true
+	case "github.auth":
+		return cfg.GitHub.Auth, true
+	case "github.branch":
+		return cfg.GitHub.Branch, true
+	case "github.protected_branches":
+		return strings.Join(cfg.GitHub.ProtectedBranches, ","), true
+	case "defaults.merge_strategy":
+		return cfg.Defaults.MergeStrategy, true
+	case "defaults.editor":
+		return cfg.Defaults.Editor, true
+	case "defaults.max_files":
+		return strconv.Itoa(cfg.Defaults.MaxFiles), true
+	case "defaults.max_bytes":
+		return strconv.FormatInt(cfg.Defaults.MaxBytes, 10), true
+	case "defaults.update_channel":
+		return cfg.Defaults.UpdateChannel, true
+	case "defaults.content_dirs":
+		return strings.Join(cfg.Defaults.ContentDirs, ","), true
+	case "store.path":
+		return cfg.Store.Path, true
+	case "store.cache_dir":
+		return cfg.Store.CacheDir, true
+	case "theme.name":
+		return cfg.Theme.Name, true
+	}
+	if name, ok := strings.CutPrefix(key, "theme."); ok {
+		if color := themeColor(&cfg.Theme, name); color != nil {
+			return *color, true
+		}
+	}
+	if name, ok := strings.CutPrefix(key, "profile_sets."); ok {
+		if profiles, ok := cfg.ProfileSets[name]; ok {
+			return strings.Join(profiles, ","), true
+		}
+	}
+	return "", false
+}
+
+// setProfileSet sets the profile set name to the comma-separated
+// profiles in value, or removes it when value is empty.
+func setProfileSet(cfg *config.Config, name, value string) error {
+	if err := profile.ValidateName(name); err != nil {
+		return fmt.Errorf("invalid profile set name: %w", err)
+	}
+	var profiles []string
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		if err := profile.ValidateName(p); err != nil {
+			return err
+		}
+		profiles = append(profiles, p)
+	}
+	if len(profiles) == 0 {
+		delete(cfg.ProfileSets, name)
+		return nil
+	}
+	if cfg.ProfileSets == nil {
+		cfg.ProfileSets = make(map[string][]string)
+	}
+	cfg.ProfileSets[name] = profiles
+	return nil
+}
+
+// themeColorKeys lists the colour keys of the [theme] section.
+var themeColorKeys = []string{"primary", "secondary", "success", "warning", "error", "muted", "text", "background"}
+
+// themeColor returns the field of t holding the named colour, or nil if
+// there is no such colour.
+func themeColor(t *config.Theme, name string) *string {
+	switch name {
+	case "primary":
+		return &t.Primary
+	case "secondary":
+		return &t.Secondary
+	case "success":
+		return &t.Success
+	case "warning":
+		return &t.Warning
+	case "error":
+		return &t.Error
+	case "muted":
+		return &t.Muted
+	case "text":
+		return &t.Text
+	case "background":
+		return &t.Background
+	}
+	return nil
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set a configuration value",
-	Args:  cobra.ExactArgs(2),
+	Long: `Set a key in the global config.toml ("ocmgr config path" prints
+its location). Values pinned by a
+project's .ocmgr.toml still take precedence inside that project; edit
+that file to change them.
+
+profile_sets.<name> saves a list of profiles for "init --profile-set",
+e.g. "ocmgr config set profile_sets.backend base,go,company". Setting it
+to an empty value removes the set.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
 		value := args[1]
 
-		cfg, err := config.Load()
+		cfg, err := config.LoadGlobal()
 		if err != nil {
 			return fmt.Errorf("loading config: %w", err)
 		}
@@ -56,24 +256,91 @@ var configSetCmd = &cobra.Command{
 		switch key {
 		case "github.repo":
 			cfg.GitHub.Repo = value
+		case "github.host":
+			host := strings.TrimSpace(value)
+			if host == "" || strings.Contains(host, "://") || strings.ContainsAny(host, "/ ") {
+				return fmt.Errorf("invalid host %q; expected a bare host name such as gitlab.example.com", value)
+			}
+			cfg.GitHub.Host = host
 		case "github.auth":
-			validAuth := map[string]bool{"gh": true, "env": true, "ssh": true, "token": true}
-			if !validAuth[value] {
+			if !validAuthMethods[value] {
 				return fmt.Errorf("invalid auth method %q; must be one of: gh, env, ssh, token", value)
 			}
 			cfg.GitHub.Auth = value
+		case "github.branch":
+			branch := strings.TrimSpace(value)
+			if strings.HasPrefix(branch, "-") || strings.ContainsAny(branch, " \t") {
+				return fmt.Errorf("invalid branch %q", value)
+			}
+			cfg.GitHub.Branch = branch
+		case "github.protected_branches":
+			var branches []string
+			for _, b := range strings.Split(value, ",") {
+				if b = strings.TrimSpace(b); b != "" {
+					branches = append(branches, b)
+				}
+			}
+			cfg.GitHub.ProtectedBranches = branches
 		case "defaults.merge_strategy":
-			validStrategies := map[string]bool{"prompt": true, "overwrite": true, "merge": true, "skip": true}
-			if !validStrategies[value] {
+			if !validStrategies[copier.Strategy(value)] {
 				return fmt.Errorf("invalid merge strategy %q; must be one of: prompt, overwrite, merge, skip", value)
 			}
 			cfg.Defaults.MergeStrategy = value
 		case "defaults.editor":
 			cfg.Defaults.Editor = value
+		case "defaults.max_files":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid max_files %q; must be a non-negative integer", value)
+			}
+			cfg.Defaults.MaxFiles = n
+		case "defaults.max_bytes":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid max_bytes %q; must be a non-negative integer", value)
+			}
+			cfg.Defaults.MaxBytes = n
+		case "defaults.update_channel":
+			if !validUpdateChannels[value] {
+				return fmt.Errorf("invalid update channel %q; must be one of: stable, prerelease", value)
+			}
+			cfg.Defaults.UpdateChannel = value
+		case "defaults.content_dirs":
+			var dirs []string
+			for _, d := range strings.Split(value, ",") {
+				if d = strings.TrimSpace(d); d != "" {
+					dirs = append(dirs, d)
+				}
+			}
+			if err := config.ValidateContentDirs(dirs); err != nil {
+				return err
+			}
+			cfg.Defaults.ContentDirs = dirs
 		case "store.path":
 			cfg.Store.Path = value
+		case "store.cache_dir":
+			cfg.Store.CacheDir = value
+		case "theme.name":
+			cfg.Theme.Name = value
+			if err := cfg.Theme.Validate(); err != nil {
+				return err
+			}
 		default:
-			return fmt.Errorf("unrecognized key %q\nValid keys: github.repo, github.auth, defaults.merge_strategy, defaults.editor, store.path", key)
+			if name, ok := strings.CutPrefix(key, "profile_sets."); ok {
+				if err := setProfileSet(cfg, name, value); err != nil {
+					return err
+				}
+				break
+			}
+			name, ok := strings.CutPrefix(key, "theme.")
+			color := themeColor(&cfg.Theme, name)
+			if !ok || color == nil {
+				return fmt.Errorf("unrecognized key %q\nValid keys: %s", key, strings.Join(configKeys, ", "))
+			}
+			*color = value
+			if err := cfg.Theme.Validate(); err != nil {
+				return err
+			}
 		}
 
 		if err := config.Save(cfg); err != nil {
@@ -106,31 +373,247 @@ var configInitCmd = &cobra.Command{
 		mergeStrategy := prompt("Default merge strategy (prompt/overwrite/merge/skip)", "prompt")
 		editor := prompt("Editor", "nvim")
 
-		cfg := &config.Config{
-			GitHub: config.GitHub{
-				Repo: repo,
-				Auth: auth,
-			},
-			Defaults: config.Defaults{
-				MergeStrategy: mergeStrategy,
-				Editor:        editor,
-			},
-			Store: config.Store{
-				Path: "~/.ocmgr/profiles",
-			},
-		}
+		cfg := config.DefaultConfig()
+		cfg.GitHub.Repo = repo
+		cfg.GitHub.Auth = auth
+		cfg.Defaults.MergeStrategy = mergeStrategy
+		cfg.Defaults.Editor = editor
 
 		if err := config.Save(cfg); err != nil {
 			return fmt.Errorf("saving config: %w", err)
 		}
 
-		fmt.Println("Configuration saved to ~/.ocmgr/config.toml")
+		fmt.Println("Configuration saved to " + config.ConfigPath())
 		return nil
 	},
 }
 
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the configuration file in your editor",
+	Long: `Open the global config.toml ("ocmgr config path" prints its
+location) in the configured editor (defaults.editor, then $EDITOR,
+then nvim or vi), creating it with the default settings
+first if it does not exist. When the editor exits the file is loaded
+again and checked as by "config validate"; any problems are reported
+so they can be fixed straight away.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := config.ConfigPath()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if err := config.Save(config.DefaultConfig()); err != nil {
+				return fmt.Errorf("creating %s: %w", path, err)
+			}
+			fmt.Printf("Created %s with the default settings\n", path)
+		}
+
+		// A config that no longer parses is what the user may be here to
+		// fix, so fall back to $EDITOR rather than failing.
+		cfg, err := config.LoadGlobal()
+		if err != nil {
+			cfg = config.DefaultConfig()
+		}
+		argv := cfg.EditorCommand()
+		editor := exec.Command(argv[0], append(argv[1:], path)...)
+		editor.Stdin = os.Stdin
+		editor.Stdout = os.Stdout
+		editor.Stderr = os.Stderr
+		if err := editor.Run(); err != nil {
+			return fmt.Errorf("running %s: %w", argv[0], err)
+		}
+
+		cfg, err = config.LoadGlobal()
+		if err != nil {
+			return fmt.Errorf("%s does not parse: %w; run \"ocmgr config edit\" again to fix it", path, err)
+		}
+		failed := 0
+		checkConfigValues(cfg, func(name string, err error, detail string) {
+			if err != nil {
+				failed++
+				fmt.Printf("✗ %-24s %v\n", name, err)
+			}
+		})
+		if failed > 0 {
+			return fmt.Errorf("%d configuration checks failed; run \"ocmgr config edit\" again to fix them", failed)
+		}
+		fmt.Printf("✓ %s is valid\n", path)
+		return nil
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print where ocmgr keeps its files",
+	Long: `Print the locations ocmgr uses, one key=value pair per line:
+
+  config    the global config file
+  project   the .ocmgr.toml in effect here (empty if none)
+  store     the local profile store (store.path)
+  cache     the sync cache (store.cache_dir or OCMGR_CACHE_DIR)
+  token     the token file read when github.auth = "token"
+
+Paths are absolute, with "~" expanded and project overrides applied.
+With --json the locations are printed as a JSON object.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		project := ""
+		if wd, err := os.Getwd(); err == nil {
+			project = config.FindProjectFile(wd)
+		}
+		store, err := filepath.Abs(config.ExpandPath(cfg.Store.Path))
+		if err != nil {
+			return fmt.Errorf("resolving store.path: %w", err)
+		}
+		cache, err := filepath.Abs(config.CacheDir())
+		if err != nil {
+			return fmt.Errorf("resolving the sync cache: %w", err)
+		}
+
+		paths := []struct {
+			Key  string
+			Path string
+		}{
+			{"config", config.ConfigPath()},
+			{"project", project},
+			{"store", store},
+			{"cache", cache},
+			{"token", github.TokenPath()},
+		}
+		if jsonOutput {
+			out := make(map[string]string, len(paths))
+			for _, p := range paths {
+				out[p.Key] = p.Path
+			}
+			return printJSON(out)
+		}
+		for _, p := range paths {
+			fmt.Printf("%s=%s\n", p.Key, p.Path)
+		}
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration for problems",
+	Long: `Load the global config.toml and check that its values are usable:
+the repository slug and host, the auth method (including the token
+file for auth = "token"), the default merge strategy, and that the
+store path is a writable directory. Each check is printed with a
+pass/fail marker and the command exits non-zero if any check fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("✗ %-24s %v\n", "config.toml parses", err)
+			return fmt.Errorf("configuration is invalid")
+		}
+		fmt.Printf("✓ %-24s %s\n", "config.toml parses", config.ConfigPath())
+
+		failed := 0
+		checkConfigValues(cfg, func(name string, err error, detail string) {
+			if err != nil {
+				failed++
+				fmt.Printf("✗ %-24s %v\n", name, err)
+				return
+			}
+			fmt.Printf("✓ %-24s %s\n", name, detail)
+		})
+
+		if failed > 0 {
+			return fmt.Errorf("%d configuration checks failed", failed)
+		}
+		return nil
+	},
+}
+
+// checkConfigValues runs the checks of "config validate" on cfg, calling
+// check with each check's name, its error (nil if it passed), and a
+// detail to show when it passed.
+func checkConfigValues(cfg *config.Config, check func(name string, err error, detail string)) {
+	check("github.repo", github.ValidateRepo(cfg.GitHub.Repo), cfg.GitHub.Repo)
+
+	var hostErr error
+	if h := strings.TrimSpace(cfg.GitHub.Host); h != "" && (strings.Contains(h, "://") || strings.ContainsAny(h, "/ ")) {
+		hostErr = fmt.Errorf("invalid host %q; expected a bare host name", cfg.GitHub.Host)
+	}
+	check("github.host", hostErr, cfg.GitHub.Host)
+
+	var authErr error
+	if !validAuthMethods[cfg.GitHub.Auth] {
+		authErr = fmt.Errorf("invalid auth method %q; must be one of: gh, env, ssh, token", cfg.GitHub.Auth)
+	}
+	check("github.auth", authErr, cfg.GitHub.Auth)
+	if cfg.GitHub.Auth == "token" {
+		check("token file", github.CheckStoredToken(), github.TokenPath()+" (0600)")
+	}
+
+	var strategyErr error
+	if !validStrategies[copier.Strategy(cfg.Defaults.MergeStrategy)] {
+		strategyErr = fmt.Errorf("invalid merge strategy %q; must be one of: prompt, overwrite, merge, skip", cfg.Defaults.MergeStrategy)
+	}
+	check("defaults.merge_strategy", strategyErr, cfg.Defaults.MergeStrategy)
+
+	var channelErr error
+	if c := cfg.Defaults.UpdateChannel; c != "" && !validUpdateChannels[c] {
+		channelErr = fmt.Errorf("invalid update channel %q; must be one of: stable, prerelease", c)
+	}
+	check("defaults.update_channel", channelErr, cfg.Defaults.UpdateChannel)
+
+	check("defaults.content_dirs", config.ValidateContentDirs(cfg.Defaults.ContentDirs), strings.Join(cfg.Defaults.ContentDirs, ", "))
+
+	storeDir := config.ExpandPath(cfg.Store.Path)
+	check("store.path", checkWritableDir(storeDir), storeDir)
+
+	check("theme", cfg.Theme.Validate(), cfg.Theme.Name)
+}
+
+// checkWritableDir reports whether dir is (or can be created as) a
+// writable directory. If dir does not exist yet, its nearest existing
+// ancestor must be writable. Nothing is left behind on disk.
+func checkWritableDir(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("path is empty")
+	}
+
+	probe := dir
+	for {
+		info, err := os.Stat(probe)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", probe)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(probe)
+		if parent == probe {
+			return fmt.Errorf("no existing parent directory for %s", dir)
+		}
+		probe = parent
+	}
+
+	f, err := os.CreateTemp(probe, ".ocmgr-write-test-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", probe, err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
 func init() {
 	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configValidateCmd)
 }
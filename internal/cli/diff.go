@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/acchapm1/ocmgr/internal/copier"
+	"github.com/acchapm1/ocmgr/internal/resolver"
+	"github.com/acchapm1/ocmgr/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <profile> [target-dir]",
+	Short: "Preview the changes init would make",
+	Long: `Show a unified diff between an existing .opencode directory and the
+files a profile would copy into it. Nothing is written.
+
+The profile's extends chain is resolved exactly as "ocmgr init" does, so
+the diff reflects the final content after all parents are layered.
+Only files that differ are shown:
+
+  new <path>        the file does not exist yet and would be added
+  modified <path>   followed by a unified diff (existing → profile)
+
+Files that exist in .opencode but not in the profile are listed as
+"untracked"; init leaves them in place.
+
+--only and --exclude accept the same values as for init.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringP("only", "o", "", "content dirs or path globs to include (comma-separated, e.g. agents,skills/python-*)")
+	diffCmd.Flags().StringP("exclude", "e", "", "content dirs or path globs to exclude (comma-separated, e.g. plugins,agents/experimental-*)")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	onlyRaw, _ := cmd.Flags().GetString("only")
+	excludeRaw, _ := cmd.Flags().GetString("exclude")
+
+	if onlyRaw != "" && excludeRaw != "" {
+		return fmt.Errorf("--only and --exclude are mutually exclusive")
+	}
+	includeDirs, err := parseContentDirs(onlyRaw)
+	if err != nil {
+		return fmt.Errorf("--only: %w", err)
+	}
+	excludeDirs, err := parseContentDirs(excludeRaw)
+	if err != nil {
+		return fmt.Errorf("--exclude: %w", err)
+	}
+
+	targetDir := "."
+	if len(args) == 2 {
+		targetDir = args[1]
+	}
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("cannot resolve target directory: %w", err)
+	}
+	targetOpencode := filepath.Join(absTarget, ".opencode")
+
+	s, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("cannot open store: %w", err)
+	}
+
+	resolved, err := resolver.Resolve([]string{args[0]}, storeLoader(s))
+	if err != nil {
+		return fmt.Errorf("resolving profile dependencies: %w", err)
+	}
+
+	opts := copier.Options{
+		Strategy:    copier.StrategyOverwrite,
+		DryRun:      true,
+		IncludeDirs: includeDirs,
+		ExcludeDirs: excludeDirs,
+	}
+
+	// Later profiles in the chain override earlier ones, so remember the
+	// last source for every destination path.
+	sources := make(map[string]string)
+	var order []string
+	for _, name := range resolved {
+		p, err := s.Get(name)
+		if err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		result, err := copier.CopyProfile(p.Path, targetOpencode, opts)
+		if err != nil {
+			return fmt.Errorf("reading profile %q: %w", name, err)
+		}
+		for _, rel := range result.Files() {
+			if _, ok := sources[rel]; !ok {
+				order = append(order, rel)
+			}
+			sources[rel] = filepath.Join(p.Path, rel)
+		}
+	}
+	sort.Strings(order)
+
+	changes := 0
+	for _, rel := range order {
+		src := sources[rel]
+		dst := filepath.Join(targetOpencode, rel)
+
+		if _, err := os.Stat(dst); os.IsNotExist(err) {
+			fmt.Printf("new %s\n", rel)
+			changes++
+			continue
+		}
+
+		equal, err := copier.FilesEqual(src, dst)
+		if err != nil {
+			return fmt.Errorf("comparing %s: %w", rel, err)
+		}
+		if equal {
+			continue
+		}
+
+		fmt.Printf("modified %s\n", rel)
+		changes++
+		if err := unifiedDiff("a/"+rel, "b/"+rel, dst, src); err != nil {
+			fmt.Fprintf(os.Stderr, "  (diff command failed: %v)\n", err)
+		}
+	}
+
+	untracked, err := untrackedFiles(targetOpencode, sources, opts)
+	if err != nil {
+		return err
+	}
+	for _, rel := range untracked {
+		fmt.Printf("untracked %s\n", rel)
+	}
+
+	if changes == 0 {
+		fmt.Printf("No changes: %s is up to date with %s\n", targetOpencode, strings.Join(resolved, " → "))
+	}
+	return nil
+}
+
+// unifiedDiff prints a unified diff from the file from to the file to,
+// labelling the two sides fromLabel and toLabel.
+func unifiedDiff(fromLabel, toLabel, from, to string) error {
+	diff := exec.Command("diff", "-u", "--label", fromLabel, "--label", toLabel, from, to)
+	diff.Stdout = os.Stdout
+	diff.Stderr = os.Stderr
+	err := diff.Run()
+
+	// diff exits with status 1 when the files differ.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil
+	}
+	return err
+}
+
+// untrackedFiles lists files under the content directories of
+// targetOpencode that no profile would write, filtered by opts.
+func untrackedFiles(targetOpencode string, sources map[string]string, opts copier.Options) ([]string, error) {
+	var untracked []string
+	err := filepath.WalkDir(targetOpencode, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(targetOpencode, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := sources[rel]; !ok && opts.Includes(rel) {
+			untracked = append(untracked, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", targetOpencode, err)
+	}
+	return untracked, nil
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/acchapm1/ocmgr/internal/config"
+	"github.com/acchapm1/ocmgr/internal/github"
+	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common problems with the environment",
+	Long: `Check that everything ocmgr relies on is in place:
+
+  - git is installed (required for sync and GitHub imports)
+  - gh is installed when github.auth is "gh"
+  - bun is installed when any profile in the store ships plugins
+  - the config directory and the store path are writable
+  - the configuration is valid (see "ocmgr config validate")
+  - the configured git host is reachable over the network
+
+Each check is printed as pass (✓), warning (!) or failure (✗), with a
+hint on how to fix it. The command exits non-zero if any check fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d := &doctor{}
+
+		d.binary("git", true, "install git from https://git-scm.com/downloads")
+
+		cfg, err := config.Load()
+		if err != nil {
+			d.fail("config.toml parses", err, "fix the syntax error in "+config.ConfigPath()+" or run: ocmgr config init")
+			cfg = config.DefaultConfig()
+		} else {
+			d.pass("config.toml parses", config.ConfigPath())
+		}
+
+		if cfg.GitHub.Auth == "gh" {
+			d.binary("gh", true, "install the GitHub CLI (https://cli.github.com) and run: gh auth login")
+		}
+		if name := profileWithPlugins(); name != "" {
+			d.binary("bun", false, fmt.Sprintf("profile %q has plugins, which need bun to install dependencies; see https://bun.sh", name))
+		}
+
+		dirErr := checkWritableDir(config.ConfigDir())
+		d.check("config dir writable", dirErr, config.ConfigDir(), "check the ownership and permissions of "+config.ConfigDir())
+
+		checkConfigValues(cfg, func(name string, err error, detail string) {
+			d.check(name, err, detail, configHint(name))
+		})
+
+		d.reachable(cfg)
+
+		fmt.Println()
+		if d.failed > 0 {
+			return fmt.Errorf("%d check(s) failed, %d warning(s)", d.failed, d.warned)
+		}
+		if d.warned > 0 {
+			fmt.Printf("All required checks passed, %d warning(s).\n", d.warned)
+			return nil
+		}
+		fmt.Println("All checks passed.")
+		return nil
+	},
+}
+
+// doctor prints the results of the doctor checks and counts problems.
+type doctor struct {
+	failed int
+	warned int
+}
+
+func (d *doctor) pass(name, detail string) {
+	fmt.Printf("✓ %-24s %s\n", name, detail)
+}
+
+func (d *doctor) warn(name string, err error, hint string) {
+	d.warned++
+	fmt.Printf("! %-24s %v\n", name, err)
+	fmt.Printf("  %-24s → %s\n", "", hint)
+}
+
+func (d *doctor) fail(name string, err error, hint string) {
+	d.failed++
+	fmt.Printf("✗ %-24s %v\n", name, err)
+	fmt.Printf("  %-24s → %s\n", "", hint)
+}
+
+// check reports a required check: it passes when err is nil.
+func (d *doctor) check(name string, err error, detail, hint string) {
+	if err != nil {
+		d.fail(name, err, hint)
+		return
+	}
+	d.pass(name, detail)
+}
+
+// binary checks that the named program is in PATH. A missing program
+// is a failure if required and a warning otherwise.
+func (d *doctor) binary(name string, required bool, hint string) {
+	path, err := exec.LookPath(name)
+	switch {
+	case err == nil:
+		d.pass(name, path)
+	case required:
+		d.fail(name, fmt.Errorf("not found in PATH"), hint)
+	default:
+		d.warn(name, fmt.Errorf("not found in PATH"), hint)
+	}
+}
+
+// reachable checks that a TCP connection can be opened to the configured
+// git host, on the SSH port when github.auth is "ssh" and HTTPS
+// otherwise. Being offline only matters for sync and imports, so it is
+// reported as a warning.
+func (d *doctor) reachable(cfg *config.Config) {
+	host := strings.TrimSpace(cfg.GitHub.Host)
+	if host == "" {
+		host = "github.com"
+	}
+	port := "443"
+	if cfg.GitHub.Auth == "ssh" {
+		port = "22"
+	}
+	addr := net.JoinHostPort(host, port)
+
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		d.warn("network", fmt.Errorf("cannot reach %s: %v", addr, err),
+			"check your internet connection, proxy, or firewall; sync and imports need access to "+host)
+		return
+	}
+	conn.Close()
+	d.pass("network", addr+" reachable")
+}
+
+// profileWithPlugins returns the name of the first profile in the store
+// that contains plugins, or "" if there is none.
+func profileWithPlugins() string {
+	s, err := store.NewStore()
+	if err != nil {
+		return ""
+	}
+	profiles, err := s.List()
+	if err != nil {
+		return ""
+	}
+	for _, p := range profiles {
+		c, err := profile.ListContents(p)
+		if err == nil && (len(c.Plugins) > 0 || c.HasPackageJSON) {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// configHint returns the remediation hint for a failed config check.
+func configHint(name string) string {
+	switch name {
+	case "token file":
+		return "save a personal access token in " + github.TokenPath() + " (chmod 600), or switch auth: ocmgr config set github.auth gh"
+	case "store.path":
+		return "fix the directory's permissions or run: ocmgr config set store.path <dir>"
+	}
+	return "run: ocmgr config set " + name + " <value>"
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -3,17 +3,25 @@ package cli
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/acchapm1/ocmgr/internal/config"
 	"github.com/acchapm1/ocmgr/internal/configgen"
 	"github.com/acchapm1/ocmgr/internal/copier"
 	"github.com/acchapm1/ocmgr/internal/mcps"
 	"github.com/acchapm1/ocmgr/internal/plugins"
+	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/provenance"
 	"github.com/acchapm1/ocmgr/internal/resolver"
 	"github.com/acchapm1/ocmgr/internal/store"
 	"github.com/spf13/cobra"
@@ -28,34 +36,163 @@ specified, the current working directory is used.
 
 Multiple profiles can be layered by passing --profile more than once;
 they are applied in order so later profiles override earlier ones.
+A combination used often can be saved as a profile set in config.toml
+and applied with --profile-set:
+
+  [profile_sets]
+  backend = ["base", "go", "company-standards"]
+
+The set's profiles come first, followed by any --profile profiles.
 
 If a profile has an "extends" field in its profile.toml, the parent
-profile is automatically included before the child. Circular
+profile is automatically included before the child. The field may be a
+single name or an array (extends = ["go", "typescript"]); multiple
+parents are applied depth-first in declaration order. Circular
 dependencies are detected and reported as errors.
 
 Use --only or --exclude to limit which content directories are copied
-(agents, commands, skills, plugins).`,
+(agents, commands, skills, plugins, or any others listed in the
+defaults.content_dirs setting). Entries may also be path globs
+matched against the path inside the profile, e.g.
+--only 'skills/python-*' or --exclude 'agents/experimental-*'; "**"
+matches any number of directories. A file is included when it matches
+any --only entry, whether a bare directory or a glob.
+
+Use --var key=value (repeatable) to fill in placeholders: every
+"{{key}}" in copied .md, .json, .toml, and .txt files is replaced by the
+value. Other files, and files that look binary, are copied unchanged.
+
+Files matched by a .ocmgrignore file at the root of a profile are never
+copied. It takes gitignore-style patterns, one per line.
+
+Use --porcelain for a stable, line-oriented output format intended for
+scripts. Each processed file is printed on its own line as:
+
+  A <path>          added (did not exist before)
+  M <path>          modified (existing file overwritten)
+  U <path>          unchanged (with --dry-run: the file already matches)
+  S <path>          skipped (existing file left in place)
+  E <path> <error>  failed to copy
+
+No other text is written to stdout, and the interactive plugin, MCP,
+and dependency-install prompts are skipped.
+
+Use --json to print the same information as a single JSON array with
+one object per applied profile. Each entry in its "errors" list carries
+the file path, the failed operation, and an error kind (permission,
+not_exist, no_space, or other) so failures can be classified.
+
+If some files fail to copy (for example because of transient I/O errors
+on a network mount), init offers to retry just those files once the
+rest of the profile has been applied. Pass --retry-errors to retry them
+once automatically, which also works with --force, --merge, and the
+scripted output modes.
+
+When profiles are layered, a file provided by more than one profile is
+taken from the last profile that wrote it. Pass --warn-overrides to list
+those files on stderr once all profiles have been applied, e.g.
+
+  skills/foo/SKILL.md: base → go (go wins)
+
+Use --backup to save every file that is about to be overwritten (and
+whose contents actually change) to .opencode/.ocmgr-backups/<timestamp>/
+so it can be recovered later.
+
+Use --from-url to apply a profile straight from a GitHub tree URL,
+such as https://github.com/<owner>/<repo>/tree/<branch>/<path>, without
+importing it into the store first. The repository is cloned to a
+temporary directory that is removed afterwards. The profile is applied
+after any --profile profiles, and parents named in its "extends" field
+are taken from the local store.
+
+Use --link while developing a profile: instead of copying, every file
+is created as a symlink to its source in the store, so edits made in
+.opencode/ change the profile directly. Existing files are still
+resolved by the conflict strategy: by default you are asked about each
+one, --force replaces them with links, and --merge only links files
+that are missing. Files already linked to the same profile file are
+left alone. --link cannot be combined with --var or --from-url.
+
+Plugins and MCP servers stored in a profile (see "ocmgr snapshot
+--with-config") and those selected at the end of init are merged into
+an existing opencode.json: other settings in the file are kept, plugins
+are added without duplicates, and MCP servers that are already
+configured are left alone unless --overwrite-mcp is passed.
+
+The names and versions of the applied profiles, and the files each one
+wrote, are recorded in .opencode/.ocmgr-applied.toml so the origin of
+the configuration can be traced later and "ocmgr uninit" knows what to
+remove. Pass --write-provenance=false to skip this file.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
 
 func init() {
-	initCmd.Flags().StringSliceP("profile", "p", nil, "profile name(s) to apply (required, may be repeated)")
+	initCmd.Flags().StringSliceP("profile", "p", nil, "profile name(s) to apply (may be repeated; required unless --from-url is set)")
+	initCmd.Flags().String("profile-set", "", "apply the profiles of a set from profile_sets in config.toml, before any --profile")
+	initCmd.Flags().String("from-url", "", "apply a profile from a GitHub tree URL without importing it")
 	initCmd.Flags().BoolP("force", "f", false, "overwrite existing files without prompting")
 	initCmd.Flags().BoolP("merge", "m", false, "only copy new files, skip existing ones")
 	initCmd.Flags().BoolP("dry-run", "d", false, "preview changes without copying")
-	initCmd.Flags().StringP("only", "o", "", "content dirs to include (comma-separated: agents,commands,skills,plugins)")
-	initCmd.Flags().StringP("exclude", "e", "", "content dirs to exclude (comma-separated: agents,commands,skills,plugins)")
-	_ = initCmd.MarkFlagRequired("profile")
+	initCmd.Flags().StringP("only", "o", "", "content dirs or path globs to include (comma-separated, e.g. agents,skills/python-*)")
+	initCmd.Flags().StringP("exclude", "e", "", "content dirs or path globs to exclude (comma-separated, e.g. plugins,agents/experimental-*)")
+	initCmd.Flags().StringArray("var", nil, "substitute {{key}} with value in copied text files (key=value, may be repeated)")
+	initCmd.Flags().Bool("backup", false, "save files that would be overwritten under .opencode/.ocmgr-backups/<timestamp>/")
+	initCmd.Flags().Bool("write-provenance", true, "record applied profiles in .opencode/.ocmgr-applied.toml (use =false to disable)")
+	initCmd.Flags().Bool("warn-overrides", false, "list files provided by more than one layered profile and which profile won")
+	initCmd.Flags().Bool("retry-errors", false, "retry files that failed to copy once, without prompting")
+	initCmd.Flags().Bool("porcelain", false, "print machine-readable, one-line-per-file output")
+	initCmd.Flags().Bool("overwrite-mcp", false, "replace MCP servers already configured in opencode.json with the selected ones")
+	initCmd.Flags().Bool("link", false, "symlink files to the profile in the store instead of copying them")
+	initCmd.Flags().Bool("strict", false, "fail instead of prompting when the profile exceeds defaults.max_files or defaults.max_bytes")
+	_ = initCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+	_ = initCmd.RegisterFlagCompletionFunc("profile-set", completeProfileSets)
+}
+
+// loadedProfile is a profile resolved for init, in application order.
+type loadedProfile struct {
+	name    string
+	path    string
+	version string
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
 	profileNames, _ := cmd.Flags().GetStringSlice("profile")
+	profileSet, _ := cmd.Flags().GetString("profile-set")
+	fromURL, _ := cmd.Flags().GetString("from-url")
 	force, _ := cmd.Flags().GetBool("force")
 	merge, _ := cmd.Flags().GetBool("merge")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	onlyRaw, _ := cmd.Flags().GetString("only")
 	excludeRaw, _ := cmd.Flags().GetString("exclude")
+	strict, _ := cmd.Flags().GetBool("strict")
+	porcelain, _ := cmd.Flags().GetBool("porcelain")
+	if porcelain && jsonOutput {
+		return fmt.Errorf("--porcelain and --json are mutually exclusive")
+	}
+	scripted := porcelain || jsonOutput
+	writeProvenance, _ := cmd.Flags().GetBool("write-provenance")
+	backup, _ := cmd.Flags().GetBool("backup")
+	retryErrors, _ := cmd.Flags().GetBool("retry-errors")
+	warnOverrides, _ := cmd.Flags().GetBool("warn-overrides")
+	varsRaw, _ := cmd.Flags().GetStringArray("var")
+	overwriteMCP, _ := cmd.Flags().GetBool("overwrite-mcp")
+	link, _ := cmd.Flags().GetBool("link")
+
+	if profileSet != "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		set, err := cfg.ProfileSet(profileSet)
+		if err != nil {
+			return fmt.Errorf("--profile-set: %w", err)
+		}
+		profileNames = append(slices.Clone(set), profileNames...)
+	}
+	if len(profileNames) == 0 && fromURL == "" {
+		return fmt.Errorf("at least one --profile, --profile-set or --from-url is required")
+	}
 
 	// Validate mutually exclusive flags.
 	if force && merge {
@@ -64,6 +201,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if onlyRaw != "" && excludeRaw != "" {
 		return fmt.Errorf("--only and --exclude are mutually exclusive")
 	}
+	if link && len(varsRaw) > 0 {
+		return fmt.Errorf("--link and --var are mutually exclusive: linked files are not rendered")
+	}
+	if link && fromURL != "" {
+		return fmt.Errorf("--link cannot be used with --from-url: the clone is removed after init")
+	}
 
 	// Parse and validate --only / --exclude values.
 	includeDirs, err := parseContentDirs(onlyRaw)
@@ -74,6 +217,10 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("--exclude: %w", err)
 	}
+	vars, err := parseVars(varsRaw)
+	if err != nil {
+		return fmt.Errorf("--var: %w", err)
+	}
 
 	// Resolve target directory.
 	targetDir := "."
@@ -96,38 +243,61 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot open store: %w", err)
 	}
 
+	// Fetch a --from-url profile into a temporary clone. It is layered
+	// after the --profile profiles and resolved like a store profile,
+	// except that its own name always refers to the clone.
+	loader := storeLoader(s)
+	var urlProfile *profile.Profile
+	if fromURL != "" {
+		if !isGitHubURL(fromURL) {
+			return fmt.Errorf("--from-url: expected a GitHub tree URL such as https://github.com/<owner>/<repo>/tree/<branch>/<path>")
+		}
+		if !scripted {
+			fmt.Printf("Fetching %s …\n", fromURL)
+		}
+		p, _, cleanup, err := cloneGitHubProfile(fromURL)
+		if err != nil {
+			return fmt.Errorf("--from-url: %w", err)
+		}
+		defer cleanup()
+
+		urlProfile = p
+		profileNames = append(profileNames, p.Name)
+		storeLoad := loader
+		loader = func(name string) ([]string, error) {
+			if name == urlProfile.Name {
+				return urlProfile.Extends, nil
+			}
+			return storeLoad(name)
+		}
+	}
+
 	// Resolve the extends dependency chain for all requested profiles.
 	// This expands "go" (extends "base") into ["base", "go"] so parents
 	// are applied first.
-	resolved, err := resolver.Resolve(profileNames, func(name string) (string, error) {
-		p, err := s.Get(name)
-		if err != nil {
-			return "", err
-		}
-		return p.Extends, nil
-	})
+	resolved, err := resolver.Resolve(profileNames, loader)
 	if err != nil {
 		return fmt.Errorf("resolving profile dependencies: %w", err)
 	}
 
 	// If the resolved list differs from what the user requested, show
 	// the full chain so the user knows what will be applied.
-	if len(resolved) != len(profileNames) || !slicesEqual(resolved, profileNames) {
+	if !scripted && (len(resolved) != len(profileNames) || !slicesEqual(resolved, profileNames)) {
 		fmt.Printf("Resolved dependency chain: %s\n", strings.Join(resolved, " → "))
 	}
 
 	// Load every resolved profile up-front so we fail fast.
-	type loadedProfile struct {
-		name string
-		path string
-	}
 	profiles := make([]loadedProfile, 0, len(resolved))
 	for _, name := range resolved {
+		if urlProfile != nil && name == urlProfile.Name {
+			profiles = append(profiles, loadedProfile{name: name, path: urlProfile.Path, version: urlProfile.Version})
+			continue
+		}
 		p, err := s.Get(name)
 		if err != nil {
 			return fmt.Errorf("profile %q: %w", name, err)
 		}
-		profiles = append(profiles, loadedProfile{name: name, path: p.Path})
+		profiles = append(profiles, loadedProfile{name: name, path: p.Path, version: p.Version})
 	}
 
 	// Determine copy strategy.
@@ -147,37 +317,39 @@ func runInit(cmd *cobra.Command, args []string) error {
 		DryRun:      dryRun,
 		IncludeDirs: includeDirs,
 		ExcludeDirs: excludeDirs,
-		OnConflict: func(src, dst string) (copier.ConflictChoice, error) {
-			relPath, _ := filepath.Rel(targetOpencode, dst)
-			fmt.Fprintf(os.Stderr, "Conflict: %s\n", relPath)
-			fmt.Fprintf(os.Stderr, "  [o]verwrite  [s]kip  [c]ompare  [a]bort\n")
-			for {
-				fmt.Fprintf(os.Stderr, "Choice: ")
-				input, _ := reader.ReadString('\n')
-				switch strings.TrimSpace(strings.ToLower(input)) {
-				case "o":
-					return copier.ChoiceOverwrite, nil
-				case "s":
-					return copier.ChoiceSkip, nil
-				case "c":
-					diff := exec.Command("diff", "--color=always", src, dst)
-					diff.Stdout = os.Stdout
-					diff.Stderr = os.Stderr
-					if err := diff.Run(); err != nil {
-						// diff returns exit code 1 when files differ — that's expected.
-						// Only warn if the command itself failed to run.
-						if diff.ProcessState == nil || !diff.ProcessState.Exited() {
-							fmt.Fprintf(os.Stderr, "  (diff command failed: %v)\n", err)
-						}
-					}
-					return copier.ChoiceCompare, nil
-				case "a":
-					return copier.ChoiceCancel, nil
-				default:
-					continue
-				}
-			}
-		},
+		OnConflict:  promptConflict(reader, targetOpencode),
+		Vars:        vars,
+	}
+	if link {
+		opts.Mode = copier.ModeLink
+	}
+	if backup {
+		opts.BackupDir = filepath.Join(targetOpencode, ".ocmgr-backups", time.Now().Format("20060102-150405"))
+	}
+	if !dryRun && !scripted {
+		counter := &fileCounter{}
+		opts.Progress = counter.update
+		onConflict := opts.OnConflict
+		opts.OnConflict = func(src, dst string) (copier.ConflictChoice, error) {
+			counter.breakLine()
+			return onConflict(src, dst)
+		}
+	}
+
+	// Pre-flight: make sure the profiles are not unexpectedly large
+	// before anything is written.
+	if !dryRun {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		paths := make([]string, len(profiles))
+		for i, lp := range profiles {
+			paths[i] = lp.path
+		}
+		if err := checkApplyLimits(paths, targetOpencode, opts, cfg.Defaults, strict, reader); err != nil {
+			return err
+		}
 	}
 
 	prefix := ""
@@ -185,40 +357,71 @@ func runInit(cmd *cobra.Command, args []string) error {
 		prefix = "[dry run] "
 	}
 
-	// Apply each profile in order.
+	// Apply each profile in order, remembering which files failed so
+	// they can be retried once everything else has been applied.
+	var jsonResults []initJSONResult
+	var failed []failedCopy
+	written := make(map[string]map[string]string)
+	layers := newLayerTracker()
 	for _, lp := range profiles {
-		fmt.Printf("%sApplying profile %q …\n", prefix, lp.name)
+		if !scripted {
+			fmt.Printf("%sApplying profile %q …\n", prefix, lp.name)
+		}
 
 		result, err := copier.CopyProfile(lp.path, targetOpencode, opts)
 		if err != nil {
 			return fmt.Errorf("copying profile %q: %w", lp.name, err)
 		}
+		failed = trackFailures(failed, lp.path, result)
+		layers.add(lp.name, result)
+		if !dryRun {
+			written[lp.name] = writtenHashes(targetOpencode, result.Copied)
+		}
 
-		// Summary: copied files.
-		if len(result.Copied) > 0 {
-			fmt.Printf("%s✓ Copied %d files\n", prefix, len(result.Copied))
-			for _, f := range result.Copied {
-				fmt.Printf("    %s\n", f)
-			}
+		switch {
+		case jsonOutput:
+			jsonResults = append(jsonResults, initJSONResult{Profile: lp.name, Result: result})
+		case porcelain:
+			printPorcelain(os.Stdout, result)
+		default:
+			printCopyResult(prefix, result)
 		}
+	}
 
-		// Summary: skipped files.
-		if len(result.Skipped) > 0 {
-			fmt.Printf("%s→ Skipped %d files\n", prefix, len(result.Skipped))
-			for _, f := range result.Skipped {
-				fmt.Printf("    %s\n", f)
-			}
+	if warnOverrides {
+		layers.report(prefix)
+	}
+
+	// Offer to retry files that failed with (possibly transient) write
+	// errors. --retry-errors retries once without asking.
+	if !dryRun && len(failed) > 0 && (retryErrors || (strategy == copier.StrategyPrompt && !scripted)) {
+		recovered := retryFailedCopies(failed, targetOpencode, opts, retryErrors, reader)
+		addRecovered(written, profiles, targetOpencode, recovered)
+	}
+
+	// Record which profiles produced this .opencode/ directory.
+	if writeProvenance && !dryRun {
+		if err := recordProvenance(targetOpencode, profiles, written, vars); err != nil {
+			return err
 		}
+	}
 
-		// Summary: errors.
-		if len(result.Errors) > 0 {
-			fmt.Printf("%s✗ %d errors\n", prefix, len(result.Errors))
-			for _, e := range result.Errors {
-				fmt.Printf("    %s\n", e)
-			}
+	// Merge plugins and MCP servers captured by "snapshot --with-config".
+	for _, lp := range profiles {
+		if err := applyProfileConfig(lp.name, lp.path, targetOpencode, dryRun, overwriteMCP, !scripted); err != nil {
+			return err
 		}
 	}
 
+	// Porcelain and JSON output are meant for scripts; skip every
+	// interactive step.
+	if jsonOutput {
+		return printJSON(jsonResults)
+	}
+	if porcelain {
+		return nil
+	}
+
 	// Check for plugin dependencies.
 	if copier.DetectPluginDeps(targetOpencode) {
 		fmt.Fprintf(os.Stderr, "Plugin dependencies detected. Install now? [y/N] ")
@@ -243,7 +446,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	// Prompt for plugins and MCPs (skip in dry-run mode).
 	if !dryRun {
-		if err := promptForPluginsAndMCPs(targetOpencode, reader); err != nil {
+		if err := promptForPluginsAndMCPs(targetOpencode, reader, overwriteMCP); err != nil {
 			return fmt.Errorf("plugin/MCP selection: %w", err)
 		}
 	} else {
@@ -253,9 +456,340 @@ func runInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// fileCounter prints a running "done/total files" counter on stderr
+// while a profile is copied, rewriting the same line for each file.
+type fileCounter struct {
+	mu     sync.Mutex
+	onLine bool // the cursor is at the end of a counter line
+}
+
+// update is used as copier.Options.Progress.
+func (c *fileCounter) update(done, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(os.Stderr, "\r  %d/%d files", done, total)
+	c.onLine = done < total
+	if !c.onLine {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// breakLine ends an unfinished counter line so other output, such as a
+// conflict prompt, starts on a fresh line.
+func (c *fileCounter) breakLine() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.onLine {
+		fmt.Fprintln(os.Stderr)
+		c.onLine = false
+	}
+}
+
+// promptConflict returns an OnConflict callback that asks the user on
+// stderr how to resolve each conflicting file. Paths are shown relative
+// to targetOpencode.
+func promptConflict(reader *bufio.Reader, targetOpencode string) func(src, dst string) (copier.ConflictChoice, error) {
+	return func(src, dst string) (copier.ConflictChoice, error) {
+		relPath, _ := filepath.Rel(targetOpencode, dst)
+		fmt.Fprintf(os.Stderr, "Conflict: %s\n", relPath)
+		fmt.Fprintf(os.Stderr, "  [o]verwrite  [s]kip  [c]ompare  [a]bort\n")
+		for {
+			fmt.Fprintf(os.Stderr, "Choice: ")
+			input, _ := reader.ReadString('\n')
+			switch strings.TrimSpace(strings.ToLower(input)) {
+			case "o":
+				return copier.ChoiceOverwrite, nil
+			case "s":
+				return copier.ChoiceSkip, nil
+			case "c":
+				args := []string{src, dst}
+				if colorEnabled(os.Stdout) {
+					args = append([]string{"--color=always"}, args...)
+				}
+				diff := exec.Command("diff", args...)
+				diff.Stdout = os.Stdout
+				diff.Stderr = os.Stderr
+				if err := diff.Run(); err != nil {
+					// diff returns exit code 1 when files differ — that's expected.
+					// Only warn if the command itself failed to run.
+					if diff.ProcessState == nil || !diff.ProcessState.Exited() {
+						fmt.Fprintf(os.Stderr, "  (diff command failed: %v)\n", err)
+					}
+				}
+				return copier.ChoiceCompare, nil
+			case "a":
+				return copier.ChoiceCancel, nil
+			default:
+				continue
+			}
+		}
+	}
+}
+
+// printCopyResult prints the copied, skipped, and error summary for a
+// single CopyProfile invocation.
+func printCopyResult(prefix string, result *copier.Result) {
+	// Summary: copied files.
+	if len(result.Copied) > 0 {
+		breakdown := ""
+		if n := len(result.Overwritten); n > 0 {
+			breakdown = fmt.Sprintf(" (%d new, %d changed)", len(result.Copied)-n, n)
+		}
+		fmt.Printf("%s✓ Copied %d files%s\n", prefix, len(result.Copied), breakdown)
+		for _, f := range result.Copied {
+			fmt.Printf("    %s\n", f)
+		}
+	}
+
+	// Summary: files a dry run found already up to date.
+	if len(result.Unchanged) > 0 {
+		fmt.Printf("%s= %d files unchanged\n", prefix, len(result.Unchanged))
+	}
+
+	// Summary: skipped files.
+	if len(result.Skipped) > 0 {
+		fmt.Printf("%s→ Skipped %d files\n", prefix, len(result.Skipped))
+		for _, f := range result.Skipped {
+			fmt.Printf("    %s\n", f)
+		}
+	}
+
+	// Summary: backups.
+	if len(result.BackedUp) > 0 {
+		fmt.Printf("%s↺ Backed up %d overwritten files to %s\n", prefix, len(result.BackedUp), result.BackupDir)
+	}
+
+	// Summary: notes.
+	if len(result.Notes) > 0 {
+		fmt.Printf("%sℹ %d notes\n", prefix, len(result.Notes))
+		for _, n := range result.Notes {
+			fmt.Printf("    %s\n", n)
+		}
+	}
+
+	// Summary: errors.
+	if len(result.Errors) > 0 {
+		fmt.Printf("%s✗ %d errors\n", prefix, len(result.Errors))
+		for _, e := range result.Errors {
+			fmt.Printf("    %s\n", e)
+		}
+	}
+}
+
+// layerTracker records, across the CopyProfile calls of a layered init,
+// which profiles provided each file and which one's copy ended up in the
+// target.
+type layerTracker struct {
+	providers map[string][]string
+	winner    map[string]string
+}
+
+func newLayerTracker() *layerTracker {
+	return &layerTracker{
+		providers: make(map[string][]string),
+		winner:    make(map[string]string),
+	}
+}
+
+// add records the files profile name wrote, skipped, or (in a dry run)
+// found unchanged.
+func (t *layerTracker) add(name string, result *copier.Result) {
+	for _, rel := range result.Copied {
+		t.providers[rel] = append(t.providers[rel], name)
+		t.winner[rel] = name
+	}
+	for _, rel := range result.Unchanged {
+		t.providers[rel] = append(t.providers[rel], name)
+		t.winner[rel] = name
+	}
+	for _, rel := range result.Skipped {
+		t.providers[rel] = append(t.providers[rel], name)
+	}
+}
+
+// report prints every file provided by more than one profile to stderr,
+// in path order.
+func (t *layerTracker) report(prefix string) {
+	var paths []string
+	for rel, names := range t.providers {
+		if len(names) > 1 {
+			paths = append(paths, rel)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(os.Stderr, "%s⚠ %d files provided by more than one profile\n", prefix, len(paths))
+	for _, rel := range paths {
+		outcome := "existing file kept"
+		if w := t.winner[rel]; w != "" {
+			outcome = w + " wins"
+		}
+		fmt.Fprintf(os.Stderr, "    %s: %s (%s)\n", rel, strings.Join(t.providers[rel], " → "), outcome)
+	}
+}
+
+// failedCopy is a file that could not be written, together with the
+// profile it came from.
+type failedCopy struct {
+	profilePath string
+	err         copier.CopyError
+}
+
+// trackFailures updates the list of failed copies after a profile has been
+// applied: files the profile has now written successfully are dropped (a
+// later profile supersedes an earlier failure), and its own retryable
+// failures are added.
+func trackFailures(failed []failedCopy, profilePath string, result *copier.Result) []failedCopy {
+	written := make(map[string]bool, len(result.Copied))
+	for _, rel := range result.Copied {
+		written[rel] = true
+	}
+
+	kept := failed[:0]
+	for _, f := range failed {
+		if !written[f.err.Path] {
+			kept = append(kept, f)
+		}
+	}
+	for _, e := range result.Errors {
+		if e.Retryable() {
+			kept = append(kept, failedCopy{profilePath: profilePath, err: e})
+		}
+	}
+	return kept
+}
+
+// writtenHashes maps each of the paths, relative to targetOpencode, to
+// the hash of its content for the provenance file. Files that cannot be
+// read get an empty hash.
+func writtenHashes(targetOpencode string, paths []string) map[string]string {
+	hashes := make(map[string]string, len(paths))
+	for _, rel := range paths {
+		hashes[rel], _ = copier.FileHash(filepath.Join(targetOpencode, rel))
+	}
+	return hashes
+}
+
+// retryFailedCopies re-attempts the failed copies. Unless auto is set the
+// user is asked first, and asked again after each round that leaves files
+// failing. Progress is reported on stderr so scripted output on stdout is
+// not disturbed. It returns the paths that were written on retry, keyed
+// by the directory of the profile they came from.
+func retryFailedCopies(failed []failedCopy, targetOpencode string, opts copier.Options, auto bool, reader *bufio.Reader) map[string][]string {
+	recovered := make(map[string][]string)
+	for len(failed) > 0 {
+		if !auto {
+			fmt.Fprintf(os.Stderr, "\n%d files failed to copy. Retry them? [y/N] ", len(failed))
+			answer, _ := reader.ReadString('\n')
+			answer = strings.TrimSpace(strings.ToLower(answer))
+			if answer != "y" && answer != "yes" {
+				return recovered
+			}
+		}
+
+		// Retry each file against the profile it came from.
+		byProfile := make(map[string]copier.CopyErrors)
+		var order []string
+		for _, f := range failed {
+			if _, ok := byProfile[f.profilePath]; !ok {
+				order = append(order, f.profilePath)
+			}
+			byProfile[f.profilePath] = append(byProfile[f.profilePath], f.err)
+		}
+
+		attempted := len(failed)
+		var still []failedCopy
+		for _, path := range order {
+			result := copier.Retry(path, targetOpencode, byProfile[path], opts)
+			recovered[path] = append(recovered[path], result.Copied...)
+			for _, e := range result.Errors {
+				still = append(still, failedCopy{profilePath: path, err: e})
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "✓ %d of %d files succeeded on retry\n", attempted-len(still), attempted)
+		for _, f := range still {
+			fmt.Fprintf(os.Stderr, "    ✗ %s\n", f.err)
+		}
+
+		failed = still
+		if auto {
+			break
+		}
+	}
+	return recovered
+}
+
+// addRecovered adds the files that retryFailedCopies wrote to written,
+// the per-profile file hashes recorded in the provenance file.
+func addRecovered(written map[string]map[string]string, profiles []loadedProfile, targetOpencode string, recovered map[string][]string) {
+	for _, lp := range profiles {
+		paths := recovered[lp.path]
+		if len(paths) == 0 {
+			continue
+		}
+		if written[lp.name] == nil {
+			written[lp.name] = make(map[string]string, len(paths))
+		}
+		maps.Copy(written[lp.name], writtenHashes(targetOpencode, paths))
+	}
+}
+
+// recordProvenance records in targetOpencode's provenance file that
+// profiles were applied, with the files each of them wrote.
+func recordProvenance(targetOpencode string, profiles []loadedProfile, written map[string]map[string]string, vars map[string]string) error {
+	prov, err := provenance.Load(targetOpencode)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, lp := range profiles {
+		prov.Record(lp.name, lp.version, now, written[lp.name])
+		prov.Find(lp.name).Vars = vars
+	}
+	return prov.Save(targetOpencode)
+}
+
+// initJSONResult is the per-profile object printed by "init --json".
+type initJSONResult struct {
+	Profile string `json:"profile"`
+	*copier.Result
+}
+
+// printPorcelain writes one line per file to w in the stable --porcelain
+// format: "A <path>", "M <path>", "U <path>", "S <path>", or
+// "E <path> <error>".
+// Lines are grouped by kind: the A and M lines come first, in the order
+// the files were copied, followed by the U, S and E lines.
+func printPorcelain(w io.Writer, result *copier.Result) {
+	overwritten := make(map[string]bool, len(result.Overwritten))
+	for _, f := range result.Overwritten {
+		overwritten[f] = true
+	}
+	for _, f := range result.Copied {
+		if overwritten[f] {
+			fmt.Fprintf(w, "M %s\n", f)
+		} else {
+			fmt.Fprintf(w, "A %s\n", f)
+		}
+	}
+	for _, f := range result.Unchanged {
+		fmt.Fprintf(w, "U %s\n", f)
+	}
+	for _, f := range result.Skipped {
+		fmt.Fprintf(w, "S %s\n", f)
+	}
+	for _, e := range result.Errors {
+		fmt.Fprintf(w, "E %s %v\n", e.Path, e.Err)
+	}
+}
+
 // parseContentDirs splits a comma-separated string of content directory
-// names, validates each one, and returns the list. An empty input returns
-// nil.
+// names and path globs, validates each one, and returns the list. An
+// empty input returns nil.
 func parseContentDirs(raw string) ([]string, error) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -268,14 +802,102 @@ func parseContentDirs(raw string) ([]string, error) {
 		if d == "" {
 			continue
 		}
-		if !copier.ValidContentDirs[d] {
-			return nil, fmt.Errorf("invalid content directory %q; must be one of: agents, commands, skills, plugins", d)
+		if copier.IsPattern(d) {
+			if err := copier.ValidatePattern(filepath.ToSlash(d)); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", d, err)
+			}
+		} else if !copier.ValidContentDirs[d] {
+			return nil, fmt.Errorf("invalid content directory %q; must be one of: %s, or a path glob", d, strings.Join(profile.ContentDirs(), ", "))
 		}
 		dirs = append(dirs, d)
 	}
 	return dirs, nil
 }
 
+// parseVars turns key=value pairs into a map. Each entry must contain an
+// "=" and a non-empty key; later entries override earlier ones.
+func parseVars(raw []string) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	vars := make(map[string]string, len(raw))
+	for _, kv := range raw {
+		key, value, ok := strings.Cut(kv, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, fmt.Errorf("%q must be in key=value form", kv)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("%q has an empty key", kv)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// checkApplyLimits dry-runs every profile in paths and compares the total
+// file count and size against the configured limits. When a limit is
+// exceeded the user is asked to confirm, or an error is returned if
+// strict is set.
+func checkApplyLimits(paths []string, targetDir string, opts copier.Options, defaults config.Defaults, strict bool, reader *bufio.Reader) error {
+	if defaults.MaxFiles <= 0 && defaults.MaxBytes <= 0 {
+		return nil
+	}
+
+	// Count everything that would be written, regardless of conflicts;
+	// files that already match are left alone.
+	opts.Strategy = copier.StrategyOverwrite
+	opts.DryRun = true
+	opts.Progress = nil
+
+	var files int
+	var size int64
+	for _, path := range paths {
+		result, err := copier.CopyProfile(path, targetDir, opts)
+		if err != nil {
+			return fmt.Errorf("pre-flight check: %w", err)
+		}
+		files += len(result.Copied)
+		size += result.Bytes
+	}
+
+	var exceeded []string
+	if defaults.MaxFiles > 0 && files > defaults.MaxFiles {
+		exceeded = append(exceeded, fmt.Sprintf("%d files (limit %d)", files, defaults.MaxFiles))
+	}
+	if defaults.MaxBytes > 0 && size > defaults.MaxBytes {
+		exceeded = append(exceeded, fmt.Sprintf("%d bytes (limit %d)", size, defaults.MaxBytes))
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("profile contents exceed configured limits: %s", strings.Join(exceeded, ", "))
+	if strict {
+		return fmt.Errorf("%s", msg)
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	fmt.Fprintf(os.Stderr, "Continue anyway? [y/N] ")
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	if answer != "y" && answer != "yes" {
+		return fmt.Errorf("aborted: %s", msg)
+	}
+	return nil
+}
+
+// storeLoader returns a resolver.Loader backed by the given store.
+func storeLoader(s *store.Store) resolver.Loader {
+	return func(name string) ([]string, error) {
+		p, err := s.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return p.Extends, nil
+	}
+}
+
 // slicesEqual reports whether two string slices have the same elements
 // in the same order.
 func slicesEqual(a, b []string) bool {
@@ -290,8 +912,42 @@ func slicesEqual(a, b []string) bool {
 	return true
 }
 
+// applyProfileConfig merges the opencode.json stored in the profile at
+// profileDir, if any, into the one in targetOpencode. Messages are only
+// printed when report is set.
+func applyProfileConfig(name, profileDir, targetOpencode string, dryRun, overwriteMCP, report bool) error {
+	if _, err := os.Stat(filepath.Join(profileDir, "opencode.json")); err != nil {
+		return nil
+	}
+	if dryRun {
+		if report {
+			fmt.Printf("[dry run] Would merge opencode.json from profile %q\n", name)
+		}
+		return nil
+	}
+
+	result, err := configgen.Apply(targetOpencode, profileDir, overwriteMCP)
+	if err != nil {
+		return fmt.Errorf("profile %q: merging opencode.json: %w", name, err)
+	}
+	if !report || result == nil {
+		return nil
+	}
+	if result.Changed() {
+		fmt.Printf("✓ Merged opencode.json from profile %q: added %d plugin(s) and %d MCP server(s)\n",
+			name, len(result.AddedPlugins), len(result.AddedMCPs))
+	}
+	if len(result.KeptMCPs) > 0 {
+		fmt.Printf("  Kept existing MCP config for: %s (use --overwrite-mcp to replace)\n",
+			strings.Join(result.KeptMCPs, ", "))
+	}
+	return nil
+}
+
 // promptForPluginsAndMCPs prompts the user to select plugins and MCP servers.
-func promptForPluginsAndMCPs(targetDir string, reader *bufio.Reader) error {
+// Existing MCP servers in opencode.json are replaced only if
+// overwriteMCP is set.
+func promptForPluginsAndMCPs(targetDir string, reader *bufio.Reader, overwriteMCP bool) error {
 	// Load plugin registry
 	pluginRegistry, err := plugins.Load()
 	if err != nil {
@@ -334,14 +990,28 @@ func promptForPluginsAndMCPs(targetDir string, reader *bufio.Reader) error {
 	// Generate opencode.json if there's anything to write
 	if len(selectedPlugins) > 0 || len(selectedMCPs) > 0 {
 		opts := configgen.Options{
-			Plugins: selectedPlugins,
-			MCPs:    selectedMCPs,
+			Plugins:      selectedPlugins,
+			MCPs:         selectedMCPs,
+			OverwriteMCP: overwriteMCP,
 		}
-		if err := configgen.Generate(targetDir, opts); err != nil {
+		result, err := configgen.Generate(targetDir, opts)
+		if err != nil {
 			return fmt.Errorf("generating opencode.json: %w", err)
 		}
-		fmt.Printf("✓ Created opencode.json with %d plugin(s) and %d MCP server(s)\n",
-			len(selectedPlugins), len(selectedMCPs))
+		switch {
+		case result.Created:
+			fmt.Printf("✓ Created opencode.json with %d plugin(s) and %d MCP server(s)\n",
+				len(result.AddedPlugins), len(result.AddedMCPs))
+		case result.Changed():
+			fmt.Printf("✓ Updated opencode.json: added %d plugin(s) and %d MCP server(s)\n",
+				len(result.AddedPlugins), len(result.AddedMCPs))
+		default:
+			fmt.Println("✓ opencode.json already has the selected plugins and MCP servers")
+		}
+		if len(result.KeptMCPs) > 0 {
+			fmt.Printf("  Kept existing MCP config for: %s (use --overwrite-mcp to replace)\n",
+				strings.Join(result.KeptMCPs, ", "))
+		}
 	}
 
 	return nil
@@ -409,27 +1079,32 @@ func promptForMCPs(registry *mcps.Registry, reader *bufio.Reader) (map[string]co
 		return nil, nil
 	}
 
+	var defs []mcps.Definition
 	if input == "all" {
-		result := make(map[string]configgen.MCPEntry)
-		for _, m := range registry.List() {
-			result[m.Name] = mcpConfigToEntry(m.Config)
+		defs = registry.List()
+	} else {
+		selected, err := parseSelection(input, registry.List(), func(m mcps.Definition) string {
+			return m.Name
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range selected {
+			if m := registry.GetByName(name); m != nil {
+				defs = append(defs, *m)
+			}
 		}
-		return result, nil
-	}
-
-	// Parse selection
-	selected, err := parseSelection(input, registry.List(), func(m mcps.Definition) string {
-		return m.Name
-	})
-	if err != nil {
-		return nil, err
 	}
 
+	// Leave out definitions that would produce a broken opencode.json.
 	result := make(map[string]configgen.MCPEntry)
-	for _, name := range selected {
-		if m := registry.GetByName(name); m != nil {
-			result[name] = mcpConfigToEntry(m.Config)
+	for _, m := range defs {
+		entry, err := mcpConfigToEntry(m.Name, m.Config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Skipping %v\n", err)
+			continue
 		}
+		result[m.Name] = entry
 	}
 	return result, nil
 }
@@ -467,56 +1142,131 @@ func parseSelection[T any](input string, items []T, getName func(T) string) ([]s
 	return selected, nil
 }
 
-// mcpConfigToEntry converts an MCP config map to an MCPEntry struct.
-func mcpConfigToEntry(config map[string]interface{}) configgen.MCPEntry {
+// mcpConfigToEntry converts the config map of the MCP definition name
+// to an MCPEntry. "command" may be an array or a single command line,
+// which is split into words. Values of the wrong type, an unknown
+// "type", and a missing command or url are reported in the error
+// rather than dropped.
+func mcpConfigToEntry(name string, cfg map[string]interface{}) (configgen.MCPEntry, error) {
 	entry := configgen.MCPEntry{}
+	var problems []string
+	mismatch := func(key, want string, got interface{}) {
+		problems = append(problems, fmt.Sprintf("%q must be %s, got %s", key, want, jsonTypeName(got)))
+	}
 
-	if v, ok := config["type"].(string); ok {
-		entry.Type = v
+	if v, ok := cfg["type"]; ok {
+		if s, ok := v.(string); ok {
+			entry.Type = s
+		} else {
+			mismatch("type", "a string", v)
+		}
 	}
-	if v, ok := config["url"].(string); ok {
-		entry.URL = v
+	if v, ok := cfg["url"]; ok {
+		if s, ok := v.(string); ok {
+			entry.URL = s
+		} else {
+			mismatch("url", "a string", v)
+		}
 	}
-	if v, ok := config["enabled"].(bool); ok {
-		entry.Enabled = v
+	if v, ok := cfg["enabled"]; ok {
+		if b, ok := v.(bool); ok {
+			entry.Enabled = b
+		} else {
+			mismatch("enabled", "a boolean", v)
+		}
 	}
-	if v, ok := config["timeout"].(float64); ok {
-		entry.Timeout = int(v)
+	if v, ok := cfg["timeout"]; ok {
+		if f, ok := v.(float64); ok && f >= 0 && f == float64(int(f)) {
+			entry.Timeout = int(f)
+		} else {
+			mismatch("timeout", "a non-negative whole number", v)
+		}
 	}
 
-	// Handle command array
-	if cmd, ok := config["command"].([]interface{}); ok {
-		for _, c := range cmd {
+	// Handle command, given as an array or a single command line
+	switch cmd := cfg["command"].(type) {
+	case nil:
+	case string:
+		entry.Command = config.SplitCommand(cmd)
+	case []interface{}:
+		for i, c := range cmd {
 			if s, ok := c.(string); ok {
 				entry.Command = append(entry.Command, s)
+			} else {
+				mismatch(fmt.Sprintf("command[%d]", i), "a string", c)
 			}
 		}
+	default:
+		mismatch("command", "a string or an array of strings", cmd)
 	}
 
-	// Handle environment map
-	if env, ok := config["environment"].(map[string]interface{}); ok {
-		entry.Environment = make(map[string]string)
-		for k, v := range env {
-			if s, ok := v.(string); ok {
-				entry.Environment[k] = s
-			}
+	// Handle environment and headers maps
+	for _, key := range []string{"environment", "headers"} {
+		v, ok := cfg[key]
+		if !ok {
+			continue
 		}
-	}
-
-	// Handle headers map
-	if headers, ok := config["headers"].(map[string]interface{}); ok {
-		entry.Headers = make(map[string]string)
-		for k, v := range headers {
-			if s, ok := v.(string); ok {
-				entry.Headers[k] = s
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			mismatch(key, "an object", v)
+			continue
+		}
+		out := make(map[string]string, len(m))
+		for k, val := range m {
+			if s, ok := val.(string); ok {
+				out[k] = s
+			} else {
+				mismatch(key+"."+k, "a string", val)
 			}
 		}
+		if key == "environment" {
+			entry.Environment = out
+		} else {
+			entry.Headers = out
+		}
 	}
 
 	// Handle oauth
-	if oauth, ok := config["oauth"]; ok {
+	if oauth, ok := cfg["oauth"]; ok {
 		entry.OAuth = oauth
 	}
 
-	return entry
+	switch entry.Type {
+	case "local":
+		if len(entry.Command) == 0 {
+			problems = append(problems, `"command" is required for a local server`)
+		}
+	case "remote":
+		if entry.URL == "" {
+			problems = append(problems, `"url" is required for a remote server`)
+		}
+	default:
+		if _, ok := cfg["type"].(string); ok || cfg["type"] == nil {
+			problems = append(problems, fmt.Sprintf(`"type" must be "local" or "remote", got %q`, entry.Type))
+		}
+	}
+
+	if len(problems) > 0 {
+		return entry, fmt.Errorf("MCP %q: %s", name, strings.Join(problems, "; "))
+	}
+	return entry, nil
+}
+
+// jsonTypeName names the JSON type of a value decoded by encoding/json.
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
 }
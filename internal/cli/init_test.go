@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/acchapm1/ocmgr/internal/config"
+	"github.com/acchapm1/ocmgr/internal/copier"
+	"github.com/acchapm1/ocmgr/internal/provenance"
+)
+
+func TestCheckApplyLimits(t *testing.T) {
+	tmp := t.TempDir()
+	src := writeTestProfile(t, tmp, "go")
+	writeFiles(t, src, "agents/b.md", "skills/s/SKILL.md")
+	target := filepath.Join(tmp, "project", ".opencode")
+
+	for _, tc := range []struct {
+		name     string
+		defaults config.Defaults
+		strict   bool
+		answer   string
+		wantErr  string
+	}{
+		{name: "no limits", defaults: config.Defaults{}},
+		{name: "under the limits", defaults: config.Defaults{MaxFiles: 3, MaxBytes: 1 << 20}},
+		{name: "confirmed", defaults: config.Defaults{MaxFiles: 2}, answer: "y\n"},
+		{name: "declined", defaults: config.Defaults{MaxFiles: 2}, answer: "n\n", wantErr: "aborted: profile contents exceed configured limits: 3 files (limit 2)"},
+		{name: "no answer", defaults: config.Defaults{MaxBytes: 1}, wantErr: "aborted"},
+		{name: "strict", defaults: config.Defaults{MaxFiles: 2}, strict: true, answer: "y\n", wantErr: "3 files (limit 2)"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tc.answer))
+			err := checkApplyLimits([]string{src}, target, copier.Options{}, tc.defaults, tc.strict, reader)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("checkApplyLimits() = %v, want nil", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("checkApplyLimits() = %v, want an error containing %q", err, tc.wantErr)
+			}
+			if rest, _ := reader.ReadString('\n'); tc.strict && rest != tc.answer {
+				t.Error("strict mode prompted for confirmation")
+			}
+			if _, err := os.Stat(target); !os.IsNotExist(err) {
+				t.Error("the pre-flight check wrote to the target")
+			}
+		})
+	}
+}
+
+func TestPrintPorcelain(t *testing.T) {
+	result := &copier.Result{
+		Copied:      []string{"agents/new.md", "agents/old.md"},
+		Overwritten: []string{"agents/old.md"},
+		Unchanged:   []string{"agents/same.md"},
+		Skipped:     []string{"skills/kept/SKILL.md"},
+		Errors: copier.CopyErrors{
+			{Path: "commands/locked.md", Op: copier.OpCopy, Err: errors.New("permission denied")},
+		},
+	}
+
+	var b strings.Builder
+	printPorcelain(&b, result)
+	want := "A agents/new.md\n" +
+		"M agents/old.md\n" +
+		"U agents/same.md\n" +
+		"S skills/kept/SKILL.md\n" +
+		"E commands/locked.md permission denied\n"
+	if b.String() != want {
+		t.Errorf("porcelain output:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestPrintPorcelainStableAcrossRuns(t *testing.T) {
+	tmp := t.TempDir()
+	src := writeTestProfile(t, tmp, "go")
+	writeFiles(t, src, "agents/b.md", "commands/c.md")
+
+	want := "M agents/a.md\nA agents/b.md\nA commands/c.md\n"
+	for run := range 2 {
+		target := filepath.Join(tmp, fmt.Sprintf("project%d", run), ".opencode")
+		writeFiles(t, target, "agents/a.md")
+
+		result, err := copier.CopyProfile(src, target, copier.Options{Strategy: copier.StrategyOverwrite})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		printPorcelain(&b, result)
+		if b.String() != want {
+			t.Errorf("run %d porcelain output:\n%s\nwant:\n%s", run, b.String(), want)
+		}
+	}
+}
+
+func TestRetryFailedCopies(t *testing.T) {
+	tmp := t.TempDir()
+	src := writeTestProfile(t, tmp, "go")
+	writeFiles(t, src, "commands/c.md")
+	target := filepath.Join(tmp, "project", ".opencode")
+	writeFiles(t, target, "agents")
+
+	opts := copier.Options{Strategy: copier.StrategyPrompt}
+	result, err := copier.CopyProfile(src, target, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Errors = %v, want one", result.Errors)
+	}
+	profiles := []loadedProfile{{name: "go", path: src, version: "1.0.0"}}
+	written := map[string]map[string]string{"go": writtenHashes(target, result.Copied)}
+	failed := trackFailures(nil, src, result)
+
+	// Declining leaves the obstacle in place and recovers nothing.
+	recovered := retryFailedCopies(failed, target, opts, false, bufio.NewReader(strings.NewReader("n\n")))
+	if len(recovered) != 0 {
+		t.Errorf("recovered = %v after declining", recovered)
+	}
+	if info, err := os.Stat(filepath.Join(target, "agents")); err != nil || info.IsDir() {
+		t.Fatalf("agents was replaced after declining: %v", err)
+	}
+
+	// Accepting once the obstacle is gone copies the file, and it is
+	// recorded in the provenance file along with the first-pass files.
+	if err := os.Remove(filepath.Join(target, "agents")); err != nil {
+		t.Fatal(err)
+	}
+	recovered = retryFailedCopies(failed, target, opts, false, bufio.NewReader(strings.NewReader("y\n")))
+	aPath := filepath.Join("agents", "a.md")
+	if got := recovered[src]; !slices.Equal(got, []string{aPath}) {
+		t.Fatalf("recovered = %v, want %s for the profile", recovered, aPath)
+	}
+	addRecovered(written, profiles, target, recovered)
+	if err := recordProvenance(target, profiles, written, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	prov, err := provenance.Load(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := prov.Find("go")
+	if e == nil {
+		t.Fatal("no provenance entry for go")
+	}
+	if want := []string{"agents/a.md", "commands/c.md"}; !slices.Equal(e.Files, want) {
+		t.Errorf("provenance files = %v, want %v", e.Files, want)
+	}
+	if e.Hashes["agents/a.md"] == "" {
+		t.Error("the recovered file has no hash in the provenance file")
+	}
+}
+
+func TestMCPConfigToEntry(t *testing.T) {
+	decode := func(t *testing.T, s string) map[string]interface{} {
+		t.Helper()
+		var cfg map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+
+	t.Run("command forms", func(t *testing.T) {
+		for _, cfg := range []string{
+			`{"type": "local", "command": "npx -y server --flag"}`,
+			`{"type": "local", "command": ["npx", "-y", "server", "--flag"]}`,
+		} {
+			entry, err := mcpConfigToEntry("srv", decode(t, cfg))
+			if err != nil {
+				t.Fatalf("%s: %v", cfg, err)
+			}
+			if want := []string{"npx", "-y", "server", "--flag"}; !slices.Equal(entry.Command, want) {
+				t.Errorf("%s: Command = %q, want %q", cfg, entry.Command, want)
+			}
+		}
+	})
+
+	for _, tc := range []struct {
+		cfg  string
+		want string
+	}{
+		{`{"type": 1, "command": ["x"]}`, `"type" must be a string, got a number`},
+		{`{"type": "stdio", "command": ["x"]}`, `"type" must be "local" or "remote", got "stdio"`},
+		{`{"command": ["x"]}`, `"type" must be "local" or "remote", got ""`},
+		{`{"type": "remote", "url": 8080}`, `"url" must be a string, got a number`},
+		{`{"type": "local", "command": ["x"], "enabled": "yes"}`, `"enabled" must be a boolean, got a string`},
+		{`{"type": "local", "command": ["x"], "timeout": "5000"}`, `"timeout" must be a non-negative whole number, got a string`},
+		{`{"type": "local", "command": ["x"], "timeout": -1}`, `"timeout" must be a non-negative whole number, got a number`},
+		{`{"type": "local", "command": ["x"], "timeout": 1.5}`, `"timeout" must be a non-negative whole number, got a number`},
+		{`{"type": "local", "command": 42}`, `"command" must be a string or an array of strings, got a number`},
+		{`{"type": "local", "command": ["x", 1]}`, `"command[1]" must be a string, got a number`},
+		{`{"type": "local", "command": ["x"], "environment": "KEY=v"}`, `"environment" must be an object, got a string`},
+		{`{"type": "local", "command": ["x"], "environment": {"KEY": 1}}`, `"environment.KEY" must be a string, got a number`},
+		{`{"type": "remote", "url": "https://x", "headers": ["a"]}`, `"headers" must be an object, got an array`},
+		{`{"type": "local"}`, `"command" is required for a local server`},
+		{`{"type": "remote"}`, `"url" is required for a remote server`},
+	} {
+		_, err := mcpConfigToEntry("srv", decode(t, tc.cfg))
+		if err == nil || !strings.HasPrefix(err.Error(), `MCP "srv": `) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want MCP \"srv\": ...%s", tc.cfg, err, tc.want)
+		}
+	}
+}
@@ -4,13 +4,15 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"text/tabwriter"
+	"unicode/utf8"
 
+	"github.com/acchapm1/ocmgr/internal/archive"
 	"github.com/acchapm1/ocmgr/internal/github"
 	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/resolver"
 	"github.com/acchapm1/ocmgr/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -24,7 +26,18 @@ var profileCmd = &cobra.Command{
 var profileListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all profiles in the local store",
+	Long: `List all profiles in the local store.
+
+Use --tag (repeatable or comma-separated) to list only profiles carrying
+every given tag, or any of them with --match any. Tags are compared
+case-insensitively.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		match, _ := cmd.Flags().GetString("match")
+		if match != "all" && match != "any" {
+			return fmt.Errorf("invalid --match %q; must be one of: all, any", match)
+		}
+
 		s, err := store.NewStore()
 		if err != nil {
 			return fmt.Errorf("opening store: %w", err)
@@ -34,8 +47,29 @@ var profileListCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("listing profiles: %w", err)
 		}
+		if len(tags) > 0 {
+			profiles = filterByTags(profiles, tags, match == "any")
+		}
+
+		if jsonOutput {
+			out := make([]profileJSON, 0, len(profiles))
+			for _, p := range profiles {
+				out = append(out, profileJSON{
+					Name:        p.Name,
+					Version:     p.Version,
+					Description: p.Description,
+					Tags:        append([]string{}, p.Tags...),
+					Extends:     append([]string{}, p.Extends...),
+				})
+			}
+			return printJSON(out)
+		}
 
 		if len(profiles) == 0 {
+			if len(tags) > 0 {
+				fmt.Println("No profiles match the given tags.")
+				return nil
+			}
 			fmt.Println("No profiles found. Create one with: ocmgr profile create <name>")
 			return nil
 		}
@@ -56,18 +90,55 @@ var profileListCmd = &cobra.Command{
 	},
 }
 
+// profileJSON is the JSON representation of a profile printed by
+// "profile list --json".
+type profileJSON struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Extends     []string `json:"extends"`
+}
+
 var profileShowCmd = &cobra.Command{
 	Use:   "show <name>",
 	Short: "Show details of a profile",
-	Args:  cobra.ExactArgs(1),
+	Long: `Show the metadata and contents of a profile.
+
+With --extends-chain, only the resolved apply order is printed (for
+example "base → go → go-web"). This is exactly the order "ocmgr init"
+would apply the profiles in. Add --json to print it as a JSON array.
+
+With --summary, the file list is replaced by the number of files and
+their total size in each content directory, which is easier to read for
+large profiles.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
+		extendsChain, _ := cmd.Flags().GetBool("extends-chain")
+		summary, _ := cmd.Flags().GetBool("summary")
+
+		if jsonOutput && !extendsChain {
+			return fmt.Errorf("--json requires --extends-chain")
+		}
 
 		s, err := store.NewStore()
 		if err != nil {
 			return fmt.Errorf("opening store: %w", err)
 		}
 
+		if extendsChain {
+			chain, err := resolver.Resolve([]string{name}, storeLoader(s))
+			if err != nil {
+				return fmt.Errorf("resolving profile dependencies: %w", err)
+			}
+			if jsonOutput {
+				return printJSON(chain)
+			}
+			fmt.Println(strings.Join(chain, " → "))
+			return nil
+		}
+
 		p, err := s.Get(name)
 		if err != nil {
 			return err
@@ -86,9 +157,29 @@ var profileShowCmd = &cobra.Command{
 		if len(p.Tags) > 0 {
 			fmt.Printf("Tags: %s\n", strings.Join(p.Tags, ", "))
 		}
-		if p.Extends != "" {
+		if len(p.Extends) > 0 {
 			fmt.Printf("Extends: %s\n", p.Extends)
 		}
+		if p.Source != nil {
+			fmt.Printf("Source: %s (%s)\n", p.Source.URL(), shortSHA(p.Source.Commit))
+		}
+		if p.Locked {
+			fmt.Println("Locked: yes")
+		}
+
+		if summary {
+			st, err := profile.Stat(p)
+			if err != nil {
+				return err
+			}
+			fmt.Println()
+			fmt.Println("Summary:")
+			for _, d := range st.Dirs {
+				fmt.Printf("  %-10s %5d files  %10s\n", d.Dir+"/", d.Files, profile.FormatSize(d.Bytes))
+			}
+			fmt.Printf("  %-10s %5d files  %10s\n", "total", st.Files, profile.FormatSize(st.Bytes))
+			return nil
+		}
 
 		contents, err := profile.ListContents(p)
 		if err != nil {
@@ -126,6 +217,21 @@ var profileShowCmd = &cobra.Command{
 			}
 		}
 
+		for _, dir := range profile.ContentDirs() {
+			var files []string
+			for _, f := range contents.Other {
+				if rel, ok := strings.CutPrefix(f, dir+"/"); ok {
+					files = append(files, rel)
+				}
+			}
+			if len(files) > 0 {
+				fmt.Printf("  %s/ (%d files)\n", dir, len(files))
+				for _, f := range files {
+					fmt.Printf("    %s\n", f)
+				}
+			}
+		}
+
 		return nil
 	},
 }
@@ -156,7 +262,11 @@ var profileCreateCmd = &cobra.Command{
 var profileDeleteCmd = &cobra.Command{
 	Use:   "delete <name>",
 	Short: "Delete a profile from the local store",
-	Args:  cobra.ExactArgs(1),
+	Long: `Delete a profile from the local store after asking for confirmation.
+
+Locked profiles (see "ocmgr profile lock") are refused; --force skips
+the confirmation and deletes locked profiles too.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		force, _ := cmd.Flags().GetBool("force")
@@ -165,6 +275,13 @@ var profileDeleteCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("opening store: %w", err)
 		}
+		p, err := s.Get(name)
+		if err != nil {
+			return err
+		}
+		if p.Locked && !force {
+			return profile.LockedError(name)
+		}
 
 		if !force {
 			fmt.Printf("Delete profile '%s'? This cannot be undone. [y/N] ", name)
@@ -177,7 +294,7 @@ var profileDeleteCmd = &cobra.Command{
 			}
 		}
 
-		if err := s.Delete(name); err != nil {
+		if err := s.Delete(name, force); err != nil {
 			return err
 		}
 
@@ -186,19 +303,81 @@ var profileDeleteCmd = &cobra.Command{
 	},
 }
 
+// ── profile clone ─────────────────────────────────────────────────
+
+var profileCloneCmd = &cobra.Command{
+	Use:   "clone <src> <dest>",
+	Short: "Duplicate a profile in the local store",
+	Long: `Copy an existing profile to a new name in the local store.
+
+The cloned profile.toml has its name set to <dest> and its version
+cleared so the copy starts fresh. Any import source is dropped as well,
+so check-updates will not overwrite the fork with the upstream copy.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, dest := args[0], args[1]
+
+		if err := profile.ValidateName(dest); err != nil {
+			return err
+		}
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+
+		if _, err := s.Get(src); err != nil {
+			return err
+		}
+		if s.Exists(dest) {
+			return fmt.Errorf("profile %q already exists", dest)
+		}
+
+		destDir := s.ProfileDir(dest)
+		if err := github.CopyDirRecursive(s.ProfileDir(src), destDir); err != nil {
+			_ = os.RemoveAll(destDir)
+			return fmt.Errorf("copying profile: %w", err)
+		}
+
+		p, err := profile.LoadProfile(destDir)
+		if err != nil {
+			_ = os.RemoveAll(destDir)
+			return fmt.Errorf("loading cloned profile: %w", err)
+		}
+		p.Name = dest
+		p.Version = ""
+		p.Source = nil
+		if err := profile.SaveProfile(p); err != nil {
+			_ = os.RemoveAll(destDir)
+			return fmt.Errorf("saving cloned profile: %w", err)
+		}
+
+		fmt.Printf("Cloned profile '%s' to '%s' at %s\n", src, dest, destDir)
+		return nil
+	},
+}
+
 // ── profile import ────────────────────────────────────────────────
 
 var profileImportCmd = &cobra.Command{
 	Use:   "import <source>",
-	Short: "Import a profile from a local directory or GitHub URL",
+	Short: "Import a profile from a local directory, archive, or GitHub URL",
 	Long: `Import a profile into the local store.
 
 The source can be:
   - A local directory containing a valid profile.toml
+  - A .tar.gz, .tgz or .zip archive of such a directory, as written by
+    "ocmgr profile export --archive"
   - A GitHub URL (https://github.com/<owner>/<repo>/tree/<branch>/profiles/<name>)
+  - A git SSH remote, optionally followed by //<path> to the profile
+    inside the repository and @<ref> for a branch or tag
+    (git@github.com:<owner>/<repo>.git//profiles/<name>@<branch>).
+    SSH sources clone with your SSH keys, so private repositories work.
 
 Examples:
   ocmgr profile import /path/to/my-profile
+  ocmgr profile import ./go.tar.gz
+  ocmgr profile import git@github.com:user/opencode-profiles.git//profiles/go@main
   ocmgr profile import https://github.com/user/opencode-profiles/tree/main/profiles/go`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -209,38 +388,26 @@ Examples:
 			return fmt.Errorf("opening store: %w", err)
 		}
 
-		var srcDir string
-		var tmpDir string
+		unlock, err := s.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
 
 		if isGitHubURL(source) {
-			// Parse the URL and clone the repo to extract the profile.
-			repo, branch, profilePath, err := parseGitHubProfileURL(source)
+			p, err := importGitHubProfile(s, source, false)
 			if err != nil {
 				return err
 			}
+			fmt.Printf("✓ Imported profile %q to %s\n", p.Name, p.Path)
+			return nil
+		}
 
-			tmpDir, err = os.MkdirTemp("", "ocmgr-import-*")
-			if err != nil {
-				return fmt.Errorf("creating temp dir: %w", err)
-			}
-			defer os.RemoveAll(tmpDir)
-
-			cloneURL := fmt.Sprintf("https://github.com/%s.git", repo)
-			cloneCmd := exec.Command("git", "clone", "--depth", "1", "--branch", branch, cloneURL, tmpDir)
-			cloneCmd.Stderr = os.Stderr
-			if err := cloneCmd.Run(); err != nil {
-				return fmt.Errorf("cloning %s: %w", repo, err)
-			}
-
-			srcDir = filepath.Join(tmpDir, profilePath)
-		} else {
-			// Local directory.
-			abs, err := filepath.Abs(source)
-			if err != nil {
-				return fmt.Errorf("resolving path: %w", err)
-			}
-			srcDir = abs
+		srcDir, cleanup, err := resolveImportSource(source)
+		if err != nil {
+			return err
 		}
+		defer cleanup()
 
 		// Validate the source is a proper profile.
 		p, err := github.ValidateProfileDir(srcDir)
@@ -263,15 +430,134 @@ Examples:
 	},
 }
 
+// ── profile check-updates ─────────────────────────────────────────
+
+var profileCheckUpdatesCmd = &cobra.Command{
+	Use:   "check-updates [name]",
+	Short: "Check imported profiles for upstream changes",
+	Long: `Check profiles that were imported from a GitHub URL for upstream
+changes. The commit recorded at import time is compared against the
+current commit of the upstream branch using "git ls-remote", so no
+profile content is downloaded.
+
+Profiles created locally (or imported from a local directory) have no
+recorded source and are skipped.
+
+Use --update to re-import every outdated profile from its source.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		update, _ := cmd.Flags().GetBool("update")
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+
+		var profiles []*profile.Profile
+		if len(args) == 1 {
+			p, err := s.Get(args[0])
+			if err != nil {
+				return err
+			}
+			if p.Source == nil {
+				return fmt.Errorf("profile %q was not imported from GitHub; nothing to check", p.Name)
+			}
+			profiles = append(profiles, p)
+		} else {
+			all, err := s.List()
+			if err != nil {
+				return fmt.Errorf("listing profiles: %w", err)
+			}
+			for _, p := range all {
+				if p.Source != nil {
+					profiles = append(profiles, p)
+				}
+			}
+		}
+
+		if len(profiles) == 0 {
+			fmt.Println("No imported profiles found.")
+			return nil
+		}
+
+		var outdated []*profile.Profile
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "PROFILE\tSOURCE\tSTATUS\n")
+		for _, p := range profiles {
+			src := fmt.Sprintf("%s@%s", p.Source.Repo, p.Source.Branch)
+			latest, behind, err := checkUpstream(p)
+			switch {
+			case err != nil:
+				fmt.Fprintf(w, "%s\t%s\t✗ %v\n", p.Name, src, err)
+			case !behind:
+				fmt.Fprintf(w, "%s\t%s\t✓ up to date\n", p.Name, src)
+			default:
+				fmt.Fprintf(w, "%s\t%s\t~ outdated (%s → %s)\n", p.Name, src, shortSHA(p.Source.Commit), shortSHA(latest))
+				outdated = append(outdated, p)
+			}
+		}
+		w.Flush()
+
+		if !update || len(outdated) == 0 {
+			if len(outdated) > 0 {
+				fmt.Println("\nRun with --update to re-import outdated profiles.")
+			}
+			return nil
+		}
+
+		unlock, err := s.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		fmt.Println()
+		for _, p := range outdated {
+			updated, err := importGitHubProfile(s, p.Source.URL(), true)
+			if err != nil {
+				return fmt.Errorf("updating %q: %w", p.Name, err)
+			}
+			fmt.Printf("✓ Updated profile %q to %s\n", updated.Name, shortSHA(updated.Source.Commit))
+		}
+		return nil
+	},
+}
+
+// checkUpstream reports whether the upstream branch of an imported
+// profile has moved past the commit recorded at import time. latest is
+// the commit the branch points to now.
+func checkUpstream(p *profile.Profile) (latest string, outdated bool, err error) {
+	latest, err = github.RemoteCommit(p.Source.Repo, p.Source.Branch)
+	if err != nil {
+		return "", false, err
+	}
+	return latest, latest != p.Source.Commit, nil
+}
+
 // ── profile export ────────────────────────────────────────────────
 
 var profileExportCmd = &cobra.Command{
 	Use:   "export <name> <target-dir>",
 	Short: "Export a profile to a local directory",
-	Args:  cobra.ExactArgs(2),
+	Long: `Export a profile to <target-dir>/<name>.
+
+With --archive the profile is written as a single file instead,
+<target-dir>/<name>.tar.gz (or .zip with --format zip), which
+"ocmgr profile import" accepts directly.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		targetDir := args[1]
+		asArchive, _ := cmd.Flags().GetBool("archive")
+		formatFlag, _ := cmd.Flags().GetString("format")
+
+		format, err := archive.ParseFormat(formatFlag)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("format") {
+			asArchive = true
+		}
 
 		s, err := store.NewStore()
 		if err != nil {
@@ -288,6 +574,18 @@ var profileExportCmd = &cobra.Command{
 			return fmt.Errorf("resolving target: %w", err)
 		}
 
+		if asArchive {
+			if err := os.MkdirAll(abs, 0o755); err != nil {
+				return fmt.Errorf("creating target: %w", err)
+			}
+			dst := filepath.Join(abs, name+format.Ext())
+			if err := archive.Write(p.Path, dst, name, format); err != nil {
+				return fmt.Errorf("exporting profile: %w", err)
+			}
+			fmt.Printf("✓ Exported profile %q to %s\n", name, dst)
+			return nil
+		}
+
 		dst := filepath.Join(abs, name)
 		if err := github.CopyDirRecursive(p.Path, dst); err != nil {
 			return fmt.Errorf("exporting profile: %w", err)
@@ -298,8 +596,820 @@ var profileExportCmd = &cobra.Command{
 	},
 }
 
+// ── profile rename-tag ────────────────────────────────────────────
+
+var profileRenameTagCmd = &cobra.Command{
+	Use:   "rename-tag <old> <new>",
+	Short: "Rename a tag across every profile in the store",
+	Long: `Rename a tag in every profile that uses it. If a profile already
+has the new tag, the old one is simply removed so no duplicates are
+left behind.
+
+Use --dry-run to list the affected profiles without saving anything.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldTag := strings.TrimSpace(args[0])
+		newTag := strings.TrimSpace(args[1])
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if oldTag == "" || newTag == "" {
+			return fmt.Errorf("tags must not be empty")
+		}
+		if oldTag == newTag {
+			return fmt.Errorf("old and new tag are the same")
+		}
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+
+		profiles, err := s.List()
+		if err != nil {
+			return fmt.Errorf("listing profiles: %w", err)
+		}
+
+		prefix := ""
+		if dryRun {
+			prefix = "[dry run] "
+		}
+
+		updated := 0
+		for _, p := range profiles {
+			tags, changed := renameTag(p.Tags, oldTag, newTag)
+			if !changed {
+				continue
+			}
+			updated++
+			if dryRun {
+				fmt.Printf("%s%s\n", prefix, p.Name)
+				continue
+			}
+			p.Tags = tags
+			if err := profile.SaveProfile(p); err != nil {
+				return fmt.Errorf("saving profile %q: %w", p.Name, err)
+			}
+			fmt.Printf("  %s\n", p.Name)
+		}
+
+		if updated == 0 {
+			fmt.Printf("No profiles use tag %q.\n", oldTag)
+			return nil
+		}
+
+		if dryRun {
+			fmt.Printf("%sWould rename tag %q to %q in %d profile(s)\n", prefix, oldTag, newTag, updated)
+		} else {
+			fmt.Printf("✓ Renamed tag %q to %q in %d profile(s)\n", oldTag, newTag, updated)
+		}
+		return nil
+	},
+}
+
+// ── profile tree ──────────────────────────────────────────────────
+
+var profileTreeCmd = &cobra.Command{
+	Use:   "tree <name>",
+	Short: "Show the extends tree of a profile",
+	Long: `Show the profiles <name> extends as a tree, followed by the order
+"ocmgr init" applies them in. A profile shared by several parents is
+expanded once and marked "(see above)" afterwards.
+
+With --indent, each layer is shown as an indented list together with
+the content it contributes (agents, commands, skills, plugins).
+
+Circular dependencies are marked in the tree and make the command fail.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		indent, _ := cmd.Flags().GetBool("indent")
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+
+		root := resolver.Tree(args[0], storeLoader(s))
+		if root.Err != nil {
+			return root.Err
+		}
+
+		if indent {
+			printTreeIndented(s, root, 0)
+		} else {
+			fmt.Println(root.Name)
+			printTreeBranches(root.Parents, "")
+		}
+
+		if root.HasCycle() {
+			return fmt.Errorf("profile %q has a circular dependency", root.Name)
+		}
+		order, err := resolver.Resolve([]string{root.Name}, storeLoader(s))
+		if err != nil {
+			return fmt.Errorf("resolving profile dependencies: %w", err)
+		}
+		fmt.Printf("\nApply order: %s\n", strings.Join(order, " → "))
+		return nil
+	},
+}
+
+// treeMarker returns the note printed after a node's name, if any.
+func treeMarker(n *resolver.Node) string {
+	switch {
+	case n.Cycle:
+		return "  ⟲ cycle"
+	case n.Repeat:
+		return "  (see above)"
+	case n.Err != nil:
+		return "  ✗ " + n.Err.Error()
+	}
+	return ""
+}
+
+// printTreeBranches prints nodes with box-drawing connectors below a
+// parent line, prefix holding the connectors of the enclosing levels.
+func printTreeBranches(nodes []*resolver.Node, prefix string) {
+	for i, n := range nodes {
+		connector, next := "├── ", "│   "
+		if i == len(nodes)-1 {
+			connector, next = "└── ", "    "
+		}
+		fmt.Printf("%s%s%s%s\n", prefix, connector, n.Name, treeMarker(n))
+		printTreeBranches(n.Parents, prefix+next)
+	}
+}
+
+// printTreeIndented prints n and its parents as an indented list, with a
+// summary of the content each expanded layer contributes.
+func printTreeIndented(s *store.Store, n *resolver.Node, depth int) {
+	pad := strings.Repeat("  ", depth)
+	fmt.Printf("%s%s%s\n", pad, n.Name, treeMarker(n))
+	if n.Cycle || n.Repeat || n.Err != nil {
+		return
+	}
+
+	if summary := layerSummary(s, n.Name); summary != "" {
+		fmt.Printf("%s  · %s\n", pad, summary)
+	}
+	for _, p := range n.Parents {
+		printTreeIndented(s, p, depth+1)
+	}
+}
+
+// layerSummary describes the content directories of the named profile,
+// e.g. "agents (2), skills (1)", or "no content".
+func layerSummary(s *store.Store, name string) string {
+	p, err := s.Get(name)
+	if err != nil {
+		return ""
+	}
+	c, err := profile.ListContents(p)
+	if err != nil {
+		return ""
+	}
+
+	var parts []string
+	add := func(dir string, files []string) {
+		if len(files) > 0 {
+			parts = append(parts, fmt.Sprintf("%s (%d)", dir, len(files)))
+		}
+	}
+	add("agents", c.Agents)
+	add("commands", c.Commands)
+	add("skills", c.Skills)
+	add("plugins", c.Plugins)
+	add("other", c.Other)
+	if len(parts) == 0 {
+		return "no content"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ── profile tag ───────────────────────────────────────────────────
+
+var profileTagCmd = &cobra.Command{
+	Use:   "tag <name>",
+	Short: "Add or remove tags on a profile",
+	Long: `Edit the tags of a profile without touching its contents. Both
+--add and --remove can be repeated or given comma-separated values;
+removals are applied after additions. Existing tags keep their order,
+new ones are appended and duplicates are dropped.
+
+Without flags the current tags are printed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		add, _ := cmd.Flags().GetStringSlice("add")
+		remove, _ := cmd.Flags().GetStringSlice("remove")
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+
+		p, err := s.Get(name)
+		if err != nil {
+			return err
+		}
+
+		if len(add) > 0 || len(remove) > 0 {
+			p.Tags = editTags(p.Tags, add, remove)
+			if err := profile.SaveProfile(p); err != nil {
+				return fmt.Errorf("saving profile %q: %w", p.Name, err)
+			}
+		}
+
+		if len(p.Tags) == 0 {
+			fmt.Printf("Profile %q has no tags.\n", p.Name)
+			return nil
+		}
+		fmt.Println(strings.Join(p.Tags, ", "))
+		return nil
+	},
+}
+
+var profileLockCmd = &cobra.Command{
+	Use:   "lock <name>",
+	Short: "Protect a profile from being deleted or overwritten",
+	Long: `Mark a profile as locked by setting locked = true in its
+profile.toml. "profile delete", "snapshot --update" and "sync pull"
+refuse to modify a locked profile unless --force is given.
+
+The lock is advisory metadata, not a filesystem lock: the files can
+still be edited directly. Remove it with "ocmgr profile unlock".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setLocked(args[0], true)
+	},
+}
+
+var profileUnlockCmd = &cobra.Command{
+	Use:   "unlock <name>",
+	Short: "Remove the lock from a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setLocked(args[0], false)
+	},
+}
+
+// setLocked sets or clears the locked flag of the named profile.
+func setLocked(name string, locked bool) error {
+	s, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("opening store: %w", err)
+	}
+
+	p, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+
+	state := "unlocked"
+	if locked {
+		state = "locked"
+	}
+	if p.Locked == locked {
+		fmt.Printf("Profile '%s' is already %s\n", name, state)
+		return nil
+	}
+
+	p.Locked = locked
+	if err := profile.SaveProfile(p); err != nil {
+		return fmt.Errorf("saving profile %q: %w", name, err)
+	}
+	fmt.Printf("Profile '%s' %s\n", name, state)
+	return nil
+}
+
+// ── profile bump ──────────────────────────────────────────────────
+
+var profileBumpCmd = &cobra.Command{
+	Use:   "bump <name> <major|minor|patch>",
+	Short: "Increment the version of a profile",
+	Long: `Increment one component of a profile's semantic version and save it
+to profile.toml: "major" turns 1.2.3 into 2.0.0, "minor" into 1.3.0
+and "patch" into 1.2.4. Any prerelease suffix is dropped, and a
+prerelease of the bumped part becomes its release: "patch" turns
+1.2.4-rc.1 into 1.2.4. A profile without a version is bumped from
+0.0.0.
+
+The version is what "ocmgr status" and "ocmgr upgrade" compare against
+the version recorded when a profile was applied to a project.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, part := args[0], args[1]
+		force, _ := cmd.Flags().GetBool("force")
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+
+		unlock, err := s.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		p, err := s.Get(name)
+		if err != nil {
+			return err
+		}
+		if p.Locked && !force {
+			return profile.LockedError(name)
+		}
+
+		version, err := profile.BumpVersion(p.Version, part)
+		if err != nil {
+			return err
+		}
+		old := p.Version
+		p.Version = version
+		if err := profile.SaveProfile(p); err != nil {
+			return fmt.Errorf("saving profile %q: %w", name, err)
+		}
+		fmt.Printf("Profile '%s' version %s → %s\n", name, displayVersion(old), version)
+		return nil
+	},
+}
+
+// displayVersion returns version for messages, or "unversioned" if it
+// is empty.
+func displayVersion(version string) string {
+	if version == "" {
+		return "unversioned"
+	}
+	return version
+}
+
+var profileSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search profiles by name, description, tags, and files",
+	Long: `Search every profile in the local store for query, ignoring case.
+The query is matched against each profile's name, description and tags,
+and the paths of its agents, commands and skills.
+
+With --content the text of those files is searched as well, and each
+matching line is printed with its file and line number.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := strings.TrimSpace(args[0])
+		if query == "" {
+			return fmt.Errorf("query must not be empty")
+		}
+		content, _ := cmd.Flags().GetBool("content")
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+		profiles, err := s.List()
+		if err != nil {
+			return fmt.Errorf("listing profiles: %w", err)
+		}
+
+		results := []searchResult{}
+		for _, p := range profiles {
+			matches, err := searchProfile(p, query, content)
+			if err != nil {
+				return fmt.Errorf("searching profile %q: %w", p.Name, err)
+			}
+			if len(matches) > 0 {
+				results = append(results, searchResult{Profile: p.Name, Matches: matches})
+			}
+		}
+
+		if jsonOutput {
+			return printJSON(results)
+		}
+		if len(results) == 0 {
+			fmt.Printf("No profiles match %q.\n", query)
+			return nil
+		}
+		for i, r := range results {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(r.Profile)
+			for _, m := range r.Matches {
+				switch {
+				case m.Line > 0:
+					fmt.Printf("  %s:%d: %s\n", m.File, m.Line, m.Snippet)
+				case m.File != "":
+					fmt.Printf("  file: %s\n", m.File)
+				default:
+					fmt.Printf("  %s: %s\n", m.Field, m.Snippet)
+				}
+			}
+		}
+		return nil
+	},
+}
+
 // ── helpers ───────────────────────────────────────────────────────
 
+// searchResult lists where a query matched in one profile.
+type searchResult struct {
+	Profile string        `json:"profile"`
+	Matches []searchMatch `json:"matches"`
+}
+
+// searchMatch is one place a query matched. Field is "name",
+// "description", "tags", "file" (the path matched) or "content" (a line
+// of the file matched).
+type searchMatch struct {
+	Field   string `json:"field"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// maxMatchesPerFile caps the content matches reported for one file.
+const maxMatchesPerFile = 3
+
+// searchProfile matches query, case-insensitively, against the metadata
+// and file paths of p and, when content is set, the lines of its agents,
+// commands and skills.
+func searchProfile(p *profile.Profile, query string, content bool) ([]searchMatch, error) {
+	q := strings.ToLower(query)
+	var matches []searchMatch
+
+	if strings.Contains(strings.ToLower(p.Name), q) {
+		matches = append(matches, searchMatch{Field: "name", Snippet: p.Name})
+	}
+	if strings.Contains(strings.ToLower(p.Description), q) {
+		matches = append(matches, searchMatch{Field: "description", Snippet: snippet(p.Description, q)})
+	}
+	for _, t := range p.Tags {
+		if strings.Contains(strings.ToLower(t), q) {
+			matches = append(matches, searchMatch{Field: "tags", Snippet: strings.Join(p.Tags, ", ")})
+			break
+		}
+	}
+
+	c, err := profile.ListContents(p)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	files = append(files, c.Agents...)
+	files = append(files, c.Commands...)
+	files = append(files, c.Skills...)
+	files = append(files, c.Other...)
+
+	for _, rel := range files {
+		if strings.Contains(strings.ToLower(rel), q) {
+			matches = append(matches, searchMatch{Field: "file", File: rel})
+		}
+		if !content {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(p.Path, rel))
+		if err != nil {
+			return nil, err
+		}
+		found := 0
+		for i, line := range strings.Split(string(data), "\n") {
+			if !strings.Contains(strings.ToLower(line), q) {
+				continue
+			}
+			matches = append(matches, searchMatch{Field: "content", File: rel, Line: i + 1, Snippet: snippet(line, q)})
+			if found++; found == maxMatchesPerFile {
+				break
+			}
+		}
+	}
+	return matches, nil
+}
+
+// snippet trims line to about 80 characters around the first match of
+// the lower-cased query q.
+func snippet(line, q string) string {
+	const width = 80
+	line = strings.Join(strings.Fields(line), " ")
+	if len(line) <= width {
+		return line
+	}
+
+	start := strings.Index(strings.ToLower(line), q) - (width-len(q))/2
+	if start < 0 {
+		start = 0
+	}
+	end := min(start+width, len(line))
+	start = max(end-width, 0)
+
+	// Do not cut a multi-byte character in half.
+	for start > 0 && !utf8.RuneStart(line[start]) {
+		start--
+	}
+	for end < len(line) && !utf8.RuneStart(line[end]) {
+		end++
+	}
+
+	out := line[start:end]
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(line) {
+		out += "…"
+	}
+	return out
+}
+
+// filterByTags returns the profiles whose tags include all of want, or
+// at least one of them when matchAny is true. Tags are compared
+// case-insensitively.
+func filterByTags(profiles []*profile.Profile, want []string, matchAny bool) []*profile.Profile {
+	var out []*profile.Profile
+	for _, p := range profiles {
+		have := make(map[string]bool, len(p.Tags))
+		for _, t := range p.Tags {
+			have[strings.ToLower(t)] = true
+		}
+		matched := 0
+		for _, t := range want {
+			if have[strings.ToLower(strings.TrimSpace(t))] {
+				matched++
+			}
+		}
+		if (matchAny && matched > 0) || (!matchAny && matched == len(want)) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// editTags appends add to tags and then drops every tag in remove,
+// trimming whitespace and dropping blanks and duplicates while
+// preserving order.
+func editTags(tags, add, remove []string) []string {
+	drop := make(map[string]bool, len(remove))
+	for _, t := range remove {
+		drop[strings.TrimSpace(t)] = true
+	}
+	seen := make(map[string]bool, len(tags)+len(add))
+	var out []string
+	for _, t := range append(append([]string{}, tags...), add...) {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] || drop[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+// renameTag replaces oldTag with newTag in tags, dropping duplicates
+// while preserving order. It reports whether oldTag was present.
+func renameTag(tags []string, oldTag, newTag string) ([]string, bool) {
+	found := false
+	seen := make(map[string]bool, len(tags))
+	var out []string
+	for _, t := range tags {
+		if t == oldTag {
+			found = true
+			t = newTag
+		}
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	if !found {
+		return tags, false
+	}
+	return out, true
+}
+
+// resolveImportSource turns a local directory, archive, or git SSH
+// source into a directory holding the profile to import. cleanup removes
+// any temporary files and must be called once the directory has been
+// copied; it is a no-op for local directories.
+func resolveImportSource(source string) (dir string, cleanup func(), err error) {
+	cleanup = func() {}
+
+	if isSSHSource(source) {
+		remote, subPath, ref := parseSSHSource(source)
+		tmpDir, err := os.MkdirTemp("", "ocmgr-import-*")
+		if err != nil {
+			return "", cleanup, fmt.Errorf("creating temp dir: %w", err)
+		}
+		cleanup = func() { os.RemoveAll(tmpDir) }
+
+		if _, err := github.CloneURL(remote, ref, tmpDir); err != nil {
+			cleanup()
+			return "", func() {}, err
+		}
+		dir = filepath.Join(tmpDir, filepath.FromSlash(subPath))
+		if rel, err := filepath.Rel(tmpDir, dir); err != nil || strings.HasPrefix(rel, "..") {
+			cleanup()
+			return "", func() {}, fmt.Errorf("invalid path %q in %s", subPath, source)
+		}
+		return dir, cleanup, nil
+	}
+
+	dir, err = filepath.Abs(source)
+	if err != nil {
+		return "", cleanup, fmt.Errorf("resolving path: %w", err)
+	}
+	if _, ok := archive.Detect(dir); !ok {
+		return dir, cleanup, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "ocmgr-import-*")
+	if err != nil {
+		return "", cleanup, fmt.Errorf("creating temp dir: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(tmpDir) }
+
+	if err := archive.Extract(dir, tmpDir); err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+	if dir, err = archiveProfileDir(tmpDir); err != nil {
+		cleanup()
+		return "", func() {}, fmt.Errorf("%s: %w", source, err)
+	}
+	return dir, cleanup, nil
+}
+
+// isSSHSource reports whether source is a git SSH remote, either in scp
+// form (git@host:owner/repo.git) or as an ssh:// URL. An existing local
+// path is never one, even if its name contains "@" and ":".
+func isSSHSource(s string) bool {
+	if strings.HasPrefix(s, "ssh://") {
+		return true
+	}
+	if _, err := os.Lstat(s); err == nil {
+		return false
+	}
+	at := strings.Index(s, "@")
+	colon := strings.Index(s, ":")
+	return at > 0 && colon > at && !strings.ContainsAny(s[:at], "/:")
+}
+
+// parseSSHSource splits an SSH import source of the form
+// <remote>[//<path>][@<ref>] into its parts. The ref separator is the
+// last "@" that comes after every "/" and ":", so the user part of the
+// remote (git@) is never mistaken for it.
+func parseSSHSource(source string) (remote, subPath, ref string) {
+	if at := strings.LastIndex(source, "@"); at > strings.LastIndexAny(source, "/:") {
+		source, ref = source[:at], source[at+1:]
+	}
+
+	// Skip the scheme's own "//" when looking for the path separator.
+	start := 0
+	if strings.HasPrefix(source, "ssh://") {
+		start = len("ssh://")
+	}
+	remote = source
+	if i := strings.Index(source[start:], "//"); i >= 0 {
+		remote = source[:start+i]
+		subPath = strings.Trim(source[start+i+2:], "/")
+	}
+	return remote, subPath, ref
+}
+
+// archiveProfileDir returns the profile directory inside an extracted
+// archive: either dir itself or its only subdirectory.
+func archiveProfileDir(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "profile.toml")); err == nil {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return "", fmt.Errorf("archive does not contain a single profile directory")
+}
+
+// importGitHubProfile clones the repository behind a GitHub tree URL,
+// copies the profile it points to into the store, and records the
+// source in the imported profile.toml. When replace is true an
+// existing profile with the same name is overwritten.
+func importGitHubProfile(s *store.Store, url string, replace bool) (*profile.Profile, error) {
+	p, source, cleanup, err := cloneGitHubProfile(url)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	srcDir := p.Path
+
+	// The name comes from the remote profile.toml and becomes a path in
+	// the store.
+	if err := profile.ValidateName(p.Name); err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	targetDir := s.ProfileDir(p.Name)
+	exists := s.Exists(p.Name)
+	if exists {
+		if !replace {
+			return nil, fmt.Errorf("profile %q already exists; delete it first with 'ocmgr profile delete %s'", p.Name, p.Name)
+		}
+		if err := profile.CheckUnlocked(targetDir); err != nil {
+			return nil, err
+		}
+	}
+
+	// Copy the profile into a staging directory next to its final place
+	// and swap it in once complete, so a failed copy leaves any existing
+	// profile untouched.
+	staging, err := os.MkdirTemp(s.Dir, ".import-"+p.Name+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	stagedDir := filepath.Join(staging, p.Name)
+	if err := github.CopyDirRecursive(srcDir, stagedDir); err != nil {
+		return nil, fmt.Errorf("importing profile: %w", err)
+	}
+
+	// Record where the profile came from so check-updates can find it.
+	p.Path = stagedDir
+	p.Source = source
+	if err := profile.SaveProfile(p); err != nil {
+		return nil, fmt.Errorf("recording profile source: %w", err)
+	}
+
+	// The old copy is moved into the staging directory, which is removed
+	// on return, only once the new one is in place.
+	oldDir := filepath.Join(staging, "old")
+	if exists {
+		if err := os.Rename(targetDir, oldDir); err != nil {
+			return nil, fmt.Errorf("replacing profile %q: %w", p.Name, err)
+		}
+	}
+	if err := os.Rename(stagedDir, targetDir); err != nil {
+		if exists {
+			_ = os.Rename(oldDir, targetDir)
+		}
+		return nil, fmt.Errorf("replacing profile %q: %w", p.Name, err)
+	}
+
+	p.Path = targetDir
+	return p, nil
+}
+
+// cloneGitHubProfile shallow-clones the repository behind a GitHub tree
+// URL into a temporary directory and loads the profile it points to;
+// p.Path is the profile's directory inside the clone. cleanup removes
+// the clone and must be called once the profile has been copied.
+func cloneGitHubProfile(url string) (p *profile.Profile, source *profile.Source, cleanup func(), err error) {
+	cleanup = func() {}
+
+	repo, branch, profilePath, err := parseGitHubProfileURL(url)
+	if err != nil {
+		return nil, nil, cleanup, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "ocmgr-import-*")
+	if err != nil {
+		return nil, nil, cleanup, fmt.Errorf("creating temp dir: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(tmpDir) }
+
+	commit, err := github.CloneRef(repo, branch, tmpDir)
+	if err != nil {
+		cleanup()
+		return nil, nil, func() {}, err
+	}
+
+	// Validate the source is a proper profile.
+	p, err = github.ValidateProfileDir(filepath.Join(tmpDir, profilePath))
+	if err != nil {
+		cleanup()
+		return nil, nil, func() {}, err
+	}
+
+	source = &profile.Source{
+		Repo:   repo,
+		Branch: branch,
+		Path:   profilePath,
+		Commit: commit,
+	}
+	return p, source, cleanup, nil
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
 // isGitHubURL checks if a string looks like a GitHub URL.
 func isGitHubURL(s string) bool {
 	return strings.HasPrefix(s, "https://github.com/") ||
@@ -327,12 +1437,34 @@ func parseGitHubProfileURL(url string) (repo, branch, profilePath string, err er
 }
 
 func init() {
-	profileDeleteCmd.Flags().BoolP("force", "f", false, "skip confirmation prompt")
+	profileListCmd.Flags().StringSlice("tag", nil, "only list profiles with this tag (repeatable)")
+	profileListCmd.Flags().String("match", "all", "with several --tag flags, require all or any of them")
+	profileShowCmd.Flags().Bool("extends-chain", false, "print only the resolved extends chain in apply order")
+	profileShowCmd.Flags().Bool("summary", false, "print file counts and sizes per content directory instead of the file list")
+	profileDeleteCmd.Flags().BoolP("force", "f", false, "skip confirmation prompt and delete even if locked")
+	profileExportCmd.Flags().Bool("archive", false, "write a single archive file instead of a directory")
+	profileExportCmd.Flags().String("format", string(archive.TarGz), "archive format: tar.gz or zip (implies --archive)")
+	profileCheckUpdatesCmd.Flags().Bool("update", false, "re-import profiles that have upstream changes")
+	profileRenameTagCmd.Flags().BoolP("dry-run", "d", false, "list affected profiles without saving")
+	profileTreeCmd.Flags().Bool("indent", false, "show an indented view with the content of each layer")
+	profileBumpCmd.Flags().BoolP("force", "f", false, "bump the version even if the profile is locked")
+	profileSearchCmd.Flags().Bool("content", false, "also search the text of agents, commands and skills")
+	profileTagCmd.Flags().StringSlice("add", nil, "tag to add (repeatable)")
+	profileTagCmd.Flags().StringSlice("remove", nil, "tag to remove (repeatable)")
 
 	profileCmd.AddCommand(profileListCmd)
 	profileCmd.AddCommand(profileShowCmd)
 	profileCmd.AddCommand(profileCreateCmd)
 	profileCmd.AddCommand(profileDeleteCmd)
+	profileCmd.AddCommand(profileCloneCmd)
 	profileCmd.AddCommand(profileImportCmd)
 	profileCmd.AddCommand(profileExportCmd)
+	profileCmd.AddCommand(profileCheckUpdatesCmd)
+	profileCmd.AddCommand(profileRenameTagCmd)
+	profileCmd.AddCommand(profileTagCmd)
+	profileCmd.AddCommand(profileTreeCmd)
+	profileCmd.AddCommand(profileSearchCmd)
+	profileCmd.AddCommand(profileLockCmd)
+	profileCmd.AddCommand(profileUnlockCmd)
+	profileCmd.AddCommand(profileBumpCmd)
 }
@@ -0,0 +1,387 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acchapm1/ocmgr/internal/archive"
+	"github.com/acchapm1/ocmgr/internal/github"
+	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/store"
+)
+
+func TestIsSSHSource(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "me@host:profiles")
+	if err := os.Mkdir(local, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		source string
+		want   bool
+	}{
+		{"git@github.com:owner/repo.git", true},
+		{"git@github.com:owner/repo.git//profiles/go@main", true},
+		{"ssh://git@github.com/owner/repo.git//go", true},
+		{"./profiles/go", false},
+		{"/tmp/go.tar.gz", false},
+		{"https://github.com/owner/repo/tree/main/go", false},
+		{local, false},
+	} {
+		if got := isSSHSource(tc.source); got != tc.want {
+			t.Errorf("isSSHSource(%q) = %v, want %v", tc.source, got, tc.want)
+		}
+	}
+}
+
+func TestParseSSHSource(t *testing.T) {
+	for _, tc := range []struct {
+		source, remote, subPath, ref string
+	}{
+		{"git@github.com:owner/repo.git", "git@github.com:owner/repo.git", "", ""},
+		{"git@github.com:owner/repo.git//profiles/go", "git@github.com:owner/repo.git", "profiles/go", ""},
+		{"git@github.com:owner/repo.git//profiles/go@v1.2", "git@github.com:owner/repo.git", "profiles/go", "v1.2"},
+		{"ssh://git@host/owner/repo.git//go@main", "ssh://git@host/owner/repo.git", "go", "main"},
+	} {
+		remote, subPath, ref := parseSSHSource(tc.source)
+		if remote != tc.remote || subPath != tc.subPath || ref != tc.ref {
+			t.Errorf("parseSSHSource(%q) = %q, %q, %q; want %q, %q, %q",
+				tc.source, remote, subPath, ref, tc.remote, tc.subPath, tc.ref)
+		}
+	}
+}
+
+// writeTestProfile creates a profile called name below dir and returns
+// its path.
+func writeTestProfile(t *testing.T, dir, name string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Join(p, "agents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	toml := "[profile]\nname = \"" + name + "\"\n"
+	if err := os.WriteFile(filepath.Join(p, "profile.toml"), []byte(toml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(p, "agents", "a.md"), []byte("# a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestResolveImportSourceDir(t *testing.T) {
+	src := writeTestProfile(t, t.TempDir(), "go")
+
+	dir, cleanup, err := resolveImportSource(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup()
+	if dir != src {
+		t.Errorf("dir = %q, want %q", dir, src)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("cleanup removed the source directory: %v", err)
+	}
+}
+
+func TestResolveImportSourceArchive(t *testing.T) {
+	for _, format := range []archive.Format{archive.TarGz, archive.Zip} {
+		t.Run(string(format), func(t *testing.T) {
+			tmp := t.TempDir()
+			src := writeTestProfile(t, tmp, "go")
+			path := filepath.Join(tmp, "go"+format.Ext())
+			if err := archive.Write(src, path, "go", format); err != nil {
+				t.Fatal(err)
+			}
+
+			dir, cleanup, err := resolveImportSource(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "agents", "a.md")); err != nil {
+				t.Errorf("extracted profile is missing agents/a.md: %v", err)
+			}
+			cleanup()
+			if _, err := os.Stat(dir); !os.IsNotExist(err) {
+				t.Errorf("cleanup left %s behind", dir)
+			}
+		})
+	}
+}
+
+// fakeGitHub makes https://github.com/ URLs resolve to directories below
+// the returned root, so owner/repo lives at <root>/owner/repo.git.
+func fakeGitHub(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "url.file://"+root+"/.insteadOf")
+	t.Setenv("GIT_CONFIG_VALUE_0", "https://github.com/")
+	return root
+}
+
+// gitRun runs git in dir with a fixed identity.
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestImportGitHubProfileRecordsSource(t *testing.T) {
+	root := fakeGitHub(t)
+	repo := filepath.Join(root, "owner", "repo.git")
+	writeTestProfile(t, filepath.Join(repo, "profiles"), "go")
+	gitRun(t, root, "init", "-q", "-b", "main", repo)
+	gitRun(t, repo, "add", "-A")
+	gitRun(t, repo, "commit", "-q", "-m", "initial")
+	head, err := github.HeadCommit(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := store.NewStoreAt(filepath.Join(root, "store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := importGitHubProfile(s, "https://github.com/owner/repo/tree/main/profiles/go", false); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := s.Get("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := profile.Source{Repo: "owner/repo", Branch: "main", Path: "profiles/go", Commit: head}
+	if p.Source == nil || *p.Source != want {
+		t.Fatalf("Source = %+v, want %+v", p.Source, want)
+	}
+
+	latest, outdated, err := checkUpstream(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outdated || latest != head {
+		t.Errorf("checkUpstream() = %s, %v right after import; want %s, false", latest, outdated, head)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "profiles", "go", "agents", "b.md"), []byte("# b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, repo, "add", "-A")
+	gitRun(t, repo, "commit", "-q", "-m", "upstream change")
+	newHead, err := github.HeadCommit(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, outdated, err = checkUpstream(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outdated || latest != newHead {
+		t.Errorf("checkUpstream() = %s, %v after an upstream commit; want %s, true", latest, outdated, newHead)
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		tags    []string
+		want    []string
+		changed bool
+	}{
+		{"renamed in place", []string{"web", "js", "api"}, []string{"web", "javascript", "api"}, true},
+		{"new tag already present", []string{"javascript", "web", "js"}, []string{"javascript", "web"}, true},
+		{"new tag later in the list", []string{"js", "web", "javascript"}, []string{"javascript", "web"}, true},
+		{"tag absent", []string{"go", "web"}, []string{"go", "web"}, false},
+		{"no tags", nil, nil, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, changed := renameTag(tc.tags, "js", "javascript")
+			if changed != tc.changed || !slices.Equal(got, tc.want) {
+				t.Errorf("renameTag(%q) = %q, %v; want %q, %v", tc.tags, got, changed, tc.want, tc.changed)
+			}
+		})
+	}
+}
+
+func TestProfileRenameTagCmd(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Chdir(home)
+
+	s, err := store.NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, tags := range map[string][]string{
+		"frontend": {"js", "web"},
+		"node":     {"javascript", "js"},
+		"go":       {"go"},
+	} {
+		p := &profile.Profile{Name: name, Tags: tags, Path: filepath.Join(s.Dir, name)}
+		if err := profile.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filepath.Join(p.Path, "profile.toml"), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rewritten := func(name string) bool {
+		t.Helper()
+		info, err := os.Stat(filepath.Join(s.Dir, name, "profile.toml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return !info.ModTime().Equal(old)
+	}
+
+	// No profile has the tag: nothing is written.
+	rootCmd.SetArgs([]string{"profile", "rename-tag", "--dry-run=false", "rust", "rs"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"frontend", "node", "go"} {
+		if rewritten(name) {
+			t.Errorf("%s was rewritten although it has no rust tag", name)
+		}
+	}
+
+	rootCmd.SetArgs([]string{"profile", "rename-tag", "--dry-run=false", "js", "javascript"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string][]string{
+		"frontend": {"javascript", "web"},
+		"node":     {"javascript"},
+		"go":       {"go"},
+	} {
+		p, err := s.Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(p.Tags, want) {
+			t.Errorf("%s tags = %q, want %q", name, p.Tags, want)
+		}
+		if got := rewritten(name); got != (name != "go") {
+			t.Errorf("%s rewritten = %v, want %v", name, got, name != "go")
+		}
+	}
+}
+
+func TestImportGitHubProfileReplace(t *testing.T) {
+	root := fakeGitHub(t)
+	repo := filepath.Join(root, "owner", "repo.git")
+	remote := writeTestProfile(t, filepath.Join(repo, "profiles"), "go")
+	gitRun(t, root, "init", "-q", "-b", "main", repo)
+	gitRun(t, repo, "add", "-A")
+	gitRun(t, repo, "commit", "-q", "-m", "initial")
+	url := "https://github.com/owner/repo/tree/main/profiles/go"
+
+	s, err := store.NewStoreAt(filepath.Join(root, "store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := importGitHubProfile(s, url, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeEntries := func() []string {
+		t.Helper()
+		entries, err := os.ReadDir(s.Dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		return names
+	}
+
+	// A file that cannot be copied makes the re-import fail; the
+	// existing profile must survive it.
+	if err := os.Symlink("missing", filepath.Join(remote, "agents", "broken.md")); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, repo, "add", "-A")
+	gitRun(t, repo, "commit", "-q", "-m", "broken")
+	if _, err := importGitHubProfile(s, url, true); err == nil {
+		t.Fatal("re-import with an uncopyable file succeeded")
+	}
+	p, err := s.Get("go")
+	if err != nil {
+		t.Fatalf("profile lost after a failed re-import: %v", err)
+	}
+	if p.Source == nil || p.Source.Commit != first.Source.Commit {
+		t.Errorf("Source = %+v, want the original import", p.Source)
+	}
+	if _, err := os.Stat(filepath.Join(p.Path, "agents", "a.md")); err != nil {
+		t.Errorf("agents/a.md lost after a failed re-import: %v", err)
+	}
+	if got := storeEntries(); !slices.Equal(got, []string{"go"}) {
+		t.Errorf("store holds %v after a failed re-import, want [go]", got)
+	}
+
+	// Once fixed upstream, the re-import replaces the profile.
+	if err := os.Remove(filepath.Join(remote, "agents", "broken.md")); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, remote, "agents/b.md")
+	gitRun(t, repo, "add", "-A")
+	gitRun(t, repo, "commit", "-q", "-m", "fixed")
+	updated, err := importGitHubProfile(s, url, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Source.Commit == first.Source.Commit {
+		t.Error("Source commit was not updated")
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, "go", "agents", "b.md")); err != nil {
+		t.Errorf("agents/b.md missing after re-import: %v", err)
+	}
+	if got := storeEntries(); !slices.Equal(got, []string{"go"}) {
+		t.Errorf("store holds %v after re-import, want [go]", got)
+	}
+}
+
+func TestImportGitHubProfileRejectsInvalidName(t *testing.T) {
+	root := fakeGitHub(t)
+	repo := filepath.Join(root, "owner", "repo.git")
+	dir := writeTestProfile(t, filepath.Join(repo, "profiles"), "go")
+	toml := "[profile]\nname = \"../escape\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "profile.toml"), []byte(toml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, root, "init", "-q", "-b", "main", repo)
+	gitRun(t, repo, "add", "-A")
+	gitRun(t, repo, "commit", "-q", "-m", "initial")
+
+	s, err := store.NewStoreAt(filepath.Join(root, "store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := importGitHubProfile(s, "https://github.com/owner/repo/tree/main/profiles/go", true); err == nil {
+		t.Fatal("import of a profile named ../escape succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(root, "escape")); !os.IsNotExist(err) {
+		t.Error("the profile was written outside the store")
+	}
+}
@@ -1,29 +1,84 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
+	"github.com/acchapm1/ocmgr/internal/config"
+	"github.com/acchapm1/ocmgr/internal/copier"
+	"github.com/acchapm1/ocmgr/internal/github"
+	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/store"
 	"github.com/acchapm1/ocmgr/internal/tui"
 )
 
 // Version is set via ldflags at build time.
 var Version = "dev"
 
+// jsonOutput is set by the persistent --json flag. Commands that support
+// it print machine-readable JSON to stdout instead of their usual tables.
+var jsonOutput bool
+
+// verbose is set by the persistent --verbose flag. It enables diagnostic
+// logging of git commands and copied files on stderr.
+var verbose bool
+
 var rootCmd = &cobra.Command{
 	Use:     "ocmgr",
 	Short:   "OpenCode Profile Manager",
 	Long:    "ocmgr manages .opencode directory profiles.\n\nIt lets you create, snapshot, and apply reusable configuration\nprofiles for OpenCode projects so every repo starts with the\nright set of instructions, skills, and MCP servers.\n\nRun with no arguments to launch the interactive TUI.",
 	Version: Version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// The TUI owns the terminal and always needs a TTY, so logging
+		// and colour detection only apply to subcommands.
+		if cmd.HasParent() {
+			disableColorIfNeeded()
+		}
+		if verbose && cmd.HasParent() {
+			l := log.New(os.Stderr, "[ocmgr] ", 0)
+			github.SetLogger(l)
+			copier.SetLogger(l)
+		}
+		// An unreadable config is reported by the commands that need
+		// it; the content directories and git host then keep their
+		// defaults.
+		if cfg, err := config.Load(); err == nil {
+			profile.SetContentDirs(cfg.Defaults.ContentDirs)
+			copier.SetContentDirs(cfg.Defaults.ContentDirs)
+			github.SetHost(cfg.GitHub.Host)
+		}
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// --theme picks a built-in theme, ignoring the colours the
+		// config overrides, which were chosen for its theme.
+		theme := config.DefaultConfig().Theme
+		if cfg, err := config.Load(); err == nil {
+			theme = cfg.Theme
+		}
+		if name, _ := cmd.Flags().GetString("theme"); name != "" {
+			theme = config.Theme{Name: name}
+		}
+		t, err := tui.ConfigTheme(theme)
+		if err != nil {
+			return err
+		}
+		tui.SetTheme(t)
+
 		m, err := tui.NewModel()
 		if err != nil {
 			return fmt.Errorf("initializing TUI: %w", err)
 		}
-		p := tea.NewProgram(m, tea.WithAltScreen())
+		if editor, _ := cmd.Flags().GetString("editor"); editor != "" {
+			m.SetEditor(editor)
+		}
+		p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 		if _, err := p.Run(); err != nil {
 			return fmt.Errorf("running TUI: %w", err)
 		}
@@ -35,11 +90,48 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		if hint := errorHint(err); hint != "" {
+			fmt.Fprintln(os.Stderr, "Hint: "+hint)
+		}
 		os.Exit(1)
 	}
 }
 
+// errorHint suggests a next step for well-known errors, or returns "".
+// Unknown profile names get the closest stored names, if any.
+func errorHint(err error) string {
+	var notFound *store.NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		if s, serr := store.NewStore(); serr == nil {
+			if names := s.Suggest(notFound.Name); len(names) > 0 {
+				return fmt.Sprintf("did you mean: %s?", strings.Join(names, ", "))
+			}
+		}
+		return fmt.Sprintf("if %q is in your sync repository, did you mean to pull it? Run: ocmgr sync pull %s", notFound.Name, notFound.Name)
+	case errors.Is(err, github.ErrProfileNotFound):
+		return "run \"ocmgr sync status\" to list the profiles in the remote repository"
+	}
+	return ""
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "print machine-readable JSON output where supported")
+	rootCmd.Flags().String("editor", "", `editor command for the TUI, e.g. "code --wait" (default: defaults.editor, then $EDITOR)`)
+	rootCmd.Flags().String("theme", "", "colour theme for the TUI: "+strings.Join(config.ThemeNames(), ", ")+" (default: theme.name)")
+	_ = rootCmd.RegisterFlagCompletionFunc("theme", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return config.ThemeNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "log git commands and copied files to stderr")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable coloured output (also set by NO_COLOR; off automatically when not writing to a terminal)")
+
 	// Subcommands
-	rootCmd.AddCommand(initCmd, profileCmd, snapshotCmd, configCmd, syncCmd)
+	rootCmd.AddCommand(initCmd, profileCmd, snapshotCmd, configCmd, syncCmd, storeCmd, diffCmd)
 }
@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/acchapm1/ocmgr/internal/configgen"
 	"github.com/acchapm1/ocmgr/internal/copier"
 	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/provenance"
 	"github.com/acchapm1/ocmgr/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -16,18 +18,66 @@ import (
 // skipFiles is the set of infrastructure files that should not be copied
 // when snapshotting a .opencode directory into a profile.
 var skipFiles = map[string]bool{
-	"node_modules": true,
-	"package.json": true,
-	"bun.lock":     true,
-	".gitignore":   true,
+	"node_modules":      true,
+	"package.json":      true,
+	"bun.lock":          true,
+	".gitignore":        true,
+	provenance.FileName: true,
 }
 
 var snapshotCmd = &cobra.Command{
 	Use:   "snapshot <name> [source-dir]",
 	Short: "Capture current .opencode directory as a profile",
-	Args:  cobra.RangeArgs(1, 2),
+	Long: `Capture the content directories of an existing .opencode directory
+(agents, commands, skills, plugins, and any others listed in the
+defaults.content_dirs setting) as a new profile in the store.
+
+Content directories that end up with no files are removed from the new
+profile. Pass --prune-empty=false to keep the full directory skeleton.
+
+With --update, an existing profile is refreshed in place instead:
+new and changed files are copied into it while its metadata
+(description, tags, version) is left untouched. Add --prune to also
+delete profile files that no longer exist in the .opencode directory,
+and --bump major|minor|patch to increment its semantic version. A new
+snapshot starts at version 0.1.0.
+A locked profile (see "ocmgr profile lock") is only updated with
+--force.
+
+Paths matched by a .ocmgrignore file at the root of the .opencode
+directory are not captured (and not pruned). It uses gitignore-style
+patterns, one per line, for example:
+
+  .DS_Store
+  *.swp
+  agents/local-notes.md
+
+Pass --with-config to also capture the "plugin" list and "mcp" servers
+from .opencode/opencode.json. They are stored as opencode.json at the
+root of the profile, and "ocmgr init" merges them into the project's
+opencode.json. Values in an MCP server's "environment" and "headers"
+that look like secrets (names such as *_TOKEN, *_KEY or Authorization,
+or values such as "sk-..." and "Bearer ...") are replaced by
+{env:NAME} placeholders, which opencode reads from the environment;
+the redacted keys are listed so you can set them. The heuristic is not
+perfect, so this stays opt-in: review the profile's opencode.json
+before sharing it.`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
+		pruneEmpty, _ := cmd.Flags().GetBool("prune-empty")
+		update, _ := cmd.Flags().GetBool("update")
+		prune, _ := cmd.Flags().GetBool("prune")
+		withConfig, _ := cmd.Flags().GetBool("with-config")
+		force, _ := cmd.Flags().GetBool("force")
+		bump, _ := cmd.Flags().GetString("bump")
+
+		if prune && !update {
+			return fmt.Errorf("--prune requires --update")
+		}
+		if bump != "" && !update {
+			return fmt.Errorf("--bump requires --update")
+		}
 
 		sourceDir := "."
 		if len(args) > 1 {
@@ -50,8 +100,56 @@ var snapshotCmd = &cobra.Command{
 			return fmt.Errorf("opening store: %w", err)
 		}
 
+		unlock, err := s.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
 		if s.Exists(name) {
-			return fmt.Errorf("profile %q already exists; delete it first with 'ocmgr profile delete %s' or choose a different name", name, name)
+			if !update {
+				return fmt.Errorf("profile %q already exists; use --update to refresh it, delete it first with 'ocmgr profile delete %s', or choose a different name", name, name)
+			}
+
+			existing, err := s.Get(name)
+			if err != nil {
+				return err
+			}
+			if existing.Locked && !force {
+				return profile.LockedError(name)
+			}
+			newVersion := existing.Version
+			if bump != "" {
+				if newVersion, err = profile.BumpVersion(existing.Version, bump); err != nil {
+					return err
+				}
+			}
+			added, updated, removed, err := updateSnapshot(existing, openCodeDir, prune)
+			if err != nil {
+				return err
+			}
+			if pruneEmpty {
+				if _, err := profile.PruneEmptyDirs(existing); err != nil {
+					return fmt.Errorf("pruning empty directories: %w", err)
+				}
+			}
+			if withConfig {
+				if err := captureConfig(openCodeDir, existing.Path); err != nil {
+					return err
+				}
+			}
+
+			if newVersion != existing.Version {
+				oldVersion := existing.Version
+				existing.Version = newVersion
+				if err := profile.SaveProfile(existing); err != nil {
+					return fmt.Errorf("saving profile metadata: %w", err)
+				}
+				fmt.Printf("Version bumped: %s → %s\n", displayVersion(oldVersion), newVersion)
+			}
+
+			fmt.Printf("Snapshot '%s' updated: %d added, %d updated, %d removed\n", name, added, updated, removed)
+			return nil
 		}
 
 		p, err := profile.ScaffoldProfile(s.Dir, name)
@@ -67,14 +165,14 @@ var snapshotCmd = &cobra.Command{
 			}
 		}()
 
-		// Copy files from each content directory.
-		counts := map[string]int{
-			"agents":   0,
-			"commands": 0,
-			"skills":   0,
-			"plugins":  0,
+		ignore, err := copier.LoadIgnore(openCodeDir)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", copier.IgnoreFile, err)
 		}
 
+		// Copy files from each content directory.
+		counts := make(map[string]int)
+
 		for _, dir := range profile.ContentDirs() {
 			srcDir := filepath.Join(openCodeDir, dir)
 			if _, err := os.Stat(srcDir); os.IsNotExist(err) {
@@ -86,8 +184,12 @@ var snapshotCmd = &cobra.Command{
 					return walkErr
 				}
 
-				// Skip infrastructure files and directories.
-				if skipFiles[info.Name()] {
+				// Skip infrastructure files and ignored paths.
+				ignored, err := ignoredPath(ignore, openCodeDir, path, info)
+				if err != nil {
+					return err
+				}
+				if ignored {
 					if info.IsDir() {
 						return filepath.SkipDir
 					}
@@ -116,6 +218,19 @@ var snapshotCmd = &cobra.Command{
 			}
 		}
 
+		// Drop content directories that did not receive any files.
+		if pruneEmpty {
+			if _, err := profile.PruneEmptyDirs(p); err != nil {
+				return fmt.Errorf("pruning empty directories: %w", err)
+			}
+		}
+
+		if withConfig {
+			if err := captureConfig(openCodeDir, p.Path); err != nil {
+				return err
+			}
+		}
+
 		// Prompt for description and tags.
 		reader := bufio.NewReader(os.Stdin)
 
@@ -140,14 +255,199 @@ var snapshotCmd = &cobra.Command{
 		// Update and save profile metadata.
 		p.Description = description
 		p.Tags = tags
+		p.Version = profile.InitialVersion
 		if err := profile.SaveProfile(p); err != nil {
 			return fmt.Errorf("saving profile metadata: %w", err)
 		}
 
 		success = true
-		fmt.Printf("Snapshot '%s' created with %d agents, %d commands, %d skills, %d plugins\n",
-			name, counts["agents"], counts["commands"], counts["skills"], counts["plugins"])
+		parts := make([]string, 0, len(profile.ContentDirs()))
+		for _, dir := range profile.ContentDirs() {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[dir], dir))
+		}
+		fmt.Printf("Snapshot '%s' created with %s\n", name, strings.Join(parts, ", "))
 
 		return nil
 	},
 }
+
+// updateSnapshot refreshes the content directories of an existing profile
+// from openCodeDir. Files that are new or differ are copied into the
+// profile; identical files are left alone. When prune is true, profile
+// files with no counterpart in openCodeDir are deleted, except the
+// infrastructure files a snapshot never captures and those matched by
+// its .ocmgrignore, along with the directories that leaves empty. The
+// profile's metadata is not modified.
+func updateSnapshot(p *profile.Profile, openCodeDir string, prune bool) (added, updated, removed int, err error) {
+	ignore, err := copier.LoadIgnore(openCodeDir)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("reading %s: %w", copier.IgnoreFile, err)
+	}
+
+	present := make(map[string]bool)
+
+	for _, dir := range profile.ContentDirs() {
+		srcDir := filepath.Join(openCodeDir, dir)
+		if _, err := os.Stat(srcDir); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.Walk(srcDir, func(path string, info os.FileInfo, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+
+			ignored, err := ignoredPath(ignore, openCodeDir, path, info)
+			if err != nil {
+				return err
+			}
+			if ignored {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if info.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(openCodeDir, path)
+			if err != nil {
+				return fmt.Errorf("computing relative path: %w", err)
+			}
+			present[rel] = true
+
+			dst := filepath.Join(p.Path, rel)
+			if _, err := os.Stat(dst); os.IsNotExist(err) {
+				if err := copier.CopyFile(path, dst); err != nil {
+					return fmt.Errorf("copying %s: %w", rel, err)
+				}
+				added++
+				return nil
+			}
+
+			equal, err := copier.FilesEqual(path, dst)
+			if err != nil {
+				return fmt.Errorf("comparing %s: %w", rel, err)
+			}
+			if !equal {
+				if err := copier.CopyFile(path, dst); err != nil {
+					return fmt.Errorf("copying %s: %w", rel, err)
+				}
+				updated++
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("walking %s: %w", dir, err)
+		}
+	}
+
+	if !prune {
+		return added, updated, 0, nil
+	}
+
+	for _, dir := range profile.ContentDirs() {
+		profDir := filepath.Join(p.Path, dir)
+		if _, err := os.Stat(profDir); os.IsNotExist(err) {
+			continue
+		}
+
+		var stale []string
+		err := filepath.Walk(profDir, func(path string, info os.FileInfo, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+
+			ignored, err := ignoredPath(ignore, p.Path, path, info)
+			if err != nil {
+				return err
+			}
+			if ignored {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(p.Path, path)
+			if err != nil {
+				return fmt.Errorf("computing relative path: %w", err)
+			}
+			if !present[rel] {
+				stale = append(stale, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("walking %s: %w", dir, err)
+		}
+
+		for _, path := range stale {
+			if err := os.Remove(path); err != nil {
+				return 0, 0, 0, fmt.Errorf("removing %s: %w", path, err)
+			}
+			removed++
+
+			// Remove the directories this emptied, up to the content
+			// directory itself, which --prune-empty handles. Removing a
+			// directory that is not empty fails and ends the climb.
+			for d := filepath.Dir(path); d != profDir; d = filepath.Dir(d) {
+				if os.Remove(d) != nil {
+					break
+				}
+			}
+		}
+	}
+
+	return added, updated, removed, nil
+}
+
+func init() {
+	snapshotCmd.Flags().Bool("prune-empty", true, "remove content directories left empty by the snapshot")
+	snapshotCmd.Flags().Bool("update", false, "refresh an existing profile in place, keeping its metadata")
+	snapshotCmd.Flags().Bool("prune", false, "with --update, delete profile files missing from the source")
+	snapshotCmd.Flags().BoolP("force", "f", false, "with --update, refresh the profile even if it is locked")
+	snapshotCmd.Flags().String("bump", "", "with --update, increment the profile version (major, minor, or patch)")
+	snapshotCmd.Flags().Bool("with-config", false, "also capture plugins and MCP servers from opencode.json")
+}
+
+// captureConfig stores the plugins and MCP servers of the opencode.json
+// in openCodeDir in the profile at profileDir, and warns about values
+// that were replaced by placeholders.
+func captureConfig(openCodeDir, profileDir string) error {
+	result, err := configgen.Capture(openCodeDir, profileDir)
+	if err != nil {
+		return fmt.Errorf("capturing opencode.json: %w", err)
+	}
+	if result == nil {
+		fmt.Printf("No opencode.json in %s; no plugins or MCP servers captured\n", openCodeDir)
+		return nil
+	}
+	fmt.Printf("Captured opencode.json with %d plugin(s) and %d MCP server(s)\n", result.Plugins, result.MCPs)
+	if len(result.Redacted) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: replaced %d secret-looking value(s) with {env:NAME} placeholders:\n", len(result.Redacted))
+		for _, path := range result.Redacted {
+			fmt.Fprintf(os.Stderr, "  %s\n", path)
+		}
+		fmt.Fprintln(os.Stderr, "Set the variables named in the profile's opencode.json before running opencode.")
+	}
+	return nil
+}
+
+// ignoredPath reports whether path, found while walking openCodeDir, is
+// an infrastructure file or matched by the directory's .ocmgrignore.
+func ignoredPath(ignore *copier.Ignore, openCodeDir, path string, info os.FileInfo) (bool, error) {
+	if skipFiles[info.Name()] {
+		return true, nil
+	}
+	rel, err := filepath.Rel(openCodeDir, path)
+	if err != nil {
+		return false, fmt.Errorf("computing relative path: %w", err)
+	}
+	return ignore.Match(rel, info.IsDir()), nil
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/store"
+)
+
+func writeFiles(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUpdateSnapshotPrune(t *testing.T) {
+	openCodeDir := filepath.Join(t.TempDir(), ".opencode")
+	writeFiles(t, openCodeDir, "agents/a.md", "skills/keep/SKILL.md", "commands/new.md")
+
+	p := &profile.Profile{
+		Name:        "go",
+		Description: "Go backend setup",
+		Tags:        []string{"go", "backend"},
+		Version:     "1.2.0",
+		Path:        writeTestProfile(t, t.TempDir(), "go"),
+	}
+	if err := profile.SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, p.Path,
+		"skills/keep/SKILL.md",
+		"skills/gone/SKILL.md",
+		"skills/gone/ref/notes.md",
+		"plugins/package.json",
+		"plugins/node_modules/x/index.js",
+	)
+
+	added, updated, removed, err := updateSnapshot(p, openCodeDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 1 || updated != 1 || removed != 2 {
+		t.Errorf("added, updated, removed = %d, %d, %d; want 1, 1, 2", added, updated, removed)
+	}
+
+	got, err := profile.LoadProfile(p.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != p.Description || !slices.Equal(got.Tags, p.Tags) || got.Version != p.Version {
+		t.Errorf("metadata after update = %q, %q, %q; want %q, %q, %q",
+			got.Description, got.Tags, got.Version, p.Description, p.Tags, p.Version)
+	}
+
+	for _, gone := range []string{"skills/gone"} {
+		if _, err := os.Stat(filepath.Join(p.Path, gone)); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed: %v", gone, err)
+		}
+	}
+	for _, kept := range []string{"agents/a.md", "commands/new.md", "skills/keep/SKILL.md", "plugins/package.json", "plugins/node_modules/x/index.js"} {
+		if _, err := os.Stat(filepath.Join(p.Path, kept)); err != nil {
+			t.Errorf("%s was pruned: %v", kept, err)
+		}
+	}
+}
+
+func TestSnapshotPruneEmpty(t *testing.T) {
+	for _, tc := range []struct {
+		flag string
+		want []string
+	}{
+		{"--prune-empty", []string{"skills"}},
+		{"--prune-empty=false", profile.ContentDirs()},
+	} {
+		t.Run(tc.flag, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", "")
+			t.Setenv("XDG_DATA_HOME", "")
+			t.Chdir(home)
+			stdin, err := os.Open(os.DevNull)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stdin.Close()
+			orig := os.Stdin
+			os.Stdin = stdin
+			t.Cleanup(func() { os.Stdin = orig })
+
+			project := filepath.Join(home, "project")
+			writeFiles(t, filepath.Join(project, ".opencode"), "skills/review/SKILL.md")
+
+			rootCmd.SetArgs([]string{"snapshot", tc.flag, "snap", project})
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := store.NewStore()
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := s.Get("snap")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, dir := range profile.ContentDirs() {
+				_, err := os.Stat(filepath.Join(p.Path, dir))
+				if kept := slices.Contains(tc.want, dir); kept != (err == nil) {
+					t.Errorf("%s exists = %v, want %v", dir, err == nil, kept)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/acchapm1/ocmgr/internal/copier"
+	"github.com/acchapm1/ocmgr/internal/provenance"
+	"github.com/acchapm1/ocmgr/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [target-dir]",
+	Short: "Show which profiles are applied to a project and whether they are current",
+	Long: `List the profiles recorded in .opencode/.ocmgr-applied.toml with the
+version that was applied, and compare every file they wrote against the
+profile in the local store.
+
+Each profile is reported as:
+
+  up to date   every file matches the store and the version is unchanged
+  drifted      files were changed or deleted in the project, or the
+               profile in the store has changed since it was applied
+  missing      the profile is no longer in the local store
+
+A file written by more than one profile is checked against the last one
+applied, as that is the copy init left in place.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+// Values of appliedStatus.Status.
+const (
+	statusUpToDate = "up to date"
+	statusDrifted  = "drifted"
+	statusMissing  = "missing"
+)
+
+// appliedStatus is the state of one applied profile, as printed by
+// "status" and "status --json".
+type appliedStatus struct {
+	Profile      string    `json:"profile"`
+	Version      string    `json:"version,omitempty"`
+	StoreVersion string    `json:"store_version,omitempty"`
+	AppliedAt    time.Time `json:"applied_at"`
+	Status       string    `json:"status"`
+	Files        int       `json:"files"`
+	// Modified lists files whose content differs from the profile.
+	Modified []string `json:"modified,omitempty"`
+	// Deleted lists files that are no longer in the project.
+	Deleted []string `json:"deleted,omitempty"`
+	// Dropped lists files the profile in the store no longer provides.
+	Dropped []string `json:"dropped,omitempty"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	targetDir := "."
+	if len(args) == 1 {
+		targetDir = args[0]
+	}
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("cannot resolve target directory: %w", err)
+	}
+	targetOpencode := filepath.Join(absTarget, ".opencode")
+
+	prov, err := provenance.Load(targetOpencode)
+	if err != nil {
+		return err
+	}
+	if len(prov.Profiles) == 0 {
+		if jsonOutput {
+			return printJSON([]appliedStatus{})
+		}
+		fmt.Printf("No profiles recorded in %s\n", targetOpencode)
+		return nil
+	}
+
+	s, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("cannot open store: %w", err)
+	}
+
+	statuses, err := appliedStatuses(s, prov, targetOpencode)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(statuses)
+	}
+
+	width := 0
+	for _, st := range statuses {
+		width = max(width, len(st.Profile))
+	}
+	fmt.Printf("Profiles applied to %s:\n\n", targetOpencode)
+	for _, st := range statuses {
+		version := st.Version
+		if version == "" {
+			version = "-"
+		}
+		var notes []string
+		if st.Status != statusMissing {
+			notes = append(notes, fmt.Sprintf("%d files", st.Files))
+		}
+		if n := len(st.Modified); n > 0 {
+			notes = append(notes, fmt.Sprintf("%d modified", n))
+		}
+		if n := len(st.Deleted); n > 0 {
+			notes = append(notes, fmt.Sprintf("%d deleted", n))
+		}
+		if n := len(st.Dropped); n > 0 {
+			notes = append(notes, fmt.Sprintf("%d no longer in profile", n))
+		}
+		if st.Status != statusMissing && st.StoreVersion != st.Version {
+			notes = append(notes, fmt.Sprintf("store has version %q", st.StoreVersion))
+		}
+		fmt.Printf("  %-*s  %-8s  %-10s  %s\n", width, st.Profile, version, st.Status, strings.Join(notes, ", "))
+
+		for _, f := range st.Modified {
+			fmt.Printf("      modified %s\n", f)
+		}
+		for _, f := range st.Deleted {
+			fmt.Printf("      deleted  %s\n", f)
+		}
+		for _, f := range st.Dropped {
+			fmt.Printf("      dropped  %s\n", f)
+		}
+	}
+	return nil
+}
+
+// appliedStatuses compares the profiles recorded in prov against the
+// store and the files in targetOpencode.
+func appliedStatuses(s *store.Store, prov *provenance.Provenance, targetOpencode string) ([]appliedStatus, error) {
+	// Work out which files each profile wrote. Profiles recorded by older
+	// versions have no file list; use what the profile provides today.
+	files := make(map[string][]string)
+	owner := make(map[string]string)
+	for _, e := range prov.Profiles {
+		list := e.Files
+		if len(list) == 0 {
+			if p, err := s.Get(e.Name); err == nil {
+				result, err := copier.CopyProfile(p.Path, targetOpencode, copier.Options{Strategy: copier.StrategyOverwrite, DryRun: true})
+				if err != nil {
+					return nil, fmt.Errorf("reading profile %q: %w", e.Name, err)
+				}
+				for _, rel := range result.Files() {
+					list = append(list, filepath.ToSlash(rel))
+				}
+			}
+		}
+		files[e.Name] = list
+		for _, f := range list {
+			owner[f] = e.Name
+		}
+	}
+
+	var statuses []appliedStatus
+	for _, e := range prov.Profiles {
+		st := appliedStatus{
+			Profile:   e.Name,
+			Version:   e.Version,
+			AppliedAt: e.AppliedAt,
+			Files:     len(files[e.Name]),
+		}
+
+		p, err := s.Get(e.Name)
+		if err != nil {
+			st.Status = statusMissing
+			statuses = append(statuses, st)
+			continue
+		}
+		st.StoreVersion = p.Version
+
+		for _, f := range files[e.Name] {
+			if owner[f] != e.Name {
+				continue
+			}
+			rel := filepath.FromSlash(f)
+			dst := filepath.Join(targetOpencode, rel)
+			src := filepath.Join(p.Path, rel)
+			if _, err := os.Stat(dst); os.IsNotExist(err) {
+				st.Deleted = append(st.Deleted, f)
+				continue
+			}
+			if _, err := os.Stat(src); os.IsNotExist(err) {
+				st.Dropped = append(st.Dropped, f)
+				continue
+			}
+			equal, err := copier.FilesEqual(src, dst)
+			if err != nil {
+				return nil, fmt.Errorf("comparing %s: %w", f, err)
+			}
+			if !equal {
+				st.Modified = append(st.Modified, f)
+			}
+		}
+
+		st.Status = statusUpToDate
+		if len(st.Modified)+len(st.Deleted)+len(st.Dropped) > 0 || st.StoreVersion != st.Version {
+			st.Status = statusDrifted
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,293 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acchapm1/ocmgr/internal/config"
+	"github.com/acchapm1/ocmgr/internal/github"
+	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Inspect and maintain the local profile store",
+}
+
+var storePathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the profile store directory",
+	Long: `Print the absolute path of the local profile store, as resolved from
+the store.path setting. The path is printed with no decoration so it
+can be used directly in scripts, e.g.:
+
+  cd "$(ocmgr store path)"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := storeDir()
+		if err != nil {
+			return err
+		}
+		fmt.Println(dir)
+		return nil
+	},
+}
+
+var storeOpenCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the profile store in the file manager",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := storeDir()
+		if err != nil {
+			return err
+		}
+
+		var open *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			open = exec.Command("open", dir)
+		case "windows":
+			open = exec.Command("explorer", dir)
+		default:
+			open = exec.Command("xdg-open", dir)
+		}
+		if err := open.Start(); err != nil {
+			return fmt.Errorf("opening %s: %w", dir, err)
+		}
+		return nil
+	},
+}
+
+var storeGcCmd = &cobra.Command{
+	Use:   "gc [project-dir]",
+	Short: "Reclaim disk space used by the sync cache and init backups",
+	Long: `Clean up files ocmgr keeps around, and report how much space was
+reclaimed:
+
+  - the sync cache is pruned: untracked leftovers are removed and git
+    garbage-collects its object store. With --hard the cache is deleted
+    and cloned again from the configured repository.
+  - backups written by "ocmgr init --backup" to
+    <project-dir>/.opencode/.ocmgr-backups/ that are older than
+    --older-than (default 30 days) are deleted. project-dir defaults
+    to the current directory.
+
+The profile store itself is never modified.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hard, _ := cmd.Flags().GetBool("hard")
+		olderThanRaw, _ := cmd.Flags().GetString("older-than")
+		olderThan, err := parseAge(olderThanRaw)
+		if err != nil {
+			return fmt.Errorf("--older-than: %w", err)
+		}
+
+		projectDir := "."
+		if len(args) == 1 {
+			projectDir = args[0]
+		}
+		absProject, err := filepath.Abs(projectDir)
+		if err != nil {
+			return fmt.Errorf("cannot resolve project directory: %w", err)
+		}
+
+		storePath, err := storeDir()
+		if err != nil {
+			return err
+		}
+
+		var total int64
+
+		reclaimed, err := gcSyncCache(storePath, hard)
+		if err != nil {
+			return err
+		}
+		total += reclaimed
+
+		backupDir := filepath.Join(absProject, ".opencode", ".ocmgr-backups")
+		reclaimed, err = gcBackups(backupDir, olderThan)
+		if err != nil {
+			return err
+		}
+		total += reclaimed
+
+		fmt.Printf("\nReclaimed %s in total.\n", profile.FormatSize(total))
+		return nil
+	},
+}
+
+// gcSyncCache prunes the sync cache, or deletes and re-clones it when
+// hard is set, and returns the number of bytes freed. It refuses to
+// touch a cache that overlaps the profile store or that ocmgr did not
+// create.
+func gcSyncCache(storePath string, hard bool) (int64, error) {
+	cache, err := filepath.Abs(config.CacheDir())
+	if err != nil {
+		return 0, fmt.Errorf("resolving cache path: %w", err)
+	}
+	if isWithin(cache, storePath) || isWithin(storePath, cache) || cache == config.ConfigDir() || cache == config.DataDir() {
+		return 0, fmt.Errorf("refusing to clean the sync cache at %s: it overlaps the profile store or the ocmgr directory", cache)
+	}
+	if _, err := os.Stat(cache); os.IsNotExist(err) {
+		fmt.Println("Sync cache: none")
+		return 0, nil
+	}
+	if !github.IsCache(cache) {
+		return 0, fmt.Errorf("refusing to clean %s: it is not a sync cache created by ocmgr", cache)
+	}
+
+	before := dirSize(cache)
+	if hard {
+		cfg, err := config.Load()
+		if err != nil {
+			return 0, fmt.Errorf("loading config: %w", err)
+		}
+		if err := os.RemoveAll(cache); err != nil {
+			return 0, fmt.Errorf("removing sync cache: %w", err)
+		}
+		if _, err := github.EnsureCache(cfg.GitHub.Repo, cfg.GitHub.Auth, ""); err != nil {
+			return 0, fmt.Errorf("re-cloning sync cache: %w", err)
+		}
+	} else if err := github.PruneCache(); err != nil {
+		return 0, fmt.Errorf("pruning sync cache: %w", err)
+	}
+
+	reclaimed := max(before-dirSize(cache), 0)
+	action := "pruned"
+	if hard {
+		action = "re-cloned"
+	}
+	fmt.Printf("Sync cache: %s %s (reclaimed %s)\n", action, cache, profile.FormatSize(reclaimed))
+	return reclaimed, nil
+}
+
+// gcBackups deletes the backup directories in dir older than age and
+// returns the number of bytes freed. A backup's age comes from its
+// timestamped name, or its modification time if the name is not one.
+func gcBackups(dir string, age time.Duration) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		fmt.Printf("Backups: none in %s\n", dir)
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading backups: %w", err)
+	}
+
+	cutoff := time.Now().Add(-age)
+	var reclaimed int64
+	removed := 0
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		created, err := time.ParseInLocation("20060102-150405", e.Name(), time.Local)
+		if err != nil {
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			created = info.ModTime()
+		}
+		if created.After(cutoff) {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		size := dirSize(path)
+		if err := os.RemoveAll(path); err != nil {
+			return reclaimed, fmt.Errorf("removing backup %s: %w", e.Name(), err)
+		}
+		reclaimed += size
+		removed++
+	}
+
+	// Drop the backups directory once nothing is left in it.
+	if rest, err := os.ReadDir(dir); err == nil && len(rest) == 0 {
+		_ = os.Remove(dir)
+	}
+
+	fmt.Printf("Backups: removed %d of %d older than %s from %s (reclaimed %s)\n",
+		removed, len(entries), formatAge(age), dir, profile.FormatSize(reclaimed))
+	return reclaimed, nil
+}
+
+// parseAge parses a duration such as "72h" or "30d"; a "d" suffix counts
+// whole days.
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q; use e.g. 72h or 30d", s)
+	}
+	return d, nil
+}
+
+// formatAge prints d in days when it is a whole number of days.
+func formatAge(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// isWithin reports whether path is dir or lies below it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// dirSize returns the total size of the regular files below dir.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// storeDir returns the absolute path of the configured profile store.
+func storeDir() (string, error) {
+	s, err := store.NewStore()
+	if err != nil {
+		return "", fmt.Errorf("cannot open store: %w", err)
+	}
+	dir, err := filepath.Abs(s.Dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving store path: %w", err)
+	}
+	return dir, nil
+}
+
+func init() {
+	storeCmd.AddCommand(storePathCmd)
+	storeCmd.AddCommand(storeOpenCmd)
+	storeCmd.AddCommand(storeGcCmd)
+
+	storeGcCmd.Flags().Bool("hard", false, "delete the sync cache and clone it again instead of pruning it")
+	storeGcCmd.Flags().String("older-than", "30d", "delete init backups older than this (e.g. 72h, 30d)")
+}
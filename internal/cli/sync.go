@@ -1,12 +1,20 @@
 package cli
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/acchapm1/ocmgr/internal/config"
+	"github.com/acchapm1/ocmgr/internal/copier"
 	"github.com/acchapm1/ocmgr/internal/github"
+	"github.com/acchapm1/ocmgr/internal/profile"
 	"github.com/acchapm1/ocmgr/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -14,22 +22,35 @@ import (
 var syncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Synchronize profiles with GitHub",
-	Long: `Sync profiles between the local store (~/.ocmgr/profiles) and a
+	Long: `Sync profiles between the local store ("ocmgr store path") and a
 remote GitHub repository. The repository and auth method are
-read from ~/.ocmgr/config.toml (see "ocmgr config show").
+read from the global config.toml (see "ocmgr config show").
 
 Use "ocmgr sync push" to upload a profile and "ocmgr sync pull"
-to download. "ocmgr sync status" shows which profiles differ.`,
+to download. "ocmgr sync status" shows which profiles differ, and
+"ocmgr sync diff <name>" shows what differs within one profile.`,
 }
 
 // ── sync push ─────────────────────────────────────────────────────
 
 var syncPushCmd = &cobra.Command{
-	Use:   "push <name>",
-	Short: "Push a local profile to GitHub",
-	Args:  cobra.ExactArgs(1),
+	Use:   "push <name>...",
+	Short: "Push local profiles to GitHub",
+	Long: `Push one or more local profiles to the remote repository. The
+change is committed and pushed directly to github.branch, or the
+repository's default branch if that is not set. Several profiles are pushed together as a single commit
+("sync: update N profiles") with a single push.
+
+If that branch is listed in github.protected_branches, the push is
+refused unless --allow-protected is given. Use this to make sure
+profile changes to shared repositories go through pull requests.
+
+With --dry-run, the files that would be committed are listed (git
+status and a diff stat) and nothing is committed or pushed.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
+		allowProtected, _ := cmd.Flags().GetBool("allow-protected")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 		cfg, err := config.Load()
 		if err != nil {
@@ -41,18 +62,53 @@ var syncPushCmd = &cobra.Command{
 			return fmt.Errorf("opening store: %w", err)
 		}
 
-		p, err := s.Get(name)
-		if err != nil {
-			return err
+		// Check every profile up-front so nothing is pushed if one is
+		// missing.
+		var names []string
+		seen := make(map[string]bool)
+		for _, name := range args {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if _, err := s.Get(name); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+
+		if len(cfg.GitHub.ProtectedBranches) > 0 {
+			branch, err := github.CacheBranch(cfg.GitHub.Repo, cfg.GitHub.Auth, cfg.GitHub.Branch)
+			if err != nil {
+				return fmt.Errorf("push failed: %w", err)
+			}
+			if cfg.GitHub.IsProtectedBranch(branch) && !allowProtected && !dryRun {
+				return fmt.Errorf("branch %q of %s is protected; re-run with --allow-protected to push anyway", branch, cfg.GitHub.Repo)
+			}
+		}
+
+		what := fmt.Sprintf("profile %q", names[0])
+		if len(names) > 1 {
+			what = fmt.Sprintf("%d profiles (%s)", len(names), strings.Join(names, ", "))
+		}
+
+		if dryRun {
+			fmt.Printf("[dry run] Changes that pushing %s to %s would commit:\n", what, cfg.GitHub.Repo)
+			preview, err := github.PushProfiles(names, s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth, cfg.GitHub.Branch, true)
+			if err != nil {
+				return fmt.Errorf("push failed: %w", err)
+			}
+			fmt.Print(preview)
+			return nil
 		}
 
-		fmt.Printf("Pushing profile %q to %s …\n", name, cfg.GitHub.Repo)
+		fmt.Printf("Pushing %s to %s …\n", what, cfg.GitHub.Repo)
 
-		if err := github.PushProfile(name, p.Path, cfg.GitHub.Repo, cfg.GitHub.Auth); err != nil {
+		if _, err := github.PushProfiles(names, s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth, cfg.GitHub.Branch, false); err != nil {
 			return fmt.Errorf("push failed: %w", err)
 		}
 
-		fmt.Printf("✓ Pushed profile %q\n", name)
+		fmt.Printf("✓ Pushed %s\n", what)
 		return nil
 	},
 }
@@ -62,9 +118,67 @@ var syncPushCmd = &cobra.Command{
 var syncPullCmd = &cobra.Command{
 	Use:   "pull [name]",
 	Short: "Pull a profile from GitHub (or --all)",
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Pull a profile from the remote repository into the local store,
+replacing any local copy. Use --all to pull every remote profile.
+
+With --into <target-dir>, the remote profile is NOT written to the
+store. Instead its contents are applied directly to the target's
+.opencode/ directory, as "ocmgr init" would. Conflicts are resolved
+interactively unless --force or --merge is given.
+
+Use --branch to pull from a branch or tag other than the repository's
+default branch, e.g. --branch release for stable profiles.
+
+By default the local copy is replaced, discarding local edits. Use
+--strategy to merge the remote profile into it instead:
+
+  replace    delete the local copy and copy the remote one (default)
+  overwrite  write every remote file; keep files that exist only locally
+  merge      only add files missing locally; keep local versions
+  prompt     ask for each file that differs
+  skip       like merge
+
+With a strategy other than replace, profile.toml is handled like any
+other file.
+
+Locked local profiles (see "ocmgr profile lock") are not modified
+unless --force is given.
+
+With --dry-run, nothing is written. The files the pull would create
+(A), overwrite (M), or delete (D) in the local store are listed
+instead; with --into, the files init would copy are listed.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		all, _ := cmd.Flags().GetBool("all")
+		into, _ := cmd.Flags().GetString("into")
+		force, _ := cmd.Flags().GetBool("force")
+		merge, _ := cmd.Flags().GetBool("merge")
+		branch, _ := cmd.Flags().GetString("branch")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		strategyRaw, _ := cmd.Flags().GetString("strategy")
+
+		strategy := copier.Strategy(strategyRaw)
+		if strategy != pullReplace && !validStrategies[strategy] {
+			return fmt.Errorf("invalid --strategy %q; must be one of: replace, overwrite, merge, prompt, skip", strategyRaw)
+		}
+		if strategy != pullReplace && into != "" {
+			return fmt.Errorf("--strategy cannot be combined with --into; use --force or --merge")
+		}
+
+		prefix := ""
+		if dryRun {
+			prefix = "[dry run] "
+		}
+
+		if into != "" && all {
+			return fmt.Errorf("--into and --all are mutually exclusive")
+		}
+		if force && merge {
+			return fmt.Errorf("--force and --merge are mutually exclusive")
+		}
+		if merge && into == "" {
+			return fmt.Errorf("--merge requires --into")
+		}
 
 		cfg, err := config.Load()
 		if err != nil {
@@ -76,44 +190,204 @@ var syncPullCmd = &cobra.Command{
 			return fmt.Errorf("opening store: %w", err)
 		}
 
-		if all {
-			fmt.Printf("Pulling all profiles from %s …\n", cfg.GitHub.Repo)
-			pulled, err := github.PullAll(s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth)
+		// Pulling into the store replaces profiles; keep other ocmgr
+		// processes out until it is done.
+		if into == "" && !dryRun {
+			unlock, err := s.Lock()
 			if err != nil {
-				return fmt.Errorf("pull failed: %w", err)
+				return err
 			}
-			if len(pulled) == 0 {
-				fmt.Println("No profiles found in remote repository.")
-				return nil
+			defer unlock()
+		}
+
+		if strategy != pullReplace {
+			// Refresh the cache once; pullMerge reads from it as is.
+			names := args
+			if all {
+				st, err := github.Status(s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth, branch, "")
+				if err != nil {
+					return fmt.Errorf("pull failed: %w", err)
+				}
+				names = append(append(append([]string{}, st.InSync...), st.Modified...), st.RemoteOnly...)
+				sort.Strings(names)
+			} else if len(names) == 0 {
+				return fmt.Errorf("provide a profile name or use --all")
+			} else if _, err := github.EnsureCache(cfg.GitHub.Repo, cfg.GitHub.Auth, branch); err != nil {
+				return fmt.Errorf("pull failed: %w", err)
 			}
-			fmt.Printf("✓ Pulled %d profiles:\n", len(pulled))
-			for _, name := range pulled {
-				fmt.Printf("    %s\n", name)
+
+			reader := bufio.NewReader(os.Stdin)
+			for _, name := range names {
+				fmt.Printf("%sPulling profile %q from %s (%s) …\n", prefix, name, cfg.GitHub.Repo, strategy)
+				if err := pullMerge(name, s.Dir, strategy, dryRun, force, reader); err != nil {
+					return fmt.Errorf("pull failed: %w", err)
+				}
 			}
 			return nil
 		}
 
+		if all {
+			fmt.Printf("%sPulling all profiles from %s …\n", prefix, cfg.GitHub.Repo)
+			pulled, err := github.PullAll(s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth, branch, dryRun, force)
+			return reportPullAll(pulled, err, dryRun)
+		}
+
 		if len(args) == 0 {
 			return fmt.Errorf("provide a profile name or use --all")
 		}
 
 		name := args[0]
-		fmt.Printf("Pulling profile %q from %s …\n", name, cfg.GitHub.Repo)
 
-		if err := github.PullProfile(name, s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth); err != nil {
+		if into != "" {
+			return pullInto(name, into, cfg, branch, force, merge, dryRun)
+		}
+
+		fmt.Printf("%sPulling profile %q from %s …\n", prefix, name, cfg.GitHub.Repo)
+
+		diff, err := github.PullProfile(name, s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth, branch, dryRun, force)
+		if err != nil {
 			return fmt.Errorf("pull failed: %w", err)
 		}
 
-		fmt.Printf("✓ Pulled profile %q\n", name)
+		if dryRun {
+			printPullPreview(name, diff)
+		} else {
+			fmt.Printf("✓ Pulled profile %q\n", name)
+		}
 		return nil
 	},
 }
 
+// reportPullAll prints the outcome of github.PullAll or CloneAll and
+// returns an error if any profile failed.
+func reportPullAll(pulled []github.Pulled, err error, dryRun bool) error {
+	var pullErr *github.PullError
+	if err != nil && !errors.As(err, &pullErr) {
+		return fmt.Errorf("pull failed: %w", err)
+	}
+	if len(pulled) == 0 && pullErr == nil {
+		fmt.Println("No profiles found in remote repository.")
+		return nil
+	}
+	if dryRun {
+		for _, p := range pulled {
+			printPullPreview(p.Name, p.Diff)
+		}
+	} else if len(pulled) > 0 {
+		fmt.Printf("✓ Pulled %d profiles:\n", len(pulled))
+		for _, p := range pulled {
+			fmt.Printf("    %s\n", p.Name)
+		}
+	}
+	if pullErr != nil {
+		fmt.Printf("✗ %d profiles failed:\n", len(pullErr.Failed))
+		names := make([]string, 0, len(pullErr.Failed))
+		for name := range pullErr.Failed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("    %s: %v\n", name, pullErr.Failed[name])
+		}
+		return fmt.Errorf("pulled %d, %d failed", len(pulled), len(pullErr.Failed))
+	}
+	return nil
+}
+
+// printPullPreview prints the files a dry-run pull of name would create
+// (A), overwrite (M) or delete (D).
+func printPullPreview(name string, d *github.ProfileDiff) {
+	if len(d.RemoteOnly)+len(d.Modified)+len(d.LocalOnly) == 0 {
+		fmt.Printf("%s: up to date\n", name)
+		return
+	}
+	fmt.Printf("%s:\n", name)
+	for _, rel := range d.RemoteOnly {
+		fmt.Printf("  A %s\n", filepath.Join(name, rel))
+	}
+	for _, rel := range d.Modified {
+		fmt.Printf("  M %s\n", filepath.Join(name, rel))
+	}
+	// The local copy is replaced wholesale, so local-only files go away.
+	for _, rel := range d.LocalOnly {
+		fmt.Printf("  D %s\n", filepath.Join(name, rel))
+	}
+}
+
+// ── sync clone ────────────────────────────────────────────────────
+
+var syncCloneCmd = &cobra.Command{
+	Use:   "clone <owner/repo>",
+	Short: "Pull all profiles from any GitHub repository",
+	Long: `Pull every profile from a repository other than the configured
+github.repo, for example one a colleague shares, into the local store.
+The repository must use the usual profiles/<name>/ layout. It is cloned
+to a temporary directory and the repository is not saved in the
+config, so later pushes and pulls still use github.repo.
+
+Authentication follows github.auth, as for the other sync commands.
+If any of the repository's profiles already exist locally nothing is
+pulled and the clashing names are listed; --force replaces them,
+locked ones included.
+
+  ocmgr sync clone alice/opencode-profiles
+  ocmgr sync clone alice/opencode-profiles --branch experimental`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo := args[0]
+		branch, _ := cmd.Flags().GetString("branch")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if err := github.ValidateRepo(repo); err != nil {
+			return err
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+
+		if !dryRun {
+			unlock, err := s.Lock()
+			if err != nil {
+				return err
+			}
+			defer unlock()
+		}
+
+		prefix := ""
+		if dryRun {
+			prefix = "[dry run] "
+		}
+		fmt.Printf("%sPulling all profiles from %s …\n", prefix, repo)
+		pulled, err := github.CloneAll(s.Dir, repo, cfg.GitHub.Auth, branch, dryRun, force)
+		return reportPullAll(pulled, err, dryRun)
+	},
+}
+
 // ── sync status ───────────────────────────────────────────────────
 
 var syncStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show sync status between local and remote profiles",
+	Long: `Compare the profiles in the local store with those in the remote
+repository.
+
+By default the remote side is the repository's default branch. Use
+--branch to compare against another branch or tag; note that this
+also switches the sync cache, so later pulls and pushes start from the
+branch they ask for.
+
+For each profile, the number of remote commits that touched it since
+it was last pulled or pushed from this machine is shown, which tells a
+profile you edited locally apart from one someone else pushed to. Use
+--since <commit> to count from a given commit or tag instead.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load()
 		if err != nil {
@@ -125,13 +399,21 @@ var syncStatusCmd = &cobra.Command{
 			return fmt.Errorf("opening store: %w", err)
 		}
 
-		fmt.Printf("Comparing local profiles with %s …\n\n", cfg.GitHub.Repo)
+		if !jsonOutput {
+			fmt.Printf("Comparing local profiles with %s …\n\n", cfg.GitHub.Repo)
+		}
 
-		st, err := github.Status(s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth)
+		branch, _ := cmd.Flags().GetString("branch")
+		since, _ := cmd.Flags().GetString("since")
+		st, err := github.Status(s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth, branch, since)
 		if err != nil {
 			return fmt.Errorf("status check failed: %w", err)
 		}
 
+		if jsonOutput {
+			return printJSON(st)
+		}
+
 		empty := len(st.InSync) == 0 && len(st.Modified) == 0 &&
 			len(st.LocalOnly) == 0 && len(st.RemoteOnly) == 0
 
@@ -144,27 +426,340 @@ var syncStatusCmd = &cobra.Command{
 		fmt.Fprintf(w, "PROFILE\tSTATUS\n")
 
 		for _, n := range st.InSync {
-			fmt.Fprintf(w, "%s\t✓ in sync\n", n)
+			if note := withRemoteCommits(st, n, ""); note != "" {
+				fmt.Fprintf(w, "%s\t✓ in sync (%s)\n", n, note)
+			} else {
+				fmt.Fprintf(w, "%s\t✓ in sync\n", n)
+			}
 		}
 		for _, n := range st.Modified {
-			fmt.Fprintf(w, "%s\t~ modified (push or pull to sync)\n", n)
+			fmt.Fprintf(w, "%s\t~ modified (%s)\n", n, withRemoteCommits(st, n, "push or pull to sync"))
 		}
 		for _, n := range st.LocalOnly {
 			fmt.Fprintf(w, "%s\t● local only (push to sync)\n", n)
 		}
 		for _, n := range st.RemoteOnly {
-			fmt.Fprintf(w, "%s\t○ remote only (pull to sync)\n", n)
+			fmt.Fprintf(w, "%s\t○ remote only (%s)\n", n, withRemoteCommits(st, n, "pull to sync"))
+		}
+
+		w.Flush()
+		return nil
+	},
+}
+
+// withRemoteCommits prefixes hint with the number of new remote commits
+// recorded in st for the profile name, if there are any, e.g. "2 new
+// remote commits; pull to sync".
+func withRemoteCommits(st *github.SyncStatus, name, hint string) string {
+	n := st.RemoteCommits[name]
+	if n == 0 {
+		return hint
+	}
+	note := fmt.Sprintf("%d new remote commits", n)
+	if n == 1 {
+		note = "1 new remote commit"
+	}
+	if hint == "" {
+		return note
+	}
+	return note + "; " + hint
+}
+
+// ── sync diff ─────────────────────────────────────────────────────
+
+var syncDiffCmd = &cobra.Command{
+	Use:   "diff <name>",
+	Short: "Show how a local profile differs from the remote copy",
+	Long: `Compare a profile in the local store with its copy in the remote
+repository and print what differs:
+
+  local only <path>    the file exists only locally (push would add it)
+  remote only <path>   the file exists only remotely (pull would add it)
+  modified <path>      followed by a unified diff from remote to local
+
+The diff direction matches "sync push": lines marked "+" are what the
+local copy would change on the remote.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		branch, _ := cmd.Flags().GetString("branch")
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		s, err := store.NewStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+
+		d, err := github.DiffProfile(name, s.Dir, cfg.GitHub.Repo, cfg.GitHub.Auth, branch)
+		if err != nil {
+			return fmt.Errorf("diff failed: %w", err)
+		}
+
+		if len(d.LocalOnly) == 0 && len(d.RemoteOnly) == 0 && len(d.Modified) == 0 {
+			fmt.Printf("✓ Profile %q is in sync with %s\n", name, cfg.GitHub.Repo)
+			return nil
+		}
+
+		for _, rel := range d.LocalOnly {
+			fmt.Printf("local only %s\n", rel)
+		}
+		for _, rel := range d.RemoteOnly {
+			fmt.Printf("remote only %s\n", rel)
+		}
+		for _, rel := range d.Modified {
+			fmt.Printf("modified %s\n", rel)
+			err := unifiedDiff("remote/"+rel, "local/"+rel,
+				filepath.Join(d.RemoteDir, rel), filepath.Join(d.LocalDir, rel))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "  (diff command failed: %v)\n", err)
+			}
+		}
+		return nil
+	},
+}
+
+// ── sync log ──────────────────────────────────────────────────────
+
+var syncLogCmd = &cobra.Command{
+	Use:   "log <name>",
+	Short: "Show the remote commit history of a profile",
+	Long: `List the commits in the remote repository that changed a profile,
+newest first, with their date, author and message. This is read from
+the sync cache, which is refreshed first.
+
+Use -n to show only the most recent entries and --branch to read the
+history of another branch or tag.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		branch, _ := cmd.Flags().GetString("branch")
+		limit, _ := cmd.Flags().GetInt("max-count")
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
 		}
 
+		entries, err := github.ProfileLog(name, cfg.GitHub.Repo, cfg.GitHub.Auth, branch, limit)
+		if err != nil {
+			return fmt.Errorf("log failed: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(entries)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Hash, e.Date, e.Author, e.Subject)
+		}
 		w.Flush()
 		return nil
 	},
 }
 
+// pullInto applies a remote profile straight from the sync cache into
+// targetDir/.opencode without touching the local store.
+func pullInto(name, targetDir string, cfg *config.Config, branch string, force, merge, dryRun bool) error {
+	if err := profile.ValidateName(name); err != nil {
+		return err
+	}
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("cannot resolve target directory: %w", err)
+	}
+	targetOpencode := filepath.Join(absTarget, ".opencode")
+
+	fmt.Printf("Applying remote profile %q from %s …\n", name, cfg.GitHub.Repo)
+
+	src, err := github.CachedProfileDir(name, cfg.GitHub.Repo, cfg.GitHub.Auth, branch)
+	if err != nil {
+		return fmt.Errorf("pull failed: %w", err)
+	}
+
+	var strategy copier.Strategy
+	switch {
+	case force:
+		strategy = copier.StrategyOverwrite
+	case merge:
+		strategy = copier.StrategyMerge
+	default:
+		strategy = copier.StrategyPrompt
+	}
+
+	opts := copier.Options{
+		Strategy:   strategy,
+		DryRun:     dryRun,
+		OnConflict: promptConflict(bufio.NewReader(os.Stdin), targetOpencode),
+	}
+
+	result, err := copier.CopyProfile(src, targetOpencode, opts)
+	if err != nil {
+		return fmt.Errorf("copying profile %q: %w", name, err)
+	}
+	prefix := ""
+	if dryRun {
+		prefix = "[dry run] "
+	}
+	printCopyResult(prefix, result)
+	return nil
+}
+
+// pullReplace is the default sync pull strategy: the local copy of the
+// profile is deleted and replaced by the remote one.
+const pullReplace copier.Strategy = "replace"
+
+// pullMerge merges the remote copy of a profile, as found in the sync
+// cache, into the local store using strategy, so local-only changes
+// survive. Every file of the remote copy takes part, as it does in a
+// replacing pull; the caller must have refreshed the cache.
+func pullMerge(name, storeDir string, strategy copier.Strategy, dryRun, force bool, reader *bufio.Reader) error {
+	if err := profile.ValidateName(name); err != nil {
+		return err
+	}
+	dst := filepath.Join(storeDir, name)
+	if !dryRun && !force {
+		if err := profile.CheckUnlocked(dst); err != nil {
+			return err
+		}
+	}
+
+	src, err := github.CachedProfilePath(name)
+	if err != nil {
+		return err
+	}
+
+	opts := copier.Options{
+		Strategy:   strategy,
+		DryRun:     dryRun,
+		OnConflict: promptConflict(reader, dst),
+	}
+
+	result := &copier.Result{}
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Like github.CopyDirRecursive, leave out git metadata and the
+		// hash manifest.
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == copier.ManifestFile {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		return mergeFile(rel, src, dst, opts, result)
+	})
+	if err != nil {
+		return fmt.Errorf("merging profile %q: %w", name, err)
+	}
+
+	prefix := ""
+	if dryRun {
+		prefix = "[dry run] "
+	}
+	printCopyResult(prefix, result)
+	if !dryRun {
+		github.MarkPulled(name)
+	}
+	return nil
+}
+
+// mergeFile applies the file rel of srcDir to dstDir with the conflict
+// handling of opts, recording the outcome in result.
+func mergeFile(rel, srcDir, dstDir string, opts copier.Options, result *copier.Result) error {
+	src := filepath.Join(srcDir, rel)
+	dst := filepath.Join(dstDir, rel)
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+
+	overwritten := false
+	if _, err := os.Stat(dst); err == nil {
+		equal, err := copier.FilesEqual(src, dst)
+		if err != nil {
+			return fmt.Errorf("comparing %s: %w", rel, err)
+		}
+		if equal {
+			if opts.DryRun {
+				result.Unchanged = append(result.Unchanged, rel)
+			}
+			return nil
+		}
+
+		write := opts.Strategy == copier.StrategyOverwrite
+		if opts.Strategy == copier.StrategyPrompt && opts.OnConflict != nil {
+		ask:
+			for {
+				choice, err := opts.OnConflict(src, dst)
+				if err != nil {
+					return err
+				}
+				switch choice {
+				case copier.ChoiceOverwrite:
+					write = true
+					break ask
+				case copier.ChoiceSkip:
+					break ask
+				case copier.ChoiceCancel:
+					return fmt.Errorf("pull cancelled")
+				}
+			}
+		}
+		if !write {
+			result.Skipped = append(result.Skipped, rel)
+			return nil
+		}
+		overwritten = true
+	}
+
+	if !opts.DryRun {
+		if err := copier.CopyFile(src, dst); err != nil {
+			result.Errors = append(result.Errors, copier.CopyError{Path: rel, Op: copier.OpCopy, Err: err})
+			return nil
+		}
+	}
+	result.Copied = append(result.Copied, rel)
+	if overwritten {
+		result.Overwritten = append(result.Overwritten, rel)
+	}
+	return nil
+}
+
 func init() {
+	syncPullCmd.Flags().String("strategy", string(pullReplace), "how to combine with an existing local copy: replace, overwrite, merge, prompt, or skip")
+	syncPushCmd.Flags().BoolP("dry-run", "d", false, "list the files that would be committed without committing or pushing")
+	syncPullCmd.Flags().BoolP("dry-run", "d", false, "list the files that would change without writing anything")
+	syncPushCmd.Flags().Bool("allow-protected", false, "push even if the target branch is listed in github.protected_branches")
 	syncPullCmd.Flags().Bool("all", false, "pull all remote profiles")
+	syncPullCmd.Flags().String("into", "", "apply the remote profile to this project instead of the store")
+	syncPullCmd.Flags().BoolP("force", "f", false, "with --into, overwrite existing files without prompting; otherwise also replace locked profiles")
+	syncPullCmd.Flags().BoolP("merge", "m", false, "with --into, only copy new files, skip existing ones")
+	syncPullCmd.Flags().StringP("branch", "b", "", "branch or tag to pull from (default: the repository's default branch)")
+	syncDiffCmd.Flags().StringP("branch", "b", "", "branch or tag to compare against (default: the repository's default branch)")
+	syncStatusCmd.Flags().StringP("branch", "b", "", "branch or tag to compare against (default: the repository's default branch)")
+	syncStatusCmd.Flags().String("since", "", "count new remote commits from this commit or tag instead of the last pull or push")
+	syncLogCmd.Flags().StringP("branch", "b", "", "branch or tag to read the history of (default: the repository's default branch)")
+	syncLogCmd.Flags().IntP("max-count", "n", 0, "show at most this many commits (0 for all)")
+	syncCloneCmd.Flags().StringP("branch", "b", "", "branch or tag to pull from (default: the repository's default branch)")
+	syncCloneCmd.Flags().BoolP("dry-run", "d", false, "list the files that would change without writing anything")
+	syncCloneCmd.Flags().BoolP("force", "f", false, "replace profiles that already exist locally, even locked ones")
 
 	syncCmd.AddCommand(syncPushCmd)
 	syncCmd.AddCommand(syncPullCmd)
+	syncCmd.AddCommand(syncCloneCmd)
 	syncCmd.AddCommand(syncStatusCmd)
+	syncCmd.AddCommand(syncDiffCmd)
+	syncCmd.AddCommand(syncLogCmd)
 }
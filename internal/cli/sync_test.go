@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acchapm1/ocmgr/internal/copier"
+	"github.com/acchapm1/ocmgr/internal/github"
+)
+
+func TestPullMergeCopiesProfileVerbatim(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cache := filepath.Join(home, "cache")
+	t.Setenv("OCMGR_CACHE_DIR", cache)
+
+	remote := writeTestProfile(t, filepath.Join(cache, "profiles"), "go")
+	extra := map[string]string{
+		"opencode.json":        `{"plugin": ["x"]}`,
+		".ocmgrignore":         "agents/a.md\n",
+		"agents/nested/b.md":   "# b\n",
+		copier.ManifestFile:    "not copied",
+		".git/HEAD":            "not copied",
+		"notes/outside-dir.md": "kept",
+	}
+	for rel, content := range extra {
+		path := filepath.Join(remote, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := filepath.Join(home, "store")
+	local := filepath.Join(store, "go")
+	if err := os.MkdirAll(local, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "local-only.md"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := bufio.NewReader(strings.NewReader(""))
+	if err := pullMerge("go", store, copier.StrategyOverwrite, false, false, reader); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"profile.toml", "opencode.json", ".ocmgrignore", "agents/a.md", "agents/nested/b.md", "notes/outside-dir.md", "local-only.md"} {
+		if _, err := os.Stat(filepath.Join(local, rel)); err != nil {
+			t.Errorf("%s missing after merge: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{copier.ManifestFile, ".git"} {
+		if _, err := os.Stat(filepath.Join(local, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s was copied", rel)
+		}
+	}
+}
+
+func TestReportPullAllPartialFailure(t *testing.T) {
+	pulled := []github.Pulled{{Name: "go"}, {Name: "rust"}}
+	pullErr := &github.PullError{Failed: map[string]error{"web": errors.New("locked")}}
+
+	err := reportPullAll(pulled, pullErr, false)
+	if err == nil || err.Error() != "pulled 2, 1 failed" {
+		t.Errorf("reportPullAll() = %v, want \"pulled 2, 1 failed\"", err)
+	}
+
+	if err := reportPullAll(pulled, nil, false); err != nil {
+		t.Errorf("reportPullAll() = %v with no failures", err)
+	}
+	if err := reportPullAll(nil, errors.New("clone failed"), false); err == nil || !strings.Contains(err.Error(), "clone failed") {
+		t.Errorf("reportPullAll() = %v, want the clone error", err)
+	}
+}
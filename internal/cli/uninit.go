@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/acchapm1/ocmgr/internal/copier"
+	"github.com/acchapm1/ocmgr/internal/provenance"
+	"github.com/acchapm1/ocmgr/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var uninitCmd = &cobra.Command{
+	Use:   "uninit [target-dir]",
+	Short: "Remove the files a profile added to .opencode",
+	Long: `Remove the files that "ocmgr init" copied into a .opencode directory,
+leaving everything else in place.
+
+By default every profile recorded in .opencode/.ocmgr-applied.toml is
+removed; pass --profile to remove only some of them. Only the files
+init recorded as written by those profiles are considered (every file
+the profile provides, for profiles applied by older versions), and a
+file is deleted only if it still matches the profile's copy in the
+store. Files that were changed locally are kept and reported.
+Directories left empty are removed, and the removed profiles are
+dropped from .ocmgr-applied.toml.
+
+opencode.json is never touched, even if init merged plugins or MCP
+servers into it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUninit,
+}
+
+func init() {
+	uninitCmd.Flags().StringSliceP("profile", "p", nil, "profile name(s) to remove (may be repeated; default: all recorded profiles)")
+	uninitCmd.Flags().BoolP("dry-run", "d", false, "list the files that would be removed without deleting them")
+	_ = uninitCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+	rootCmd.AddCommand(uninitCmd)
+}
+
+func runUninit(cmd *cobra.Command, args []string) error {
+	names, _ := cmd.Flags().GetStringSlice("profile")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	targetDir := "."
+	if len(args) == 1 {
+		targetDir = args[0]
+	}
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("cannot resolve target directory: %w", err)
+	}
+	targetOpencode := filepath.Join(absTarget, ".opencode")
+
+	prov, err := provenance.Load(targetOpencode)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		for _, e := range prov.Profiles {
+			names = append(names, e.Name)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no profiles recorded in %s; pass --profile to name them",
+				filepath.Join(targetOpencode, provenance.FileName))
+		}
+	}
+
+	s, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("cannot open store: %w", err)
+	}
+
+	// Later profiles override earlier ones, so compare each destination
+	// path with the last profile that provides it, as init would have
+	// left it.
+	opts := copier.Options{Strategy: copier.StrategyOverwrite, DryRun: true}
+	sources := make(map[string]string)
+	for _, name := range names {
+		p, err := s.Get(name)
+		if err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		result, err := copier.CopyProfile(p.Path, targetOpencode, opts)
+		if err != nil {
+			return fmt.Errorf("reading profile %q: %w", name, err)
+		}
+		var recorded map[string]bool
+		if e := prov.Find(name); e != nil && len(e.Files) > 0 {
+			recorded = make(map[string]bool, len(e.Files))
+			for _, f := range e.Files {
+				recorded[filepath.FromSlash(f)] = true
+			}
+		}
+		for _, rel := range result.Files() {
+			if recorded == nil || recorded[rel] {
+				sources[rel] = filepath.Join(p.Path, rel)
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(sources))
+	for rel := range sources {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	prefix := ""
+	if dryRun {
+		prefix = "[dry run] "
+	}
+
+	var removed, kept int
+	for _, rel := range paths {
+		dst := filepath.Join(targetOpencode, rel)
+		if _, err := os.Lstat(dst); os.IsNotExist(err) {
+			continue
+		}
+
+		equal, err := copier.FilesEqual(sources[rel], dst)
+		if err != nil {
+			return fmt.Errorf("comparing %s: %w", rel, err)
+		}
+		if !equal {
+			fmt.Fprintf(os.Stderr, "Warning: %s was modified locally; keeping it\n", rel)
+			kept++
+			continue
+		}
+
+		if !dryRun {
+			if err := os.Remove(dst); err != nil {
+				return fmt.Errorf("removing %s: %w", rel, err)
+			}
+			removeEmptyParents(filepath.Dir(dst), targetOpencode)
+		}
+		fmt.Printf("%sremoved %s\n", prefix, rel)
+		removed++
+	}
+
+	if !dryRun {
+		for _, name := range names {
+			prov.Remove(name)
+		}
+		if len(prov.Profiles) > 0 {
+			if err := prov.Save(targetOpencode); err != nil {
+				return err
+			}
+		} else if err := os.Remove(filepath.Join(targetOpencode, provenance.FileName)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", provenance.FileName, err)
+		}
+	}
+
+	fmt.Printf("%s%d files removed, %d modified files kept\n", prefix, removed, kept)
+	return nil
+}
+
+// removeEmptyParents removes dir and each of its parents that are empty,
+// stopping at root, which is never removed.
+func removeEmptyParents(dir, root string) {
+	for dir != root && len(dir) > len(root) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/acchapm1/ocmgr/internal/config"
 	"github.com/acchapm1/ocmgr/internal/updater"
 	"github.com/spf13/cobra"
 )
@@ -19,17 +22,50 @@ If a version is specified, update to that specific version:
 
 Note: This command only works for installations done via the curl
 installer. For Homebrew installations, use: brew upgrade ocmgr
-For Go installations, use: go install github.com/acchapm1/ocmgr/cmd/ocmgr@latest`,
+For Go installations, use: go install github.com/acchapm1/ocmgr/cmd/ocmgr@latest
+
+The downloaded archive is verified against the release's checksums.txt
+before anything is installed; if the checksum is missing or does not
+match, the update is aborted and the current binary is left in place.
+--skip-checksum disables this check.
+
+Before installing, the release notes are shown and you are asked to
+confirm; pass --yes to install without asking.
+
+By default only stable releases are considered. --prerelease, or
+setting defaults.update_channel to "prerelease", also offers release
+candidates and betas; versions are ordered by semver precedence, so
+v0.3.0 is newer than v0.3.0-rc.2.
+
+The binary that was replaced is kept next to the executable as
+ocmgr.prev. Run "ocmgr update --rollback" to swap it back in if the new
+version misbehaves.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runUpdate,
 }
 
 func init() {
+	updateCmd.Flags().Bool("rollback", false, "restore the binary replaced by the last update")
+	updateCmd.Flags().BoolP("yes", "y", false, "install without asking for confirmation")
+	updateCmd.Flags().Bool("prerelease", false, "include prereleases when looking for the latest version")
+	updateCmd.Flags().Bool("skip-checksum", false, "install without verifying the release checksum (not recommended)")
 	rootCmd.AddCommand(updateCmd)
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
 	u := updater.New(Version)
+	u.SetStatus(func(msg string) { fmt.Println(msg) })
+	skipChecksum, _ := cmd.Flags().GetBool("skip-checksum")
+	u.SetSkipChecksum(skipChecksum)
+	rollback, _ := cmd.Flags().GetBool("rollback")
+
+	prerelease, _ := cmd.Flags().GetBool("prerelease")
+	if !cmd.Flags().Changed("prerelease") {
+		if cfg, err := config.Load(); err == nil {
+			prerelease = cfg.Defaults.UpdateChannel == "prerelease"
+		}
+	}
+	u.SetPrerelease(prerelease)
 
 	// Detect installation method
 	method := updater.DetectInstallMethod()
@@ -51,6 +87,21 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if rollback {
+		if len(args) > 0 {
+			return fmt.Errorf("--rollback does not take a version argument")
+		}
+		previous, err := u.Rollback()
+		if err != nil {
+			return fmt.Errorf("rollback failed: %w", err)
+		}
+		if previous == "" {
+			previous = "the previous version"
+		}
+		fmt.Printf("✓ Rolled back ocmgr from %s to %s\n", Version, previous)
+		return nil
+	}
+
 	// Determine target version
 	var targetVersion string
 	if len(args) == 1 {
@@ -90,10 +141,56 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Available version: %s\n", release.TagName)
 	fmt.Println()
 
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		printReleaseNotes(release)
+		fmt.Print("Install? [y/N] ")
+		reader := bufio.NewReader(os.Stdin)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Aborted.")
+			return nil
+		}
+		fmt.Println()
+	}
+
 	// Perform update
 	if err := u.Update(release); err != nil {
 		return fmt.Errorf("update failed: %w", err)
 	}
+	fmt.Printf("✓ Updated ocmgr to %s\n", release.TagName)
 
 	return nil
 }
+
+// maxNotesLines is the number of release-note lines shown before the
+// notes are cut short.
+const maxNotesLines = 40
+
+// printReleaseNotes prints the notes of release, truncated to
+// maxNotesLines with a pointer to the full notes on GitHub.
+func printReleaseNotes(release *updater.Release) {
+	body := strings.TrimSpace(strings.ReplaceAll(release.Body, "\r\n", "\n"))
+	if body == "" {
+		fmt.Println("No release notes were published for this version.")
+		fmt.Println()
+		return
+	}
+
+	lines := strings.Split(body, "\n")
+	truncated := len(lines) > maxNotesLines
+	if truncated {
+		lines = lines[:maxNotesLines]
+	}
+
+	fmt.Printf("Release notes for %s:\n\n", release.TagName)
+	for _, line := range lines {
+		fmt.Println("  " + line)
+	}
+	if truncated {
+		fmt.Println("  ...")
+		fmt.Println()
+		fmt.Printf("See the full notes at %s\n", release.HTMLURL)
+	}
+	fmt.Println()
+}
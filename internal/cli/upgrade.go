@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/acchapm1/ocmgr/internal/copier"
+	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/provenance"
+	"github.com/acchapm1/ocmgr/internal/store"
+	"github.com/acchapm1/ocmgr/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [target-dir]",
+	Short: "Re-apply profiles that have a newer version in the store",
+	Long: `Re-apply every profile recorded in .opencode/.ocmgr-applied.toml whose
+version in the local store is newer than the version that was applied.
+
+Files are handled according to what happened to them since init:
+
+  - files the profile adds are copied
+  - files still exactly as init wrote them are replaced with the new
+    version
+  - files changed in the project are conflicts: you are asked whether
+    to overwrite or keep each one, as with "ocmgr init"
+
+Templates are rendered with the --var values recorded when the profile
+was applied. Files a later profile overrode are left alone. Profiles
+applied by versions of ocmgr that did not record file hashes treat
+every changed file as a conflict. Use --dry-run to see what would
+happen; conflicts are then reported but nothing is asked.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().StringSliceP("profile", "p", nil, "only upgrade these profiles (may be repeated)")
+	upgradeCmd.Flags().BoolP("dry-run", "d", false, "show what would be upgraded without changing anything")
+	_ = upgradeCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	only, _ := cmd.Flags().GetStringSlice("profile")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	targetDir := "."
+	if len(args) == 1 {
+		targetDir = args[0]
+	}
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("cannot resolve target directory: %w", err)
+	}
+	targetOpencode := filepath.Join(absTarget, ".opencode")
+
+	prov, err := provenance.Load(targetOpencode)
+	if err != nil {
+		return err
+	}
+	if len(prov.Profiles) == 0 {
+		return fmt.Errorf("no profiles recorded in %s", filepath.Join(targetOpencode, provenance.FileName))
+	}
+	for _, name := range only {
+		if prov.Find(name) == nil {
+			return fmt.Errorf("profile %q is not applied to %s", name, targetOpencode)
+		}
+	}
+
+	s, err := store.NewStore()
+	if err != nil {
+		return fmt.Errorf("cannot open store: %w", err)
+	}
+
+	// A file written by several layered profiles belongs to the last one.
+	owner := make(map[string]string)
+	for _, e := range prov.Profiles {
+		for _, f := range e.Files {
+			owner[f] = e.Name
+		}
+	}
+
+	prefix := ""
+	if dryRun {
+		prefix = "[dry run] "
+	}
+	prompt := promptConflict(bufio.NewReader(os.Stdin), targetOpencode)
+
+	upgraded := 0
+	for _, e := range slices.Clone(prov.Profiles) {
+		if len(only) > 0 && !slices.Contains(only, e.Name) {
+			continue
+		}
+		p, err := s.Get(e.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %q: %v\n", e.Name, err)
+			continue
+		}
+		if !newerVersion(p.Version, e.Version) {
+			continue
+		}
+		upgraded++
+
+		fmt.Printf("%sUpgrading %q %s → %s …\n", prefix, e.Name, displayVersion(e.Version), p.Version)
+
+		// current collects files that already match the new version, so
+		// their recorded hash can be refreshed; conflicts collects files
+		// that were changed in the project.
+		current := make(map[string]string)
+		var conflicts []string
+		opts := copier.Options{
+			Strategy: copier.StrategyPrompt,
+			DryRun:   dryRun,
+			Vars:     e.Vars,
+			OnConflict: func(src, dst string) (copier.ConflictChoice, error) {
+				rel, _ := filepath.Rel(targetOpencode, dst)
+				key := filepath.ToSlash(rel)
+				if o, ok := owner[key]; ok && o != e.Name {
+					return copier.ChoiceSkip, nil
+				}
+				if eq, err := copier.FilesEqual(src, dst); err == nil && eq {
+					current[rel], _ = copier.FileHash(dst)
+					return copier.ChoiceSkip, nil
+				}
+				if hash, err := copier.FileHash(dst); err == nil && hash != "" && hash == e.Hashes[key] {
+					return copier.ChoiceOverwrite, nil
+				}
+				conflicts = append(conflicts, rel)
+				if dryRun {
+					return copier.ChoiceSkip, nil
+				}
+				return prompt(src, dst)
+			},
+		}
+
+		result, err := copier.CopyProfile(p.Path, targetOpencode, opts)
+		if err != nil {
+			return fmt.Errorf("upgrading profile %q: %w", e.Name, err)
+		}
+		// A dry run reports files that already match in result.Unchanged
+		// rather than asking OnConflict about them.
+		unchanged := len(current)
+		for _, rel := range result.Unchanged {
+			if o, ok := owner[filepath.ToSlash(rel)]; !ok || o == e.Name {
+				unchanged++
+			}
+		}
+		printUpgradeResult(prefix, result, unchanged, conflicts, dryRun)
+
+		if !dryRun {
+			hashes := writtenHashes(targetOpencode, result.Copied)
+			for rel, hash := range current {
+				hashes[rel] = hash
+			}
+			prov.Record(e.Name, p.Version, time.Now().UTC().Truncate(time.Second), hashes)
+			if err := prov.Save(targetOpencode); err != nil {
+				return err
+			}
+		}
+	}
+
+	if upgraded == 0 {
+		fmt.Println("All applied profiles are up to date.")
+	}
+	return nil
+}
+
+// printUpgradeResult summarises the upgrade of one profile: the files
+// written, how many already matched, and what happened to each file that
+// had been changed in the project.
+func printUpgradeResult(prefix string, result *copier.Result, unchanged int, conflicts []string, dryRun bool) {
+	if len(result.Copied) > 0 {
+		fmt.Printf("%s✓ Updated %d files\n", prefix, len(result.Copied))
+		for _, f := range result.Copied {
+			fmt.Printf("    %s\n", f)
+		}
+	}
+	if unchanged > 0 {
+		fmt.Printf("%s→ %d files already up to date\n", prefix, unchanged)
+	}
+	if len(conflicts) > 0 {
+		fmt.Printf("%s! %d files changed locally\n", prefix, len(conflicts))
+		for _, f := range conflicts {
+			outcome := "kept"
+			switch {
+			case dryRun:
+				outcome = "would ask"
+			case slices.Contains(result.Copied, f):
+				outcome = "overwritten"
+			}
+			fmt.Printf("    %s (%s)\n", f, outcome)
+		}
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("%s✗ %d errors\n", prefix, len(result.Errors))
+		for _, e := range result.Errors {
+			fmt.Printf("    %s\n", e)
+		}
+	}
+}
+
+// newerVersion reports whether candidate, the version of a profile in
+// the store, is newer than current, the version that was applied. Any
+// version is newer than none; versions that are not semantic versions
+// are only compared for equality.
+func newerVersion(candidate, current string) bool {
+	switch {
+	case candidate == "":
+		return false
+	case current == "":
+		return true
+	case profile.ValidateVersion(candidate) == nil && profile.ValidateVersion(current) == nil:
+		return updater.CompareVersions(candidate, current) > 0
+	}
+	return candidate != current
+}
@@ -1,10 +1,14 @@
-// Package config manages the global ocmgr configuration file (~/.ocmgr/config.toml).
+// Package config manages the ocmgr configuration: the global file
+// (config.toml in ConfigDir) and optional per-project .ocmgr.toml overrides.
 package config
 
 import (
 	"bytes"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -15,14 +19,59 @@ type Config struct {
 	GitHub   GitHub   `toml:"github"`
 	Defaults Defaults `toml:"defaults"`
 	Store    Store    `toml:"store"`
+	Theme    Theme    `toml:"theme"`
+	// ProfileSets maps a name to an ordered list of profiles that
+	// "init --profile-set <name>" applies, as if each were passed with
+	// --profile.
+	ProfileSets map[string][]string `toml:"profile_sets"`
+}
+
+// ProfileSet returns the profiles of the named profile set, in order.
+func (c *Config) ProfileSet(name string) ([]string, error) {
+	profiles, ok := c.ProfileSets[name]
+	if !ok {
+		if len(c.ProfileSets) == 0 {
+			return nil, fmt.Errorf("unknown profile set %q; none are configured (see: ocmgr config set profile_sets.<name> <profiles>)", name)
+		}
+		names := make([]string, 0, len(c.ProfileSets))
+		for n := range c.ProfileSets {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown profile set %q; configured sets: %s", name, strings.Join(names, ", "))
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("profile set %q is empty", name)
+	}
+	return profiles, nil
 }
 
 // GitHub holds settings for the remote profile repository.
 type GitHub struct {
 	// Repo is the owner/repo slug on GitHub (e.g. "acchapm1/opencode-profiles").
 	Repo string `toml:"repo"`
+	// Host is the git host serving Repo. It defaults to "github.com" but
+	// may be any host that serves repositories at <host>/<owner>/<repo>,
+	// such as a self-hosted GitLab or GitHub Enterprise instance.
+	Host string `toml:"host"`
 	// Auth is the authentication method: "gh", "env", "ssh", or "token".
 	Auth string `toml:"auth"`
+	// Branch is the branch "sync push" commits to. Empty means the
+	// repository's default branch.
+	Branch string `toml:"branch"`
+	// ProtectedBranches lists remote branches that "sync push" refuses to
+	// push to unless --allow-protected is given.
+	ProtectedBranches []string `toml:"protected_branches"`
+}
+
+// IsProtectedBranch reports whether branch is listed in ProtectedBranches.
+func (g GitHub) IsProtectedBranch(branch string) bool {
+	for _, b := range g.ProtectedBranches {
+		if b == branch {
+			return true
+		}
+	}
+	return false
 }
 
 // Defaults holds user-facing default behaviours.
@@ -30,8 +79,23 @@ type Defaults struct {
 	// MergeStrategy controls how conflicting files are handled.
 	// One of "prompt", "overwrite", "merge", or "skip".
 	MergeStrategy string `toml:"merge_strategy"`
-	// Editor is the command used to open files for editing.
+	// Editor is the command used to open files for editing, optionally
+	// with arguments (e.g. "code --wait"). When empty, $EDITOR is used.
 	Editor string `toml:"editor"`
+	// MaxFiles is the number of files a single init may copy before
+	// the user is asked to confirm. Zero disables the check.
+	MaxFiles int `toml:"max_files"`
+	// MaxBytes is the total size in bytes a single init may copy before
+	// the user is asked to confirm. Zero disables the check.
+	MaxBytes int64 `toml:"max_bytes"`
+	// UpdateChannel selects the releases "ocmgr update" considers:
+	// "stable" (the default) or "prerelease" to include release
+	// candidates and betas.
+	UpdateChannel string `toml:"update_channel"`
+	// ContentDirs lists the profile subdirectories that are copied into
+	// .opencode/ and captured by snapshots. Add a name here to manage a
+	// directory opencode gains support for before ocmgr knows about it.
+	ContentDirs []string `toml:"content_dirs"`
 }
 
 // Store holds settings for the local profile store.
@@ -39,6 +103,28 @@ type Store struct {
 	// Path is the directory where downloaded profiles are kept.
 	// The "~" prefix is expanded to the user's home directory at runtime.
 	Path string `toml:"path"`
+	// CacheDir is the directory holding the sync cache (a clone of the
+	// remote profile repository). It can be overridden with the
+	// OCMGR_CACHE_DIR environment variable.
+	CacheDir string `toml:"cache_dir"`
+}
+
+// Theme holds the colours of the interactive TUI.
+type Theme struct {
+	// Name is the built-in theme to start from: "dark" (the default) or
+	// "light".
+	Name string `toml:"name"`
+	// The remaining keys replace single colours of the named theme. Each
+	// is a hex colour ("#7C3AED") or an ANSI colour number ("5"); empty
+	// keeps the theme's own colour.
+	Primary    string `toml:"primary"`
+	Secondary  string `toml:"secondary"`
+	Success    string `toml:"success"`
+	Warning    string `toml:"warning"`
+	Error      string `toml:"error"`
+	Muted      string `toml:"muted"`
+	Text       string `toml:"text"`
+	Background string `toml:"background"`
 }
 
 // DefaultConfig returns a Config populated with sensible defaults.
@@ -46,39 +132,221 @@ func DefaultConfig() *Config {
 	return &Config{
 		GitHub: GitHub{
 			Repo: "acchapm1/opencode-profiles",
+			Host: "github.com",
 			Auth: "gh",
 		},
 		Defaults: Defaults{
 			MergeStrategy: "prompt",
-			Editor:        "nvim",
+			MaxFiles:      5000,
+			MaxBytes:      100 << 20, // 100 MiB
+			UpdateChannel: "stable",
+			ContentDirs:   []string{"agents", "commands", "skills", "plugins"},
 		},
 		Store: Store{
-			Path: "~/.ocmgr/profiles",
+			Path:     tildePath(filepath.Join(DataDir(), "profiles")),
+			CacheDir: tildePath(DefaultCacheDir()),
+		},
+		Theme: Theme{
+			Name: DefaultThemeName,
 		},
 	}
 }
 
-// ConfigDir returns the absolute path to the ocmgr configuration directory
-// (~/.ocmgr with the tilde expanded).
-func ConfigDir() string {
+// EditorCommand returns the command line used to open files for
+// editing, split into the program and its arguments: Defaults.Editor
+// when set, otherwise $EDITOR, otherwise nvim or vi, whichever is
+// installed.
+func (c *Config) EditorCommand() []string {
+	if args := SplitCommand(c.Defaults.Editor); len(args) > 0 {
+		return args
+	}
+	if args := SplitCommand(os.Getenv("EDITOR")); len(args) > 0 {
+		return args
+	}
+	if _, err := exec.LookPath("nvim"); err == nil {
+		return []string{"nvim"}
+	}
+	return []string{"vi"}
+}
+
+// SplitCommand splits a command line into words on whitespace. Single
+// or double quotes group words containing spaces, as in
+// `"/Applications/Sublime Text.app/Contents/MacOS/subl" --wait`.
+func SplitCommand(s string) []string {
+	var (
+		args   []string
+		cur    strings.Builder
+		inWord bool
+		quote  rune
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args
+}
+
+// homeDir returns the user's home directory.
+func homeDir() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		// Fall back to the HOME env var; if that is also empty the caller
 		// will get a relative path, which is the best we can do.
 		home = os.Getenv("HOME")
 	}
-	return filepath.Join(home, ".ocmgr")
+	return home
+}
+
+// LegacyDir returns ~/.ocmgr, where ocmgr keeps everything unless the
+// XDG base directory variables are set.
+func LegacyDir() string {
+	return filepath.Join(homeDir(), ".ocmgr")
+}
+
+// xdgDir returns the ocmgr directory under the XDG base directory named
+// by the environment variable env, or "" if it is unset. Relative
+// values are ignored, as the XDG specification requires.
+func xdgDir(env string) string {
+	base := os.Getenv(env)
+	if base == "" || !filepath.IsAbs(base) {
+		return ""
+	}
+	return filepath.Join(base, "ocmgr")
+}
+
+// baseDir returns the directory to use for one XDG category: its XDG
+// directory once that exists, otherwise an existing ~/.ocmgr so that
+// installs from before XDG support keep working, otherwise the XDG
+// directory if its variable is set, otherwise ~/.ocmgr.
+func baseDir(env string) string {
+	xdg := xdgDir(env)
+	if xdg == "" {
+		return LegacyDir()
+	}
+	if _, err := os.Stat(xdg); err == nil {
+		return xdg
+	}
+	if _, err := os.Stat(LegacyDir()); err == nil {
+		return LegacyDir()
+	}
+	return xdg
+}
+
+// ConfigDir returns the absolute path to the ocmgr configuration
+// directory: $XDG_CONFIG_HOME/ocmgr when XDG_CONFIG_HOME is set,
+// otherwise ~/.ocmgr (see baseDir for how an existing ~/.ocmgr is kept).
+func ConfigDir() string {
+	return baseDir("XDG_CONFIG_HOME")
+}
+
+// DataDir returns the absolute path to the directory holding the profile
+// store and sync cache by default: $XDG_DATA_HOME/ocmgr when
+// XDG_DATA_HOME is set, otherwise ~/.ocmgr.
+func DataDir() string {
+	return baseDir("XDG_DATA_HOME")
+}
+
+// tildePath abbreviates a path inside the home directory to start with
+// "~", the form the default paths are written to config.toml in.
+func tildePath(path string) string {
+	home := homeDir()
+	if home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(filepath.Join("~", rel))
+	}
+	return path
 }
 
 // ConfigPath returns the absolute path to the ocmgr configuration file
-// (~/.ocmgr/config.toml).
+// (config.toml in ConfigDir).
 func ConfigPath() string {
 	return filepath.Join(ConfigDir(), "config.toml")
 }
 
-// Load reads the configuration from disk. If the file does not exist the
-// default configuration is returned without an error.
+// CacheDir returns the absolute path to the sync cache directory. The
+// OCMGR_CACHE_DIR environment variable takes precedence over the
+// store.cache_dir config key; if neither is set (or the config cannot be
+// loaded) DefaultCacheDir is used.
+func CacheDir() string {
+	if dir := os.Getenv("OCMGR_CACHE_DIR"); dir != "" {
+		return ExpandPath(dir)
+	}
+	if cfg, err := Load(); err == nil && cfg.Store.CacheDir != "" {
+		return ExpandPath(cfg.Store.CacheDir)
+	}
+	return DefaultCacheDir()
+}
+
+// DefaultCacheDir returns the sync cache location used when none is
+// configured: .sync-cache in DataDir.
+func DefaultCacheDir() string {
+	return filepath.Join(DataDir(), ".sync-cache")
+}
+
+// ProjectFile is the name of the per-project configuration file. It may
+// set the keys in ProjectKeys, which override the global values.
+const ProjectFile = ".ocmgr.toml"
+
+// ProjectKeys are the keys a project file may set. A project file comes
+// with whatever repository was cloned, so only keys that shape how
+// profiles are applied are allowed: nothing that picks a command to
+// run, a host to send credentials to, or a directory to write to.
+var ProjectKeys = []string{
+	"defaults.merge_strategy",
+	"defaults.max_files",
+	"defaults.max_bytes",
+	"defaults.content_dirs",
+}
+
+// projectConfig is the part of Config a project file is decoded into;
+// it holds exactly the keys in ProjectKeys.
+type projectConfig struct {
+	Defaults struct {
+		MergeStrategy *string  `toml:"merge_strategy"`
+		MaxFiles      *int     `toml:"max_files"`
+		MaxBytes      *int64   `toml:"max_bytes"`
+		ContentDirs   []string `toml:"content_dirs"`
+	} `toml:"defaults"`
+}
+
+// Load returns the configuration in effect in the working directory:
+// the global config.toml with any project file overlaid (see
+// LoadForDir).
 func Load() (*Config, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return LoadGlobal()
+	}
+	return LoadForDir(dir)
+}
+
+// LoadGlobal reads the global ConfigPath file only. If the file does not
+// exist the default configuration is returned without an error. It is
+// the config that "config set" modifies and saves back.
+func LoadGlobal() (*Config, error) {
 	cfg := DefaultConfig()
 
 	data, err := os.ReadFile(ConfigPath())
@@ -92,11 +360,103 @@ func Load() (*Config, error) {
 	if _, err := toml.Decode(string(data), cfg); err != nil {
 		return nil, err
 	}
+	if err := ValidateContentDirs(cfg.Defaults.ContentDirs); err != nil {
+		return nil, fmt.Errorf("%s: defaults.content_dirs: %w", ConfigPath(), err)
+	}
 
 	return cfg, nil
 }
 
-// Save writes cfg to ~/.ocmgr/config.toml, creating the configuration
+// LoadForDir reads the global configuration and overlays the project
+// file that applies to dir (see FindProjectFile). Only the keys set in
+// the project file are overridden; everything else keeps its global
+// value. A project file setting a key outside ProjectKeys is an error.
+func LoadForDir(dir string) (*Config, error) {
+	cfg, err := LoadGlobal()
+	if err != nil {
+		return nil, err
+	}
+
+	path := FindProjectFile(dir)
+	if path == "" {
+		return cfg, nil
+	}
+	var project projectConfig
+	md, err := toml.DecodeFile(path, &project)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return nil, fmt.Errorf("%s: %s cannot be set in a project file; allowed keys: %s",
+			path, strings.Join(keys, ", "), strings.Join(ProjectKeys, ", "))
+	}
+
+	if v := project.Defaults.MergeStrategy; v != nil {
+		cfg.Defaults.MergeStrategy = *v
+	}
+	if v := project.Defaults.MaxFiles; v != nil {
+		cfg.Defaults.MaxFiles = *v
+	}
+	if v := project.Defaults.MaxBytes; v != nil {
+		cfg.Defaults.MaxBytes = *v
+	}
+	if md.IsDefined("defaults", "content_dirs") {
+		if err := ValidateContentDirs(project.Defaults.ContentDirs); err != nil {
+			return nil, fmt.Errorf("%s: defaults.content_dirs: %w", path, err)
+		}
+		cfg.Defaults.ContentDirs = project.Defaults.ContentDirs
+	}
+	return cfg, nil
+}
+
+// ValidateContentDirs checks a defaults.content_dirs list: each entry
+// must be a plain directory name (no path separators, not hidden, so
+// neither absolute nor "..") and appear only once.
+func ValidateContentDirs(dirs []string) error {
+	seen := make(map[string]bool)
+	for _, d := range dirs {
+		switch {
+		case d == "":
+			return fmt.Errorf("empty content directory name")
+		case strings.ContainsAny(d, `/\`) || strings.HasPrefix(d, ".") || filepath.IsAbs(d):
+			return fmt.Errorf("invalid content directory %q; must be a plain directory name", d)
+		case seen[d]:
+			return fmt.Errorf("content directory %q listed twice", d)
+		}
+		seen[d] = true
+	}
+	return nil
+}
+
+// FindProjectFile looks for a .ocmgr.toml in dir and its parents,
+// stopping at the root of the git repository containing dir, and returns
+// its path, or "" if there is none.
+func FindProjectFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Save writes cfg to ConfigPath, creating the configuration
 // directory if it does not already exist.
 func Save(cfg *Config) error {
 	if err := EnsureConfigDir(); err != nil {
@@ -112,8 +472,8 @@ func Save(cfg *Config) error {
 	return os.WriteFile(ConfigPath(), buf.Bytes(), 0o644)
 }
 
-// EnsureConfigDir creates the ~/.ocmgr directory (and any parents) if it does
-// not already exist.
+// EnsureConfigDir creates the configuration directory (and any parents) if
+// it does not already exist.
 func EnsureConfigDir() error {
 	return os.MkdirAll(ConfigDir(), 0o755)
 }
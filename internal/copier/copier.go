@@ -8,9 +8,15 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Strategy controls how file conflicts are resolved when copying a profile
@@ -30,6 +36,17 @@ const (
 	StrategySkip Strategy = "skip"
 )
 
+// Mode controls how profile files are placed in the target directory.
+type Mode string
+
+const (
+	// ModeCopy writes a copy of each profile file. It is the default.
+	ModeCopy Mode = "copy"
+	// ModeLink creates a symlink to each profile file instead, so edits
+	// made in the target directory change the profile itself.
+	ModeLink Mode = "link"
+)
+
 // ConflictChoice represents a per-file decision returned by the OnConflict
 // callback when the strategy is StrategyPrompt.
 type ConflictChoice int
@@ -61,30 +78,77 @@ type Options struct {
 	// StrategyPrompt, conflicting files are skipped.
 	OnConflict func(src, dst string) (ConflictChoice, error)
 	// IncludeDirs, when non-empty, restricts copying to only the listed
-	// content directories (e.g. ["agents", "skills"]).  It is mutually
-	// exclusive with ExcludeDirs.
+	// content directories (e.g. ["agents", "skills"]). Entries containing
+	// a "/" or a wildcard are treated as globs matched against the path
+	// relative to the profile root (e.g. "skills/python-*"); "**" matches
+	// any number of directories. A file is copied if it matches any entry.
 	IncludeDirs []string
 	// ExcludeDirs, when non-empty, skips the listed content directories
-	// during copying (e.g. ["plugins"]).  It is mutually exclusive with
-	// IncludeDirs.
+	// or globs during copying (e.g. ["plugins", "agents/experimental-*"]).
+	// Exclusions are applied after IncludeDirs and take precedence.
 	ExcludeDirs []string
+	// BackupDir, when non-empty, is the directory that existing files are
+	// copied to (preserving their relative path) before being overwritten.
+	// Files whose contents would not change are not backed up.
+	BackupDir string
+	// FollowSymlinks, when true, copies the file a symlink points to
+	// instead of re-creating the link at the destination.
+	FollowSymlinks bool
+	// Concurrency is the number of files written in parallel. It only
+	// applies to strategies that never prompt; StrategyPrompt always copies
+	// serially. Zero means runtime.NumCPU() and 1 disables parallelism.
+	Concurrency int
+	// Vars maps placeholder names to values. When non-empty, every
+	// "{{key}}" in text files (see TemplateExts) is replaced by its value
+	// as the file is copied. Other files are copied unchanged.
+	Vars map[string]string
+	// Mode selects whether files are copied (ModeCopy, the default when
+	// empty) or symlinked to their absolute path in the profile
+	// (ModeLink). Vars are not applied to linked files. Existing files
+	// are resolved through Strategy either way; a destination that is
+	// already a link to the same profile file is skipped silently.
+	Mode Mode
+	// Progress, when set, is called after each selected file has been
+	// handled (copied, skipped, or failed) with the number handled so far
+	// and the total number of files CopyProfile will handle. It may be
+	// called from several goroutines, but never concurrently.
+	Progress func(done, total int)
 }
 
 // Result summarises the outcome of a CopyProfile invocation.
 type Result struct {
 	// Copied lists the destination paths of files that were (or would be)
 	// written.
-	Copied []string
+	Copied []string `json:"copied"`
+	// Overwritten lists the subset of Copied that replaced a file which
+	// already existed in the target.
+	Overwritten []string `json:"overwritten"`
 	// Skipped lists the destination paths of files that already existed and
 	// were not overwritten.
-	Skipped []string
-	// Errors lists human-readable descriptions of files that could not be
-	// processed.
-	Errors []string
+	Skipped []string `json:"skipped"`
+	// Unchanged lists, in a dry run, the destination paths of files that
+	// already exist with the same contents, whatever the strategy. They
+	// are not in Copied or Skipped.
+	Unchanged []string `json:"unchanged"`
+	// Errors lists the files that could not be processed together with
+	// the operation that failed.
+	Errors CopyErrors `json:"errors"`
+	// Notes lists informational messages about files that were handled
+	// in a non-obvious way (e.g. a symlink that was copied as a file).
+	Notes []string `json:"notes"`
+	// Bytes is the total size of the files listed in Copied.
+	Bytes int64 `json:"bytes"`
+	// BackedUp lists the relative paths of files that were saved to
+	// BackupDir before being overwritten.
+	BackedUp []string `json:"backed_up"`
+	// BackupDir is the directory the files in BackedUp were saved to. It
+	// is empty if nothing was backed up.
+	BackupDir string `json:"backup_dir,omitempty"`
 }
 
 // profileDirs is the set of top-level directories inside a profile that are
 // copied into .opencode/. Everything else (notably profile.toml) is ignored.
+// SetContentDirs replaces it.
 var profileDirs = map[string]bool{
 	"agents":   true,
 	"commands": true,
@@ -92,13 +156,29 @@ var profileDirs = map[string]bool{
 	"plugins":  true,
 }
 
+// SetContentDirs replaces the content directories that are copied and
+// accepted by IncludeDirs and ExcludeDirs, for the defaults.content_dirs
+// setting. An empty list restores the built-in four.
+func SetContentDirs(dirs []string) {
+	if len(dirs) == 0 {
+		dirs = []string{"agents", "commands", "skills", "plugins"}
+	}
+	profileDirs = make(map[string]bool, len(dirs))
+	ValidContentDirs = make(map[string]bool, len(dirs))
+	for _, d := range dirs {
+		profileDirs[d] = true
+		ValidContentDirs[d] = true
+	}
+}
+
 // profileFiles is the set of root-level files (no path separators)
 // inside a profile that are copied into .opencode/. Only filenames
 // directly under the profile root are supported; nested paths will
 // be silently skipped because their parent directory is not in
 // profileDirs.
 // Note: opencode.json is NOT copied - it is generated dynamically
-// during init based on user's plugin and MCP selections.
+// during init based on user's plugin and MCP selections, and a copy
+// stored in the profile is merged into it rather than overwriting it.
 var profileFiles = map[string]bool{}
 
 // errCancelled is returned when the user chooses ChoiceCancel during an
@@ -117,22 +197,97 @@ func CopyProfile(profileDir, targetDir string, opts Options) (*Result, error) {
 		opts.Strategy = StrategyOverwrite
 	}
 
-	// Build lookup sets for include/exclude filtering.
-	includeSet := toSet(opts.IncludeDirs)
-	excludeSet := toSet(opts.ExcludeDirs)
+	logger.Printf("copying profile %s → %s (strategy %s)", profileDir, targetDir, opts.Strategy)
+
+	// Split include/exclude entries into bare directories and globs.
+	filter := newDirFilter(opts.IncludeDirs, opts.ExcludeDirs)
+
+	replacer := newVarReplacer(opts.Vars)
+
+	// Paths listed in the profile's .ocmgrignore are never copied.
+	ignore, err := LoadIgnore(profileDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", IgnoreFile, err)
+	}
 
 	result := &Result{}
 
-	err := filepath.WalkDir(profileDir, func(path string, d fs.DirEntry, walkErr error) error {
+	// On case-insensitive filesystems (the default on macOS and Windows)
+	// "agents/Foo.md" and "agents/foo.md" are the same file. Detect this
+	// once per run so conflicts are matched case-insensitively.
+	foldCase := detectCaseInsensitive(targetDir)
+	seen := make(map[string]string)
+
+	// Files are written as the walk finds them, except for strategies
+	// that never prompt: there the writes are queued and handed to a
+	// worker pool once the walk is done. mu guards result while the
+	// workers run.
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	parallel := workers > 1 && !opts.DryRun && opts.Strategy != StrategyPrompt
+	var mu sync.Mutex
+	var jobs []copyJob
+
+	addErr := func(path, op string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Errors = append(result.Errors, CopyError{Path: path, Op: op, Err: err})
+	}
+
+	// record adds a successfully written (or, in a dry run, would-be
+	// written) file to result.
+	record := func(rel string, overwritten bool, size int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Copied = append(result.Copied, rel)
+		if overwritten {
+			result.Overwritten = append(result.Overwritten, rel)
+		}
+		result.Bytes += size
+	}
+
+	// tick reports one more file as processed to opts.Progress. The total
+	// is counted up front with a separate walk, only when it is needed.
+	total, done := 0, 0
+	if opts.Progress != nil {
+		total = countFiles(profileDir, filter, ignore)
+	}
+	tick := func() {
+		if opts.Progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		opts.Progress(done, total)
+	}
+
+	// apply performs (or queues) a write and records it on success.
+	apply := func(job copyJob) {
+		switch {
+		case opts.DryRun:
+			record(job.rel, job.overwritten, job.size)
+		case parallel:
+			jobs = append(jobs, job)
+		default:
+			if job.run() {
+				record(job.rel, job.overwritten, job.size)
+			}
+		}
+	}
+
+	err = filepath.WalkDir(profileDir, func(path string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, walkErr))
+			addErr(path, OpWalk, walkErr)
 			return nil // continue walking
 		}
 
 		// Compute the path relative to the profile root.
 		rel, err := filepath.Rel(profileDir, path)
 		if err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, err))
+			addErr(path, OpWalk, err)
 			return nil
 		}
 
@@ -141,69 +296,135 @@ func CopyProfile(profileDir, targetDir string, opts Options) (*Result, error) {
 			return nil
 		}
 
-		// Determine the top-level component (e.g. "skills" from
-		// "skills/analyzing-projects/SKILL.md").
-		topLevel := strings.SplitN(rel, string(filepath.Separator), 2)[0]
-
-		// Only descend into recognised profile directories.
-		// Skip everything else (notably profile.toml and any other
-		// root-level files).
-		if !profileDirs[topLevel] {
-			if d.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil // skip loose files like profile.toml
+		include, skipDir := selectPath(rel, d.IsDir(), filter, ignore)
+		if skipDir {
+			return filepath.SkipDir
+		}
+		if !include {
+			return nil
 		}
 
-		// Apply include/exclude directory filtering.
-		if len(includeSet) > 0 && !includeSet[topLevel] {
-			if d.IsDir() {
-				return filepath.SkipDir
+		// Every selected file counts once towards progress: when its
+		// write is queued, after the worker has run it, otherwise as
+		// soon as this callback returns.
+		queued := len(jobs)
+		defer func() {
+			if len(jobs) == queued {
+				tick()
 			}
-			return nil
+		}()
+
+		src := path
+		dst := filepath.Join(targetDir, rel)
+
+		// write copies src to dst. Symlinks are re-created at the
+		// destination unless they point outside the profile or the
+		// caller asked to follow them. Text files are rendered through
+		// the replacer when variables are set.
+		write := CopyFile
+		templated := replacer != nil && isTemplate(rel)
+		if templated {
+			write = func(src, dst string) error { return CopyTemplate(src, dst, replacer) }
 		}
-		if len(excludeSet) > 0 && excludeSet[topLevel] {
-			if d.IsDir() {
-				return filepath.SkipDir
+		if opts.Mode == ModeLink {
+			absSrc, err := filepath.Abs(src)
+			if err != nil {
+				addErr(rel, OpWalk, err)
+				return nil
+			}
+			if target, err := os.Readlink(dst); err == nil && target == absSrc {
+				result.Skipped = append(result.Skipped, rel)
+				return nil
+			}
+			templated = false
+			write = func(_, dst string) error { return CopySymlink(absSrc, dst) }
+		} else if d.Type()&fs.ModeSymlink != 0 && !opts.FollowSymlinks {
+			target, inside, err := linkTarget(profileDir, path)
+			if err != nil {
+				addErr(rel, OpReadlink, err)
+				return nil
+			}
+			if inside {
+				templated = false
+				write = func(_, dst string) error { return CopySymlink(target, dst) }
+			} else {
+				result.Notes = append(result.Notes, fmt.Sprintf("%s: symlink points outside the profile; copied the target file", rel))
 			}
-			return nil
 		}
 
-		// Nothing to copy for directories themselves; they are created
-		// implicitly by CopyFile.
-		if d.IsDir() {
-			return nil
+		// overwrite replaces an existing dst, first saving a backup of it
+		// when requested and its contents actually change. Failures are
+		// recorded in result and reported as false.
+		overwrite := func(src, dst string) bool {
+			if opts.BackupDir != "" {
+				if eq, err := FilesEqual(src, dst); templated || err != nil || !eq {
+					backupRel, _ := filepath.Rel(targetDir, dst)
+					if err := CopyFile(dst, filepath.Join(opts.BackupDir, backupRel)); err != nil {
+						addErr(rel, OpBackup, fmt.Errorf("backup: %w", err))
+						return false
+					}
+					mu.Lock()
+					result.BackedUp = append(result.BackedUp, backupRel)
+					result.BackupDir = opts.BackupDir
+					mu.Unlock()
+				}
+			}
+			if err := write(src, dst); err != nil {
+				addErr(rel, OpCopy, err)
+				return false
+			}
+			return true
 		}
 
-		src := path
-		dst := filepath.Join(targetDir, rel)
+		// Two profile files that differ only in case would silently
+		// clobber each other on a case-insensitive target.
+		if foldCase {
+			key := strings.ToLower(rel)
+			if prev, ok := seen[key]; ok {
+				addErr(rel, OpCollision, fmt.Errorf("collides with %s on a case-insensitive filesystem", prev))
+				return nil
+			}
+			seen[key] = rel
+		}
 
 		// Check whether the destination already exists.
-		_, statErr := os.Stat(dst)
-		exists := statErr == nil
+		existing, exists := destExists(dst, foldCase)
+		if exists && existing != dst {
+			// Report and resolve the conflict against the file that is
+			// actually on disk rather than creating a case-variant twin.
+			dst = existing
+		}
 
 		if !exists {
 			// New file — always copy.
-			if !opts.DryRun {
-				if err := CopyFile(src, dst); err != nil {
-					result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
-					return nil
+			apply(copyJob{rel: rel, size: fileSize(d), run: func() bool {
+				if err := write(src, dst); err != nil {
+					addErr(rel, OpCopy, err)
+					return false
 				}
-			}
-			result.Copied = append(result.Copied, rel)
+				return true
+			}})
 			return nil
 		}
 
+		// A dry run tells files that would change apart from those that
+		// already match. Links and rendered templates would be written
+		// differently from their source, so they are never unchanged.
+		if opts.DryRun && opts.Mode != ModeLink && !templated && (d.Type()&fs.ModeSymlink == 0 || opts.FollowSymlinks) {
+			if eq, err := FilesEqual(src, dst); err == nil && eq {
+				result.Unchanged = append(result.Unchanged, rel)
+				return nil
+			}
+		}
+
+		replace := copyJob{rel: rel, overwritten: true, size: fileSize(d), run: func() bool {
+			return overwrite(src, dst)
+		}}
+
 		// File exists — apply conflict strategy.
 		switch opts.Strategy {
 		case StrategyOverwrite:
-			if !opts.DryRun {
-				if err := CopyFile(src, dst); err != nil {
-					result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
-					return nil
-				}
-			}
-			result.Copied = append(result.Copied, rel)
+			apply(replace)
 
 		case StrategyMerge, StrategySkip:
 			result.Skipped = append(result.Skipped, rel)
@@ -214,19 +435,13 @@ func CopyProfile(profileDir, targetDir string, opts Options) (*Result, error) {
 				if errors.Is(err, errCancelled) {
 					return err
 				}
-				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
+				addErr(rel, OpResolve, err)
 				return nil
 			}
 
 			switch choice {
 			case ChoiceOverwrite:
-				if !opts.DryRun {
-					if err := CopyFile(src, dst); err != nil {
-						result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
-						return nil
-					}
-				}
-				result.Copied = append(result.Copied, rel)
+				apply(replace)
 			case ChoiceSkip:
 				result.Skipped = append(result.Skipped, rel)
 			case ChoiceCancel:
@@ -245,9 +460,198 @@ func CopyProfile(profileDir, targetDir string, opts Options) (*Result, error) {
 		return result, err
 	}
 
+	if len(jobs) > 0 {
+		runJobs(jobs, workers, record, tick)
+
+		// Workers finish in arbitrary order; sort so summaries are
+		// deterministic.
+		sort.Strings(result.Copied)
+		sort.Strings(result.Overwritten)
+		sort.Strings(result.BackedUp)
+		sort.Slice(result.Errors, func(i, j int) bool {
+			return result.Errors[i].Path < result.Errors[j].Path
+		})
+	}
+
 	return result, err
 }
 
+// Files returns the destination paths of every file the profile would
+// write: those in Copied and, in a dry run, those in Unchanged.
+func (r *Result) Files() []string {
+	files := append(slices.Clone(r.Copied), r.Unchanged...)
+	sort.Strings(files)
+	return files
+}
+
+// selectPath decides how the walk treats the entry at rel (relative to
+// the profile root): whether it is a file to copy, and whether it is a
+// directory that can be skipped entirely.
+func selectPath(rel string, isDir bool, filter *dirFilter, ignore *Ignore) (include, skipDir bool) {
+	if ignore.Match(rel, isDir) {
+		return false, isDir
+	}
+
+	// Determine the top-level component (e.g. "skills" from
+	// "skills/analyzing-projects/SKILL.md").
+	topLevel := strings.SplitN(rel, string(filepath.Separator), 2)[0]
+
+	// Only descend into recognised profile directories. Skip everything
+	// else (notably profile.toml and any other root-level files).
+	if !profileDirs[topLevel] {
+		return false, isDir
+	}
+
+	// Apply include/exclude filtering. Whole top-level directories are
+	// skipped up front; globs are checked per path below.
+	if filter.skipTopLevel(topLevel) {
+		return false, isDir
+	}
+
+	// Nothing to copy for directories themselves; they are created
+	// implicitly by CopyFile.
+	if isDir {
+		return false, filter.excludedDir(rel)
+	}
+
+	return filter.includeFile(rel), false
+}
+
+// countFiles returns the number of files under profileDir that
+// CopyProfile would select.
+func countFiles(profileDir string, filter *dirFilter, ignore *Ignore) int {
+	n := 0
+	_ = filepath.WalkDir(profileDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(profileDir, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		include, skipDir := selectPath(rel, d.IsDir(), filter, ignore)
+		if skipDir {
+			return filepath.SkipDir
+		}
+		if include {
+			n++
+		}
+		return nil
+	})
+	return n
+}
+
+// copyJob is a single pending file write.
+type copyJob struct {
+	rel         string
+	overwritten bool
+	size        int64
+	// run performs the write, recording any error itself, and reports
+	// whether it succeeded.
+	run func() bool
+}
+
+// runJobs executes jobs on a pool of workers and calls record for every
+// job that succeeds, and done after every job.
+func runJobs(jobs []copyJob, workers int, record func(rel string, overwritten bool, size int64), done func()) {
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	queue := make(chan copyJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if job.run() {
+					record(job.rel, job.overwritten, job.size)
+				}
+				done()
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// detectCaseInsensitive reports whether the filesystem holding dir treats
+// names case-insensitively. It is a variable so it can be overridden when
+// exercising case-insensitive behaviour on a case-sensitive machine.
+var detectCaseInsensitive = isCaseInsensitiveFS
+
+// isCaseInsensitiveFS probes the nearest existing ancestor of dir without
+// writing anything: it looks for an entry whose name contains letters and
+// checks whether the same name with its case swapped also resolves. If no
+// suitable entry is found it falls back to the platform default.
+func isCaseInsensitiveFS(dir string) bool {
+	for {
+		entries, err := os.ReadDir(dir)
+		if err == nil {
+			for _, e := range entries {
+				swapped := swapCase(e.Name())
+				if swapped == e.Name() {
+					continue
+				}
+				_, err := os.Stat(filepath.Join(dir, swapped))
+				return err == nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+}
+
+// swapCase inverts the case of every ASCII letter in s.
+func swapCase(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		switch {
+		case c >= 'a' && c <= 'z':
+			b[i] = c - 'a' + 'A'
+		case c >= 'A' && c <= 'Z':
+			b[i] = c - 'A' + 'a'
+		}
+	}
+	return string(b)
+}
+
+// destExists reports whether dst exists. When foldCase is true and the
+// exact path is missing, the parent directory is searched for an entry
+// whose name matches case-insensitively; the returned path is the one
+// actually present on disk.
+func destExists(dst string, foldCase bool) (string, bool) {
+	if !foldCase {
+		_, err := os.Lstat(dst)
+		return dst, err == nil
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(dst))
+	if err != nil {
+		return dst, false
+	}
+	base := filepath.Base(dst)
+	for _, e := range entries {
+		if e.Name() == base {
+			return dst, true
+		}
+	}
+	for _, e := range entries {
+		if strings.EqualFold(e.Name(), base) {
+			return filepath.Join(filepath.Dir(dst), e.Name()), true
+		}
+	}
+	return dst, false
+}
+
 // resolveConflict invokes the OnConflict callback, handling the ChoiceCompare
 // loop (show diff, then re-prompt). If cb is nil the file is skipped.
 func resolveConflict(src, dst string, cb func(string, string) (ConflictChoice, error)) (ConflictChoice, error) {
@@ -274,17 +678,61 @@ func resolveConflict(src, dst string, cb func(string, string) (ConflictChoice, e
 	}
 }
 
-// CopyFile copies the file at src to dst, creating any necessary parent
-// directories. The original file permissions are preserved.
-func CopyFile(src, dst string) error {
-	info, err := os.Stat(src)
+// linkTarget reads the symlink at path and reports the target to use when
+// re-creating it, and whether that target lies inside profileDir. Targets
+// inside the profile are always returned relative to the link's directory
+// so the re-created link keeps working at its new location.
+func linkTarget(profileDir, path string) (string, bool, error) {
+	target, err := os.Readlink(path)
 	if err != nil {
-		return fmt.Errorf("stat source: %w", err)
+		return "", false, fmt.Errorf("read symlink: %w", err)
+	}
+
+	linkDir := filepath.Dir(path)
+	resolved := target
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(linkDir, resolved)
+	}
+	resolved = filepath.Clean(resolved)
+
+	fromRoot, err := filepath.Rel(profileDir, resolved)
+	if err != nil || fromRoot == ".." || strings.HasPrefix(fromRoot, ".."+string(filepath.Separator)) {
+		return "", false, nil
+	}
+
+	relTarget, err := filepath.Rel(linkDir, resolved)
+	if err != nil {
+		return "", false, nil
 	}
+	return relTarget, true, nil
+}
 
+// CopySymlink creates a symlink at dst pointing to target, creating any
+// necessary parent directories and replacing whatever is already at dst.
+func CopySymlink(target, dst string) error {
 	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
 		return fmt.Errorf("create parent dirs: %w", err)
 	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove existing destination: %w", err)
+	}
+	if err := os.Symlink(target, dst); err != nil {
+		return fmt.Errorf("create symlink: %w", err)
+	}
+	return nil
+}
+
+// CopyFile copies the file at src to dst, creating any necessary parent
+// directories. The original file permissions and modification time are
+// preserved. If dst is a symlink it is replaced rather than written
+// through. The copy is atomic: see writeAtomic.
+func CopyFile(src, dst string) error {
+	logger.Printf("copy %s → %s", src, dst)
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("stat source: %w", err)
+	}
 
 	in, err := os.Open(src)
 	if err != nil {
@@ -292,17 +740,78 @@ func CopyFile(src, dst string) error {
 	}
 	defer in.Close()
 
-	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	err = writeAtomic(dst, info.Mode().Perm(), func(w io.Writer) error {
+		if _, err := io.Copy(w, in); err != nil {
+			return fmt.Errorf("copy data: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("create destination: %w", err)
+		return err
 	}
 
-	if _, err := io.Copy(out, in); err != nil {
+	// Keeping the mtime is best-effort: some filesystems refuse it, and
+	// the copy itself has succeeded.
+	if err := os.Chtimes(dst, time.Now(), info.ModTime()); err != nil {
+		logger.Printf("preserving mtime of %s: %v", dst, err)
+	}
+	return nil
+}
+
+// writeAtomic writes dst through write, creating any necessary parent
+// directories. The data goes to a sibling temporary file
+// (<dst>.ocmgr-tmp-XXXXXXXX) that is renamed over dst once complete, so an
+// interrupted write leaves either the old file or the new one, never a
+// partial one. The temporary file is removed on error.
+//
+// Permissions match a plain create-or-truncate: a new file gets perm
+// (less the umask) and an existing regular file keeps its mode. An
+// existing symlink at dst is replaced, not written through.
+func writeAtomic(dst string, perm fs.FileMode, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("create parent dirs: %w", err)
+	}
+
+	out, err := createSibling(dst, perm)
+	if err != nil {
+		return fmt.Errorf("create destination: %w", err)
+	}
+	tmp := out.Name()
+	fail := func(err error) error {
 		out.Close()
-		return fmt.Errorf("copy data: %w", err)
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := write(out); err != nil {
+		return fail(err)
+	}
+	if fi, err := os.Lstat(dst); err == nil && fi.Mode().IsRegular() {
+		if err := out.Chmod(fi.Mode().Perm()); err != nil {
+			return fail(fmt.Errorf("set permissions: %w", err))
+		}
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("replace destination: %w", err)
 	}
+	return nil
+}
 
-	return out.Close()
+// createSibling creates a new, uniquely named file next to dst with the
+// given permissions.
+func createSibling(dst string, perm fs.FileMode) (*os.File, error) {
+	for {
+		name := fmt.Sprintf("%s.ocmgr-tmp-%08x", dst, rand.Uint32())
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if !os.IsExist(err) {
+			return f, err
+		}
+	}
 }
 
 // FilesEqual reports whether the files at paths a and b have identical
@@ -371,6 +880,16 @@ var ValidContentDirs = map[string]bool{
 	"plugins":  true,
 }
 
+// fileSize returns the size of the file behind d, or 0 if it cannot be
+// determined.
+func fileSize(d fs.DirEntry) int64 {
+	info, err := d.Info()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
 // toSet converts a string slice into a lookup map.
 func toSet(items []string) map[string]bool {
 	if len(items) == 0 {
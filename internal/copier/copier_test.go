@@ -0,0 +1,232 @@
+package copier
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// writeTree creates each file below root with the given contents.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// foldCase makes CopyProfile treat the target as case-insensitive.
+func foldCase(t *testing.T) {
+	t.Helper()
+	orig := detectCaseInsensitive
+	detectCaseInsensitive = func(string) bool { return true }
+	t.Cleanup(func() { detectCaseInsensitive = orig })
+}
+
+func TestCopyProfileCaseInsensitiveConflict(t *testing.T) {
+	foldCase(t)
+
+	for _, tc := range []struct {
+		strategy    Strategy
+		wantContent string
+	}{
+		{StrategySkip, "mine"},
+		{StrategyOverwrite, "profile"},
+	} {
+		t.Run(string(tc.strategy), func(t *testing.T) {
+			tmp := t.TempDir()
+			src := filepath.Join(tmp, "profile")
+			target := filepath.Join(tmp, ".opencode")
+			writeTree(t, src, map[string]string{"profile.toml": "", "agents/Foo.md": "profile"})
+			writeTree(t, target, map[string]string{"agents/foo.md": "mine"})
+
+			result, err := CopyProfile(src, target, Options{Strategy: tc.strategy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Errors) > 0 {
+				t.Fatalf("errors: %v", result.Errors)
+			}
+			switch tc.strategy {
+			case StrategySkip:
+				if len(result.Skipped) != 1 || result.Skipped[0] != "agents/Foo.md" {
+					t.Errorf("Skipped = %v, want [agents/Foo.md]", result.Skipped)
+				}
+			case StrategyOverwrite:
+				if len(result.Overwritten) != 1 || result.Overwritten[0] != "agents/Foo.md" {
+					t.Errorf("Overwritten = %v, want [agents/Foo.md]", result.Overwritten)
+				}
+			}
+
+			entries, err := os.ReadDir(filepath.Join(target, "agents"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || entries[0].Name() != "foo.md" {
+				t.Fatalf("target agents/ holds %v, want only foo.md", entries)
+			}
+			got, err := os.ReadFile(filepath.Join(target, "agents", "foo.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.wantContent {
+				t.Errorf("agents/foo.md = %q, want %q", got, tc.wantContent)
+			}
+		})
+	}
+}
+
+func TestCopyProfileCaseInsensitiveCollision(t *testing.T) {
+	foldCase(t)
+
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "profile")
+	writeTree(t, src, map[string]string{"profile.toml": "", "agents/A.md": "upper", "agents/a.md": "lower"})
+
+	result, err := CopyProfile(src, filepath.Join(tmp, ".opencode"), Options{Strategy: StrategyOverwrite})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Copied) != 1 {
+		t.Errorf("Copied = %v, want one of the two files", result.Copied)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Op != OpCollision {
+		t.Errorf("Errors = %v, want one %s error", result.Errors, OpCollision)
+	}
+}
+
+func TestCopyProfilePermissionError(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission bits are not enforced for root")
+	}
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "profile")
+	target := filepath.Join(tmp, ".opencode")
+	writeTree(t, src, map[string]string{"profile.toml": "", "agents/a.md": "a", "commands/c.md": "c"})
+	if err := os.MkdirAll(filepath.Join(target, "agents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(target, "agents"), 0o555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(filepath.Join(target, "agents"), 0o755) })
+
+	result, err := CopyProfile(src, target, Options{Strategy: StrategyOverwrite, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Copied) != 1 || result.Copied[0] != filepath.Join("commands", "c.md") {
+		t.Errorf("Copied = %v, want only commands/c.md", result.Copied)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Errors = %v, want one", result.Errors)
+	}
+	e := result.Errors[0]
+	if e.Path != filepath.Join("agents", "a.md") || e.Op != OpCopy || e.Kind() != KindPermission {
+		t.Errorf("error = {%s %s %s}, want {agents/a.md %s %s}", e.Path, e.Op, e.Kind(), OpCopy, KindPermission)
+	}
+	if !errors.Is(e, fs.ErrPermission) {
+		t.Errorf("errors.Is(%v, fs.ErrPermission) = false", e)
+	}
+}
+
+func TestCopyErrorKindAndJSON(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		kind string
+	}{
+		{&fs.PathError{Op: "open", Path: "/x", Err: syscall.EACCES}, KindPermission},
+		{&fs.PathError{Op: "open", Path: "/x", Err: syscall.ENOENT}, KindNotExist},
+		{&fs.PathError{Op: "write", Path: "/x", Err: syscall.ENOSPC}, KindNoSpace},
+		{errors.New("boom"), KindOther},
+	} {
+		e := CopyError{Path: "agents/a.md", Op: OpCopy, Err: tc.err}
+		if got := e.Kind(); got != tc.kind {
+			t.Errorf("Kind(%v) = %s, want %s", tc.err, got, tc.kind)
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"path":"agents/a.md","op":"copy","kind":"` + tc.kind + `","error":` + strconv.Quote(tc.err.Error()) + `}`
+		if string(data) != want {
+			t.Errorf("JSON = %s, want %s", data, want)
+		}
+	}
+}
+
+func TestCopyFilePreservesModTime(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src.md")
+	dst := filepath.Join(tmp, "out", "dst.md")
+	if err := os.WriteFile(src, []byte("content"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2020, 5, 17, 12, 30, 0, 0, time.UTC)
+	if err := os.Chtimes(src, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CopyFile(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := info.ModTime().Sub(mtime).Abs(); d > time.Second {
+		t.Errorf("mtime = %v, want %v (within a second)", info.ModTime(), mtime)
+	}
+	if info.Size() != int64(len("content")) || info.Mode().Perm() != 0o640 {
+		t.Errorf("size %d, mode %v; want %d, 0640", info.Size(), info.Mode().Perm(), len("content"))
+	}
+}
+
+func TestCopyProfileLinkMode(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "profile")
+	target := filepath.Join(tmp, ".opencode")
+	writeTree(t, src, map[string]string{"profile.toml": "", "agents/a.md": "a", "agents/b.md": "b"})
+	writeTree(t, target, map[string]string{"agents/b.md": "mine"})
+
+	var asked []string
+	opts := Options{Strategy: StrategyPrompt, Mode: ModeLink, OnConflict: func(_, dst string) (ConflictChoice, error) {
+		asked = append(asked, filepath.Base(dst))
+		return ChoiceSkip, nil
+	}}
+	result, err := CopyProfile(src, target, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(asked) != 1 || asked[0] != "b.md" {
+		t.Errorf("prompted for %v, want [b.md]", asked)
+	}
+	if got, err := os.Readlink(filepath.Join(target, "agents", "a.md")); err != nil || got != filepath.Join(src, "agents", "a.md") {
+		t.Errorf("agents/a.md links to %q (%v)", got, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(target, "agents", "b.md")); err != nil || string(data) != "mine" {
+		t.Errorf("agents/b.md = %q, %v; want the skipped real file", data, err)
+	}
+
+	// Running again finds the link in place and neither prompts nor
+	// rewrites it.
+	asked = nil
+	result, err = CopyProfile(src, target, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(asked) != 1 || len(result.Copied) != 0 || len(result.Skipped) != 2 {
+		t.Errorf("second run: prompted %v, Copied %v, Skipped %v", asked, result.Copied, result.Skipped)
+	}
+}
@@ -0,0 +1,85 @@
+package copier
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"syscall"
+)
+
+// Operations recorded in CopyError.Op.
+const (
+	OpWalk      = "walk"      // reading the profile tree
+	OpReadlink  = "readlink"  // resolving a symlink in the profile
+	OpCollision = "collision" // two profile files map to the same target
+	OpResolve   = "resolve"   // asking how to handle a conflict
+	OpBackup    = "backup"    // saving the existing file before overwrite
+	OpCopy      = "copy"      // writing the file into the target
+)
+
+// Kinds returned by CopyError.Kind.
+const (
+	KindPermission = "permission"
+	KindNotExist   = "not_exist"
+	KindNoSpace    = "no_space"
+	KindOther      = "other"
+)
+
+// CopyError describes a single file that could not be processed.
+type CopyError struct {
+	// Path is the file path relative to the profile root (or the absolute
+	// path when the walk itself failed before a relative path was known).
+	Path string
+	// Op is the operation that failed, one of the Op* constants.
+	Op string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error formats the error as "<path>: <message>".
+func (e CopyError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e CopyError) Unwrap() error {
+	return e.Err
+}
+
+// Kind classifies the underlying error so callers can react to common
+// failure modes (e.g. retry after freeing disk space) without parsing
+// messages.
+func (e CopyError) Kind() string {
+	switch {
+	case errors.Is(e.Err, fs.ErrPermission):
+		return KindPermission
+	case errors.Is(e.Err, fs.ErrNotExist):
+		return KindNotExist
+	case errors.Is(e.Err, syscall.ENOSPC):
+		return KindNoSpace
+	default:
+		return KindOther
+	}
+}
+
+// MarshalJSON encodes the error as {"path", "op", "kind", "error"}.
+func (e CopyError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path  string `json:"path"`
+		Op    string `json:"op"`
+		Kind  string `json:"kind"`
+		Error string `json:"error"`
+	}{e.Path, e.Op, e.Kind(), e.Err.Error()})
+}
+
+// CopyErrors is the list of per-file failures collected by CopyProfile.
+type CopyErrors []CopyError
+
+// Strings returns the errors formatted as "<path>: <message>".
+func (errs CopyErrors) Strings() []string {
+	out := make([]string, len(errs))
+	for i, e := range errs {
+		out[i] = e.Error()
+	}
+	return out
+}
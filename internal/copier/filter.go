@@ -0,0 +1,168 @@
+package copier
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsPattern reports whether an IncludeDirs/ExcludeDirs entry is a path
+// glob (it contains a separator or a wildcard) rather than a bare content
+// directory name.
+func IsPattern(s string) bool {
+	return strings.ContainsAny(s, "/*?[")
+}
+
+// ValidatePattern checks that a path glob is well-formed.
+func ValidatePattern(pattern string) error {
+	for _, seg := range strings.Split(pattern, "/") {
+		if seg == "**" {
+			continue
+		}
+		if _, err := path.Match(seg, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dirFilter decides which files of a profile are copied, based on the
+// IncludeDirs and ExcludeDirs options. Each entry is either a bare content
+// directory name ("skills"), matched against the top-level directory, or
+// a glob ("skills/python-*", "agents/**/draft-*.md"), matched against the
+// slash-separated path relative to the profile root. A glob also matches
+// everything beneath a directory it matches.
+//
+// A file is included when there are no include entries or it matches any
+// of them (bare names and globs are combined). Exclude entries are applied
+// afterwards and always win.
+type dirFilter struct {
+	includeDirs     map[string]bool
+	includePatterns []string
+	excludeDirs     map[string]bool
+	excludePatterns []string
+}
+
+// newDirFilter splits the include and exclude lists into bare directory
+// names and glob patterns.
+func newDirFilter(include, exclude []string) *dirFilter {
+	f := &dirFilter{}
+	f.includeDirs, f.includePatterns = splitPatterns(include)
+	f.excludeDirs, f.excludePatterns = splitPatterns(exclude)
+	return f
+}
+
+// splitPatterns separates bare directory names from glob patterns.
+func splitPatterns(items []string) (map[string]bool, []string) {
+	var dirs []string
+	var patterns []string
+	for _, item := range items {
+		if IsPattern(item) {
+			patterns = append(patterns, strings.Trim(filepath.ToSlash(item), "/"))
+		} else {
+			dirs = append(dirs, item)
+		}
+	}
+	return toSet(dirs), patterns
+}
+
+// hasInclude reports whether any include entries were given.
+func (f *dirFilter) hasInclude() bool {
+	return len(f.includeDirs) > 0 || len(f.includePatterns) > 0
+}
+
+// skipTopLevel reports whether the whole top-level directory can be
+// skipped without looking at its contents. This is the fast path for
+// bare directory names.
+func (f *dirFilter) skipTopLevel(topLevel string) bool {
+	if f.excludeDirs[topLevel] {
+		return true
+	}
+	if !f.hasInclude() || f.includeDirs[topLevel] {
+		return false
+	}
+	// Only include patterns remain; keep the directory if any of them
+	// could match something inside it.
+	for _, p := range f.includePatterns {
+		first := strings.SplitN(p, "/", 2)[0]
+		if first == "**" {
+			return false
+		}
+		if ok, _ := path.Match(first, topLevel); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// excludedDir reports whether a directory (relative to the profile root)
+// matches an exclude pattern, so the walk can skip it entirely.
+func (f *dirFilter) excludedDir(rel string) bool {
+	return matchAny(f.excludePatterns, filepath.ToSlash(rel))
+}
+
+// includeFile reports whether the file at rel should be copied.
+func (f *dirFilter) includeFile(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	topLevel := strings.SplitN(rel, "/", 2)[0]
+
+	if f.excludeDirs[topLevel] || matchAny(f.excludePatterns, rel) {
+		return false
+	}
+	if !f.hasInclude() || f.includeDirs[topLevel] {
+		return true
+	}
+	return matchAny(f.includePatterns, rel)
+}
+
+// matchAny reports whether rel, or any directory above it, matches one of
+// patterns.
+func matchAny(patterns []string, rel string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	segs := strings.Split(rel, "/")
+	for _, p := range patterns {
+		psegs := strings.Split(p, "/")
+		for n := 1; n <= len(segs); n++ {
+			if matchSegments(psegs, segs[:n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments.
+func matchSegments(pattern, segs []string) bool {
+	if len(pattern) == 0 {
+		return len(segs) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pattern[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segs[1:])
+}
+
+// Includes reports whether the file at rel (relative to a profile root or
+// a .opencode/ directory) falls within the content directories selected
+// by opts.IncludeDirs and opts.ExcludeDirs.
+func (opts Options) Includes(rel string) bool {
+	topLevel := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+	if !profileDirs[topLevel] {
+		return false
+	}
+	return newDirFilter(opts.IncludeDirs, opts.ExcludeDirs).includeFile(rel)
+}
@@ -0,0 +1,136 @@
+package copier
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile is the name of the file, at the root of a profile or a
+// .opencode/ directory, that lists paths to leave out when copying.
+const IgnoreFile = ".ocmgrignore"
+
+// ignoreRule is one compiled line of an ignore file.
+type ignoreRule struct {
+	segs     []string // pattern split on "/"
+	negate   bool     // "!pattern" re-includes a path
+	dirOnly  bool     // "pattern/" only matches directories
+	anchored bool     // pattern contains a "/" and matches from the root
+}
+
+// Ignore matches paths against the gitignore-style patterns of an ignore
+// file. The zero value (and a nil *Ignore) ignores nothing.
+//
+// Supported syntax: blank lines and "#" comments are skipped; "!"
+// negates a pattern; a trailing "/" matches directories only; a pattern
+// containing another "/" is matched against the full path from the root,
+// otherwise against the name at any depth; "*", "?" and "[...]" match
+// within a path segment and "**" matches any number of segments. As in
+// git, later patterns override earlier ones and nothing inside an
+// ignored directory can be re-included.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+// LoadIgnore reads and compiles the ignore file in root. A missing file
+// yields an Ignore that matches nothing.
+func LoadIgnore(root string) (*Ignore, error) {
+	f, err := os.Open(filepath.Join(root, IgnoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Ignore{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	ig, err := ParseIgnore(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", IgnoreFile, err)
+	}
+	return ig, nil
+}
+
+// ParseIgnore compiles ignore patterns read from r, one per line.
+func ParseIgnore(r io.Reader) (*Ignore, error) {
+	ig := &Ignore{}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			// "\#" and "\!" escape a literal leading character.
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimLeft(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		if err := ValidatePattern(line); err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %w", lineNo, line, err)
+		}
+
+		rule.segs = strings.Split(line, "/")
+		ig.rules = append(ig.rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ig, nil
+}
+
+// Match reports whether the path rel (relative to the ignore file's
+// directory) is ignored, either itself or because a directory above it
+// is. isDir says whether rel is a directory.
+func (ig *Ignore) Match(rel string, isDir bool) bool {
+	if ig == nil || len(ig.rules) == 0 {
+		return false
+	}
+	segs := strings.Split(filepath.ToSlash(rel), "/")
+	for n := 1; n <= len(segs); n++ {
+		if ig.matchPath(segs[:n], n < len(segs) || isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath applies the rules to a single path; the last matching rule
+// decides.
+func (ig *Ignore) matchPath(segs []string, isDir bool) bool {
+	ignored := false
+	for _, r := range ig.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		var ok bool
+		if r.anchored {
+			ok = matchSegments(r.segs, segs)
+		} else {
+			ok = matchSegments(r.segs, segs[len(segs)-1:])
+		}
+		if ok {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,18 @@
+package copier
+
+import (
+	"io"
+	"log"
+)
+
+// logger receives a line for every file written. It discards everything
+// unless SetLogger is called, e.g. by the CLI's --verbose flag.
+var logger = log.New(io.Discard, "", 0)
+
+// SetLogger directs copier's diagnostic output to l. A nil l silences it.
+func SetLogger(l *log.Logger) {
+	if l == nil {
+		l = log.New(io.Discard, "", 0)
+	}
+	logger = l
+}
@@ -0,0 +1,142 @@
+package copier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestFile is the name of the per-profile hash manifest. It lives in
+// the profile root and is never copied or synced.
+const ManifestFile = ".ocmgr-manifest.json"
+
+// ManifestEntry records the content hash of a single file together with
+// the size and modification time it had when the hash was computed.
+type ManifestEntry struct {
+	Hash    string    `json:"hash"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+}
+
+// Manifest maps paths relative to a profile root to their ManifestEntry.
+type Manifest struct {
+	Files map[string]ManifestEntry `json:"files"`
+}
+
+// FileHash returns the hex-encoded SHA-256 digest of the file at path.
+func FileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// LoadManifest reads the manifest stored in dir. A missing or unreadable
+// manifest yields an empty one so callers can simply rebuild it.
+func LoadManifest(dir string) *Manifest {
+	m := &Manifest{Files: make(map[string]ManifestEntry)}
+
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return m
+	}
+	if err := json.Unmarshal(data, m); err != nil || m.Files == nil {
+		return &Manifest{Files: make(map[string]ManifestEntry)}
+	}
+	return m
+}
+
+// Save writes the manifest into dir.
+func (m *Manifest) Save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), append(data, '\n'), 0o644)
+}
+
+// UpdateManifest brings the manifest stored in dir up to date with the
+// files on disk and returns it. Only files whose size or modification
+// time changed since the manifest was written are re-hashed, so calling
+// it on an unchanged tree is cheap. .git directories and the manifest
+// itself are ignored. The refreshed manifest is saved back to dir on a
+// best-effort basis.
+func UpdateManifest(dir string) (*Manifest, error) {
+	prev := LoadManifest(dir)
+	next := &Manifest{Files: make(map[string]ManifestEntry)}
+	changed := false
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == ManifestFile {
+			return nil
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		if old, ok := prev.Files[rel]; ok && old.Size == info.Size() && old.ModTime.Equal(info.ModTime()) {
+			next.Files[rel] = old
+			return nil
+		}
+
+		hash, err := FileHash(path)
+		if err != nil {
+			return err
+		}
+		next.Files[rel] = ManifestEntry{Hash: hash, Size: info.Size(), ModTime: info.ModTime()}
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if changed || len(next.Files) != len(prev.Files) {
+		_ = next.Save(dir)
+	}
+	return next, nil
+}
+
+// Equal reports whether two manifests describe the same set of files with
+// identical contents.
+func (m *Manifest) Equal(other *Manifest) bool {
+	if len(m.Files) != len(other.Files) {
+		return false
+	}
+	for rel, e := range m.Files {
+		o, ok := other.Files[rel]
+		if !ok || o.Hash != e.Hash {
+			return false
+		}
+	}
+	return true
+}
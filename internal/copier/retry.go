@@ -0,0 +1,121 @@
+package copier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Retryable reports whether re-attempting the failed operation could
+// succeed, i.e. it failed while writing rather than because of how the
+// profile itself is laid out.
+func (e CopyError) Retryable() bool {
+	return e.Op == OpCopy || e.Op == OpBackup
+}
+
+// Retry re-attempts the retryable entries of failed, which must come from
+// a previous CopyProfile call with the same profileDir and targetDir. The
+// conflict decision made on the first attempt is not asked again: every
+// retried file is written, with a backup first when opts.BackupDir is set.
+// In ModeLink, though, a regular file found at the destination goes
+// through opts.Strategy again, since replacing it with a link makes later
+// edits change the profile.
+//
+// The returned Result lists the files that now succeeded in Copied, those
+// left in place in Skipped, and everything that still failed (including
+// non-retryable entries) in Errors.
+func Retry(profileDir, targetDir string, failed CopyErrors, opts Options) *Result {
+	result := &Result{}
+	replacer := newVarReplacer(opts.Vars)
+
+	for i, e := range failed {
+		if !e.Retryable() {
+			result.Errors = append(result.Errors, e)
+			continue
+		}
+
+		rel := e.Path
+		src := filepath.Join(profileDir, rel)
+		dst := filepath.Join(targetDir, rel)
+
+		info, err := os.Lstat(src)
+		if err != nil {
+			result.Errors = append(result.Errors, CopyError{Path: rel, Op: OpCopy, Err: err})
+			continue
+		}
+
+		write := CopyFile
+		if replacer != nil && isTemplate(rel) {
+			write = func(src, dst string) error { return CopyTemplate(src, dst, replacer) }
+		}
+		if opts.Mode == ModeLink {
+			absSrc, err := filepath.Abs(src)
+			if err != nil {
+				result.Errors = append(result.Errors, CopyError{Path: rel, Op: OpWalk, Err: err})
+				continue
+			}
+			if target, err := os.Readlink(dst); err == nil && target == absSrc {
+				result.Skipped = append(result.Skipped, rel)
+				continue
+			}
+			write = func(_, dst string) error { return CopySymlink(absSrc, dst) }
+		} else if info.Mode()&os.ModeSymlink != 0 && !opts.FollowSymlinks {
+			target, inside, err := linkTarget(profileDir, src)
+			if err != nil {
+				result.Errors = append(result.Errors, CopyError{Path: rel, Op: OpReadlink, Err: err})
+				continue
+			}
+			if inside {
+				write = func(_, dst string) error { return CopySymlink(target, dst) }
+			}
+		}
+
+		dstInfo, statErr := os.Lstat(dst)
+		exists := statErr == nil
+
+		if opts.Mode == ModeLink && exists && dstInfo.Mode()&os.ModeSymlink == 0 {
+			replace := opts.Strategy == StrategyOverwrite
+			if opts.Strategy == StrategyPrompt {
+				choice, err := resolveConflict(src, dst, opts.OnConflict)
+				if errors.Is(err, errCancelled) {
+					result.Errors = append(result.Errors, failed[i:]...)
+					return result
+				}
+				if err != nil {
+					result.Errors = append(result.Errors, CopyError{Path: rel, Op: OpResolve, Err: err})
+					continue
+				}
+				replace = choice == ChoiceOverwrite
+			}
+			if !replace {
+				result.Skipped = append(result.Skipped, rel)
+				continue
+			}
+		}
+
+		if exists && opts.BackupDir != "" {
+			if eq, err := FilesEqual(src, dst); err != nil || !eq {
+				if err := CopyFile(dst, filepath.Join(opts.BackupDir, rel)); err != nil {
+					result.Errors = append(result.Errors, CopyError{Path: rel, Op: OpBackup, Err: fmt.Errorf("backup: %w", err)})
+					continue
+				}
+				result.BackedUp = append(result.BackedUp, rel)
+				result.BackupDir = opts.BackupDir
+			}
+		}
+
+		if err := write(src, dst); err != nil {
+			result.Errors = append(result.Errors, CopyError{Path: rel, Op: OpCopy, Err: err})
+			continue
+		}
+
+		result.Copied = append(result.Copied, rel)
+		if exists {
+			result.Overwritten = append(result.Overwritten, rel)
+		}
+		result.Bytes += info.Size()
+	}
+
+	return result
+}
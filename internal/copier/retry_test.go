@@ -0,0 +1,122 @@
+package copier
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRetryAfterTransientFailure(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "profile")
+	target := filepath.Join(tmp, ".opencode")
+	writeTree(t, src, map[string]string{"profile.toml": "", "agents/a.md": "a", "commands/c.md": "c"})
+
+	// A file where agents/ should be makes the first write fail; it is
+	// gone by the time the copy is retried.
+	writeTree(t, target, map[string]string{"agents": "in the way"})
+
+	opts := Options{Strategy: StrategyOverwrite, Concurrency: 1}
+	result, err := CopyProfile(src, target, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Op != OpCopy {
+		t.Fatalf("Errors = %v, want one %s error", result.Errors, OpCopy)
+	}
+
+	if err := os.Remove(filepath.Join(target, "agents")); err != nil {
+		t.Fatal(err)
+	}
+	collision := CopyError{Path: "agents/A.md", Op: OpCollision, Err: errors.New("collides")}
+	retried := Retry(src, target, append(result.Errors, collision), opts)
+
+	if len(retried.Copied) != 1 || retried.Copied[0] != filepath.Join("agents", "a.md") {
+		t.Errorf("Copied = %v, want [agents/a.md]", retried.Copied)
+	}
+	if len(retried.Errors) != 1 || retried.Errors[0].Op != OpCollision {
+		t.Errorf("Errors = %v, want only the non-retryable collision", retried.Errors)
+	}
+	if got, err := os.ReadFile(filepath.Join(target, "agents", "a.md")); err != nil || string(got) != "a" {
+		t.Errorf("agents/a.md = %q, %v; want \"a\"", got, err)
+	}
+}
+
+// failLink runs a link-mode CopyProfile whose write of agents/a.md fails
+// because a file sits where agents/ should be, then removes the obstacle.
+func failLink(t *testing.T) (src, target string, failed CopyErrors) {
+	t.Helper()
+	tmp := t.TempDir()
+	src = filepath.Join(tmp, "profile")
+	target = filepath.Join(tmp, ".opencode")
+	writeTree(t, src, map[string]string{"profile.toml": "", "agents/a.md": "a"})
+	writeTree(t, target, map[string]string{"agents": "in the way"})
+
+	result, err := CopyProfile(src, target, Options{Strategy: StrategyPrompt, Mode: ModeLink})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Errors = %v, want one", result.Errors)
+	}
+	if err := os.Remove(filepath.Join(target, "agents")); err != nil {
+		t.Fatal(err)
+	}
+	return src, target, result.Errors
+}
+
+func TestRetryLinkMode(t *testing.T) {
+	src, target, failed := failLink(t)
+	dst := filepath.Join(target, "agents", "a.md")
+
+	retried := Retry(src, target, failed, Options{Strategy: StrategyPrompt, Mode: ModeLink})
+	if len(retried.Errors) != 0 || len(retried.Copied) != 1 {
+		t.Fatalf("Copied = %v, Errors = %v; want agents/a.md copied", retried.Copied, retried.Errors)
+	}
+	got, err := os.Readlink(dst)
+	if want := filepath.Join(src, "agents", "a.md"); err != nil || got != want {
+		t.Fatalf("agents/a.md links to %q (%v), want %q", got, err, want)
+	}
+
+	// A destination already linked to the profile file is left alone.
+	retried = Retry(src, target, failed, Options{Strategy: StrategyPrompt, Mode: ModeLink})
+	if len(retried.Skipped) != 1 || len(retried.Copied) != 0 {
+		t.Errorf("second retry: Copied = %v, Skipped = %v; want it skipped", retried.Copied, retried.Skipped)
+	}
+}
+
+func TestRetryLinkModePromptsForRealFile(t *testing.T) {
+	for _, tc := range []struct {
+		choice ConflictChoice
+		link   bool
+	}{
+		{ChoiceSkip, false},
+		{ChoiceOverwrite, true},
+	} {
+		src, target, failed := failLink(t)
+		dst := filepath.Join(target, "agents", "a.md")
+		writeTree(t, target, map[string]string{"agents/a.md": "mine"})
+
+		var asked []string
+		opts := Options{Strategy: StrategyPrompt, Mode: ModeLink, OnConflict: func(s, d string) (ConflictChoice, error) {
+			asked = append(asked, d)
+			return tc.choice, nil
+		}}
+		retried := Retry(src, target, failed, opts)
+
+		if len(asked) != 1 || asked[0] != dst {
+			t.Errorf("choice %v: prompted for %v, want [%s]", tc.choice, asked, dst)
+		}
+		if len(retried.Errors) != 0 {
+			t.Errorf("choice %v: Errors = %v", tc.choice, retried.Errors)
+		}
+		info, err := os.Lstat(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isLink := info.Mode()&os.ModeSymlink != 0; isLink != tc.link {
+			t.Errorf("choice %v: agents/a.md is a link = %v, want %v", tc.choice, isLink, tc.link)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package copier
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TemplateExts lists the file extensions whose contents have "{{key}}"
+// placeholders substituted when Options.Vars is set.
+var TemplateExts = []string{".md", ".json", ".toml", ".txt"}
+
+// isTemplate reports whether the file at rel has a TemplateExts extension.
+func isTemplate(rel string) bool {
+	ext := strings.ToLower(filepath.Ext(rel))
+	for _, e := range TemplateExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// newVarReplacer builds a replacer turning "{{key}}" into its value, or
+// returns nil when there are no variables.
+func newVarReplacer(vars map[string]string) *strings.Replacer {
+	if len(vars) == 0 {
+		return nil
+	}
+	pairs := make([]string, 0, 2*len(vars))
+	for k, v := range vars {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// CopyTemplate copies src to dst like CopyFile, writing the contents
+// through r. Files that look binary (they contain a NUL byte) are copied
+// unchanged.
+func CopyTemplate(src, dst string, r *strings.Replacer) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("read source: %w", err)
+	}
+	if r == nil || bytes.IndexByte(data, 0) >= 0 {
+		return CopyFile(src, dst)
+	}
+
+	logger.Printf("render %s → %s", src, dst)
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("stat source: %w", err)
+	}
+	return writeAtomic(dst, info.Mode().Perm(), func(w io.Writer) error {
+		if _, err := r.WriteString(w, string(data)); err != nil {
+			return fmt.Errorf("write data: %w", err)
+		}
+		return nil
+	})
+}
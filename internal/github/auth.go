@@ -3,46 +3,169 @@
 package github
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/acchapm1/ocmgr/internal/config"
 )
 
 // repoPattern validates owner/repo format.
 var repoPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+/[a-zA-Z0-9._-]+$`)
 
+// ValidateRepo checks that repo is an owner/repo slug.
+func ValidateRepo(repo string) error {
+	if !repoPattern.MatchString(repo) {
+		return fmt.Errorf("invalid repository slug %q; expected format: owner/repo", repo)
+	}
+	return nil
+}
+
+// defaultHost is used when github.host is not configured.
+const defaultHost = "github.com"
+
+// host is the git host set by SetHost.
+var host = defaultHost
+
+// SetHost sets the git host (github.host) that remote URLs, the SSH
+// check and tokens are resolved for; empty means github.com. The CLI
+// calls it once per command with the loaded config, so the git
+// operations of a command do not each read the config again.
+func SetHost(h string) {
+	if h = strings.TrimSpace(h); h == "" {
+		h = defaultHost
+	}
+	host = h
+}
+
+// remoteHost returns the git host set by SetHost.
+func remoteHost() string {
+	return host
+}
+
 // ResolveRemoteURL returns a plain git remote URL for the given
-// repository.  Authentication tokens are NEVER embedded in the URL;
-// they are injected via git http.extraHeader at command execution
-// time (see gitCommandWithAuth).
+// repository on the configured host (github.host, default github.com).
+// Authentication tokens are NEVER embedded in the URL; they are injected
+// via git http.extraHeader at command execution time (see gitAuthArgs).
 //
 // Supported auth methods:
 //
-//	"ssh"   → git@github.com:<repo>.git
-//	"gh"    → https://github.com/<repo>.git  (token injected via extraHeader)
-//	"env"   → https://github.com/<repo>.git  (token injected via extraHeader)
-//	"token" → https://github.com/<repo>.git  (token injected via extraHeader)
+//	"ssh"   → git@<host>:<repo>.git
+//	"gh"    → https://<host>/<repo>.git  (token injected via extraHeader)
+//	"env"   → https://<host>/<repo>.git  (token injected via extraHeader)
+//	"token" → https://<host>/<repo>.git  (token injected via extraHeader)
 func ResolveRemoteURL(repo, authMethod string) (string, error) {
 	if repo == "" {
 		return "", fmt.Errorf("no GitHub repository configured; run: ocmgr config set github.repo <owner/repo>")
 	}
-	if !repoPattern.MatchString(repo) {
-		return "", fmt.Errorf("invalid repository slug %q; expected format: owner/repo", repo)
+	if err := ValidateRepo(repo); err != nil {
+		return "", err
 	}
 
+	host := remoteHost()
 	if authMethod == "ssh" {
-		return fmt.Sprintf("git@github.com:%s.git", repo), nil
+		return fmt.Sprintf("git@%s:%s.git", host, repo), nil
 	}
 
-	return fmt.Sprintf("https://github.com/%s.git", repo), nil
+	return fmt.Sprintf("https://%s/%s.git", host, repo), nil
 }
 
+// sshVerified caches a successful VerifySSHAuth so the check runs at
+// most once per process.
+var (
+	sshVerifyMu sync.Mutex
+	sshVerified bool
+)
+
+// VerifySSHAuth checks that an SSH key is set up for the configured git
+// host by running "ssh -T git@<host>" non-interactively. It returns an
+// error with setup instructions when the key is missing or rejected, or
+// the host key is unknown, instead of the raw exit status git would
+// report. It is called by EnsureCache when github.auth is "ssh".
+func VerifySSHAuth() error {
+	sshVerifyMu.Lock()
+	defer sshVerifyMu.Unlock()
+	if sshVerified {
+		return nil
+	}
+
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return fmt.Errorf("github.auth is \"ssh\" but ssh was not found in PATH; install OpenSSH or switch auth: ocmgr config set github.auth gh")
+	}
+
+	host := remoteHost()
+	cmd := exec.Command("ssh", "-T",
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=10",
+		"git@"+host)
+	logger.Printf("ssh -T git@%s", host)
+	out, err := cmd.CombinedOutput()
+	output := string(out)
+
+	// Git hosts close the session after greeting the user; GitHub exits
+	// with status 1 even when authentication succeeded.
+	var exitErr *exec.ExitError
+	if err == nil || (errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && !strings.Contains(output, "Permission denied")) {
+		sshVerified = true
+		return nil
+	}
+
+	switch {
+	case strings.Contains(output, "Permission denied"):
+		return fmt.Errorf("SSH authentication to %s failed: no accepted key was offered.\n"+
+			"Add your key to the SSH agent (ssh-add ~/.ssh/id_ed25519) and register the public key with %s,\n"+
+			"or switch auth method: ocmgr config set github.auth gh", host, host)
+	case strings.Contains(output, "Host key verification failed"):
+		return fmt.Errorf("SSH host key for %s is not trusted yet; connect once to accept it: ssh -T git@%s", host, host)
+	case strings.Contains(output, "Could not resolve hostname"):
+		return fmt.Errorf("cannot reach %s over SSH: could not resolve hostname", host)
+	}
+	return fmt.Errorf("SSH connection to %s failed: %s", host, strings.TrimSpace(output))
+}
+
+// tokenTTL bounds how long a resolved token is reused. A CLI command
+// finishes well within it; the TUI, which runs longer, picks up a
+// refreshed or revoked token after it expires.
+const tokenTTL = 5 * time.Minute
+
+// tokenCache holds the last token resolved by ResolveToken, in memory
+// only, so a command running several git operations spawns
+// "gh auth token" once. It is keyed by auth method and host and is
+// replaced when either changes.
+var (
+	tokenMu      sync.Mutex
+	tokenKey     string
+	tokenValue   string
+	tokenExpires time.Time
+)
+
 // ResolveToken extracts an authentication token using the configured
 // auth method.  Returns an empty string (not an error) if no token is
 // available — this allows public repos to work without credentials.
+// The result is cached in memory for tokenTTL.
 func ResolveToken(authMethod string) string {
+	key := authMethod + "@" + remoteHost()
+
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+	if key == tokenKey && time.Now().Before(tokenExpires) {
+		return tokenValue
+	}
+
+	tokenKey = key
+	tokenValue = resolveToken(authMethod)
+	tokenExpires = time.Now().Add(tokenTTL)
+	return tokenValue
+}
+
+// resolveToken looks up the token for authMethod without caching.
+func resolveToken(authMethod string) string {
 	switch authMethod {
 	case "gh":
 		t, _ := resolveGHToken()
@@ -57,9 +180,15 @@ func ResolveToken(authMethod string) string {
 	}
 }
 
-// resolveGHToken obtains a token from the GitHub CLI (`gh auth token`).
+// resolveGHToken obtains a token from the GitHub CLI (`gh auth token`),
+// asking for the configured host when it is not github.com (e.g. a
+// GitHub Enterprise server).
 func resolveGHToken() (string, error) {
-	out, err := exec.Command("gh", "auth", "token").Output()
+	args := []string{"auth", "token"}
+	if host := remoteHost(); host != defaultHost {
+		args = append(args, "--hostname", host)
+	}
+	out, err := exec.Command("gh", args...).Output()
 	if err != nil {
 		return "", fmt.Errorf("gh auth token failed: %w", err)
 	}
@@ -79,14 +208,29 @@ func resolveEnvToken() string {
 	return os.Getenv("GITHUB_TOKEN")
 }
 
-// resolveStoredToken reads a personal access token from ~/.ocmgr/.token.
-// It verifies the file has safe permissions (owner-only).
-func resolveStoredToken() (string, error) {
-	home, err := os.UserHomeDir()
+// CheckStoredToken verifies that the TokenPath file exists, has owner-only
+// permissions, and is not empty. It is used when auth = "token".
+func CheckStoredToken() error {
+	t, err := resolveStoredToken()
 	if err != nil {
-		return "", err
+		return err
+	}
+	if t == "" {
+		return fmt.Errorf("token file is empty")
 	}
-	tokenPath := home + "/.ocmgr/.token"
+	return nil
+}
+
+// TokenPath returns the path of the file holding the personal access
+// token used when auth = "token": .token in config.ConfigDir.
+func TokenPath() string {
+	return filepath.Join(config.ConfigDir(), ".token")
+}
+
+// resolveStoredToken reads a personal access token from TokenPath.
+// It verifies the file has safe permissions (owner-only).
+func resolveStoredToken() (string, error) {
+	tokenPath := TokenPath()
 
 	info, err := os.Stat(tokenPath)
 	if err != nil {
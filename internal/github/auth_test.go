@@ -0,0 +1,84 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGH puts a gh script on PATH that prints a token and appends a line
+// to a log file each time it runs, and returns a function counting the
+// runs so far. It resets the token cache.
+func fakeGH(tb testing.TB) func() int {
+	tb.Helper()
+	dir := tb.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho x >> '" + calls + "'\necho gho_test\n"
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0o755); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	resetTokenCache()
+	tb.Cleanup(resetTokenCache)
+
+	return func() int {
+		data, _ := os.ReadFile(calls)
+		return strings.Count(string(data), "\n")
+	}
+}
+
+func resetTokenCache() {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+	tokenKey, tokenValue, tokenExpires = "", "", time.Time{}
+}
+
+func TestResolveTokenCachesPerMethodAndHost(t *testing.T) {
+	spawns := fakeGH(t)
+	t.Cleanup(func() { SetHost("") })
+
+	for range 3 {
+		if got := ResolveToken("gh"); got != "gho_test" {
+			t.Fatalf("ResolveToken = %q", got)
+		}
+	}
+	if n := spawns(); n != 1 {
+		t.Errorf("gh ran %d times for three lookups, want 1", n)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "env_test")
+	if got := ResolveToken("env"); got != "env_test" {
+		t.Errorf("ResolveToken(env) = %q after switching method", got)
+	}
+	ResolveToken("gh")
+	if n := spawns(); n != 2 {
+		t.Errorf("gh ran %d times after switching method back, want 2", n)
+	}
+
+	SetHost("git.example.com")
+	ResolveToken("gh")
+	if n := spawns(); n != 3 {
+		t.Errorf("gh ran %d times after changing host, want 3", n)
+	}
+}
+
+// BenchmarkResolveToken reports the gh processes spawned per token
+// lookup with the in-memory cache; BenchmarkResolveTokenUncached is
+// the same lookup without it, one spawn each.
+func BenchmarkResolveToken(b *testing.B) {
+	spawns := fakeGH(b)
+	for b.Loop() {
+		ResolveToken("gh")
+	}
+	b.ReportMetric(float64(spawns())/float64(b.N), "spawns/op")
+}
+
+func BenchmarkResolveTokenUncached(b *testing.B) {
+	spawns := fakeGH(b)
+	for b.Loop() {
+		resolveToken("gh")
+	}
+	b.ReportMetric(float64(spawns())/float64(b.N), "spawns/op")
+}
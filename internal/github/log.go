@@ -0,0 +1,44 @@
+package github
+
+import (
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+// logger receives the git commands run against the sync cache. It
+// discards everything unless SetLogger is called, e.g. by the CLI's
+// --verbose flag.
+var logger = log.New(io.Discard, "", 0)
+
+// SetLogger directs github's diagnostic output to l. A nil l silences it.
+func SetLogger(l *log.Logger) {
+	if l == nil {
+		l = log.New(io.Discard, "", 0)
+	}
+	logger = l
+}
+
+// gitCommand builds a git command running in dir (the current directory
+// if empty) and logs its argv with any auth token redacted.
+func gitCommand(dir string, args ...string) *exec.Cmd {
+	logger.Printf("git %s", strings.Join(redactArgs(args), " "))
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// redactArgs returns a copy of args with the token in the header added
+// by gitAuthArgs replaced.
+func redactArgs(args []string) []string {
+	const authPrefix = "http.extraHeader=Authorization: Bearer "
+	out := make([]string, len(args))
+	for i, a := range args {
+		if strings.HasPrefix(a, authPrefix) {
+			a = authPrefix + "<redacted>"
+		}
+		out[i] = a
+	}
+	return out
+}
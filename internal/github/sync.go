@@ -1,19 +1,29 @@
 package github
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/acchapm1/ocmgr/internal/config"
 	"github.com/acchapm1/ocmgr/internal/copier"
 	"github.com/acchapm1/ocmgr/internal/profile"
 )
 
-// cacheDir returns the path to the local sync cache (~/.ocmgr/.sync-cache).
+// ErrProfileNotFound is wrapped by the errors returned when a profile
+// does not exist in the remote repository.
+var ErrProfileNotFound = errors.New("not found in remote repository")
+
+// cacheDir returns the path to the local sync cache. It defaults to
+// config.DefaultCacheDir; see config.CacheDir for overrides.
 func cacheDir() string {
-	return filepath.Join(config.ConfigDir(), ".sync-cache")
+	return config.CacheDir()
 }
 
 // cacheProfilesDir returns the profiles/ subdirectory inside the cache.
@@ -25,8 +35,15 @@ func cacheProfilesDir() string {
 // if it has not been cloned yet, or pulls the latest changes if a
 // cached clone already exists.
 //
-// The cache lives at ~/.ocmgr/.sync-cache/.
-func EnsureCache(repo, authMethod string) (string, error) {
+// ref selects the branch or tag to check out; an empty ref means the
+// remote's default branch. If the cache currently has a different ref
+// checked out it is switched over before pulling, so a previous
+// invocation with another ref never leaves stale content behind.
+//
+// The cache lives at config.DefaultCacheDir unless store.cache_dir or
+// OCMGR_CACHE_DIR point elsewhere. A configured directory that already
+// holds anything other than an ocmgr cache is refused, never replaced.
+func EnsureCache(repo, authMethod, ref string) (string, error) {
 	if _, err := exec.LookPath("git"); err != nil {
 		return "", fmt.Errorf("git is required for sync operations but was not found in PATH")
 	}
@@ -36,24 +53,38 @@ func EnsureCache(repo, authMethod string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if authMethod == "ssh" {
+		if err := VerifySSHAuth(); err != nil {
+			return "", err
+		}
+	}
 	token := ResolveToken(authMethod)
 
-	if isGitRepo(dir) {
-		// Cache exists — pull latest.
-		if err := gitPull(dir, token); err != nil {
-			return "", fmt.Errorf("pulling latest changes: %w", err)
+	if IsCache(dir) {
+		// Cache exists — switch refs if needed, then pull latest.
+		if err := updateCache(dir, token, ref); err != nil {
+			return "", err
 		}
+		markCache(dir)
+		excludeManifest(dir)
 		return dir, nil
 	}
 
-	// No cache — clone.
-	if err := os.RemoveAll(dir); err != nil {
-		return "", fmt.Errorf("cleaning cache directory: %w", err)
+	// No cache — clone. The directory may be configured to be anywhere,
+	// so only an absent or empty one is used; anything else belongs to
+	// the user.
+	if err := checkCacheDir(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", fmt.Errorf("creating cache directory: %w", err)
 	}
 
-	if err := gitClone(remoteURL, dir, token); err != nil {
+	if err := gitClone(remoteURL, dir, token, ref); err != nil {
 		return "", fmt.Errorf("cloning %s: %w", repo, err)
 	}
+	markCache(dir)
+	excludeManifest(dir)
 
 	// Ensure the profiles/ subdirectory exists in the cache so that
 	// new pushes don't fail on an empty repo.
@@ -64,53 +95,252 @@ func EnsureCache(repo, authMethod string) (string, error) {
 	return dir, nil
 }
 
+// PruneCache tidies the sync cache without contacting the remote:
+// untracked files left behind by interrupted operations are removed and
+// git's object store is garbage collected. It does nothing if there is
+// no cache yet.
+func PruneCache() error {
+	dir := cacheDir()
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	if !IsCache(dir) {
+		return fmt.Errorf("%s is not a sync cache created by ocmgr", dir)
+	}
+	if err := gitRun(dir, "clean", "-fdq"); err != nil {
+		return fmt.Errorf("git clean: %w", err)
+	}
+	if err := gitRun(dir, "gc", "--prune=now", "--quiet"); err != nil {
+		return fmt.Errorf("git gc: %w", err)
+	}
+	return nil
+}
+
+// CacheBranch refreshes the sync cache at branch and returns the name of
+// the branch that pushes will be sent to: branch itself, or the remote's
+// default branch when branch is empty.
+func CacheBranch(repo, authMethod, branch string) (string, error) {
+	cache, err := EnsureCache(repo, authMethod, branch)
+	if err != nil {
+		return "", err
+	}
+	return gitCurrentBranch(cache)
+}
+
 // PushProfile copies a local profile into the sync cache and pushes
-// the changes to the remote repository.
-func PushProfile(name, localProfileDir, repo, authMethod string) error {
-	cache, err := EnsureCache(repo, authMethod)
+// the changes to the remote repository. It is PushProfiles for a single
+// profile stored at localProfileDir.
+func PushProfile(name, localProfileDir, repo, authMethod, branch string) error {
+	_, err := pushProfileDirs([]string{name}, map[string]string{name: localProfileDir}, repo, authMethod, branch, false)
+	return err
+}
+
+// PushProfiles copies the named profiles from localStoreDir into the
+// sync cache and pushes them to the remote repository with a single
+// commit and a single push. branch is the branch to push to; empty
+// means the remote's default branch.
+//
+// With dryRun, nothing is committed or pushed: the returned preview lists
+// the files that would be committed (git status and a diff stat) and the
+// cache is reset afterwards. Otherwise the preview is empty.
+func PushProfiles(names []string, localStoreDir, repo, authMethod, branch string, dryRun bool) (string, error) {
+	dirs := make(map[string]string, len(names))
+	for _, name := range names {
+		dirs[name] = filepath.Join(localStoreDir, name)
+	}
+	return pushProfileDirs(names, dirs, repo, authMethod, branch, dryRun)
+}
+
+// pushProfileDirs implements PushProfile and PushProfiles; dirs maps each
+// name to the local profile directory.
+func pushProfileDirs(names []string, dirs map[string]string, repo, authMethod, branch string, dryRun bool) (string, error) {
+	if len(names) == 0 {
+		return "", nil
+	}
+	for _, name := range names {
+		if err := profile.ValidateName(name); err != nil {
+			return "", err
+		}
+	}
+
+	cache, err := EnsureCache(repo, authMethod, branch)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	dst := filepath.Join(cache, "profiles", name)
+	rels := make([]string, 0, len(names))
+	for _, name := range names {
+		dst := filepath.Join(cache, "profiles", name)
+
+		// Remove old version in cache so deleted files don't linger.
+		_ = os.RemoveAll(dst)
 
-	// Remove old version in cache so deleted files don't linger.
-	_ = os.RemoveAll(dst)
+		// Copy profile into cache.
+		if err := CopyDirRecursive(dirs[name], dst); err != nil {
+			return "", fmt.Errorf("copying profile %q to cache: %w", name, err)
+		}
+		rels = append(rels, filepath.Join("profiles", name))
+	}
 
-	// Copy profile into cache.
-	if err := CopyDirRecursive(localProfileDir, dst); err != nil {
-		return fmt.Errorf("copying profile to cache: %w", err)
+	if dryRun {
+		return previewPush(cache, rels...)
 	}
 
 	// Stage, commit and push.
 	token := ResolveToken(authMethod)
-	rel := filepath.Join("profiles", name)
-	if err := gitAddCommitPush(cache, rel, fmt.Sprintf("sync: update %s", name), token); err != nil {
-		return err
+	if err := gitAddCommitPush(cache, pushMessage(names), token, rels...); err != nil {
+		return "", err
 	}
+	recordSynced(cache, names...)
 
-	return nil
+	return "", nil
+}
+
+// pushMessage returns the commit message for pushing names: "sync:
+// update <name>" for one profile, otherwise a summary line followed by
+// the list of profiles.
+func pushMessage(names []string) string {
+	if len(names) == 1 {
+		return fmt.Sprintf("sync: update %s", names[0])
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "sync: update %d profiles\n\n", len(names))
+	for _, name := range names {
+		fmt.Fprintf(&b, "- %s\n", name)
+	}
+	return b.String()
 }
 
 // PullProfile downloads a single profile from the remote repository
-// into the local store directory.
-func PullProfile(name, targetStoreDir, repo, authMethod string) error {
-	if _, err := EnsureCache(repo, authMethod); err != nil {
-		return err
+// into the local store directory. ref is the branch or tag to pull from
+// (see EnsureCache); empty means the default branch.
+//
+// With dryRun, the local store is left untouched and the returned diff
+// describes the pull instead: it would create the RemoteOnly files,
+// overwrite the Modified ones and delete the LocalOnly ones. Otherwise
+// the diff is nil.
+//
+// A locked local copy is not replaced unless force is set.
+func PullProfile(name, targetStoreDir, repo, authMethod, ref string, dryRun, force bool) (*ProfileDiff, error) {
+	if err := profile.ValidateName(name); err != nil {
+		return nil, err
+	}
+	if _, err := EnsureCache(repo, authMethod, ref); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return previewPull(name, targetStoreDir)
 	}
+	if err := pullProfileFromCache(name, targetStoreDir, force); err != nil {
+		return nil, err
+	}
+	recordSynced(cacheDir(), name)
+	return nil, nil
+}
 
-	return pullProfileFromCache(name, targetStoreDir)
+// Pulled is a profile handled by PullAll or CloneAll.
+type Pulled struct {
+	Name string
+	// Diff describes the changes a dry run would make, as for
+	// PullProfile. It is nil when the profile was actually pulled.
+	Diff *ProfileDiff
 }
 
 // PullAll downloads every profile from the remote repository into the
-// local store directory and returns the names of the profiles that
-// were pulled.
-func PullAll(targetStoreDir, repo, authMethod string) ([]string, error) {
-	if _, err := EnsureCache(repo, authMethod); err != nil {
+// local store directory and returns the profiles that were pulled. With
+// dryRun, nothing is written and each profile's Diff describes what
+// would change instead.
+//
+// A profile that fails to pull, including a locked local copy when force
+// is not set, does not stop the others; failures are collected and
+// returned together as a *PullError alongside the list of profiles that
+// were pulled successfully.
+func PullAll(targetStoreDir, repo, authMethod, ref string, dryRun, force bool) ([]Pulled, error) {
+	if _, err := EnsureCache(repo, authMethod, ref); err != nil {
+		return nil, err
+	}
+	pulled, err := pullAllFrom(cacheProfilesDir(), targetStoreDir, dryRun, force)
+	if !dryRun {
+		names := make([]string, len(pulled))
+		for i, p := range pulled {
+			names[i] = p.Name
+		}
+		recordSynced(cacheDir(), names...)
+	}
+	return pulled, err
+}
+
+// CloneAll pulls every profile of repo into the local store directory
+// like PullAll, but from a temporary clone instead of the sync cache, so
+// any repository can be used without touching the configured one. ref
+// selects a branch or tag; empty means the default branch. Auth follows
+// authMethod as for EnsureCache.
+//
+// As the repository is not the user's own, profiles that already exist
+// locally are only replaced when force is set; otherwise nothing is
+// pulled and the error names them.
+func CloneAll(targetStoreDir, repo, authMethod, ref string, dryRun, force bool) ([]Pulled, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is required for sync operations but was not found in PATH")
+	}
+	remoteURL, err := ResolveRemoteURL(repo, authMethod)
+	if err != nil {
 		return nil, err
 	}
+	if authMethod == "ssh" {
+		if err := VerifySSHAuth(); err != nil {
+			return nil, err
+		}
+	}
+	token := ResolveToken(authMethod)
 
-	entries, err := os.ReadDir(cacheProfilesDir())
+	tmp, err := os.MkdirTemp("", "ocmgr-clone-")
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	dir := filepath.Join(tmp, "repo")
+	if err := gitClone(remoteURL, dir, token, ref); err != nil {
+		return nil, fmt.Errorf("cloning %s: %w", repo, err)
+	}
+	profilesDir := filepath.Join(dir, "profiles")
+	if !force {
+		if err := checkCollisions(profilesDir, targetStoreDir); err != nil {
+			return nil, err
+		}
+	}
+	return pullAllFrom(profilesDir, targetStoreDir, dryRun, force)
+}
+
+// checkCollisions returns an error naming the profiles below profilesDir
+// that already exist in targetStoreDir.
+func checkCollisions(profilesDir, targetStoreDir string) error {
+	entries, err := os.ReadDir(profilesDir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading remote profiles: %w", err)
+	}
+	var existing []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(targetStoreDir, entry.Name())); err == nil {
+			existing = append(existing, entry.Name())
+		}
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("%d profile(s) already exist locally: %s; use --force to replace them",
+			len(existing), strings.Join(existing, ", "))
+	}
+	return nil
+}
+
+// pullAllFrom implements PullAll and CloneAll for the profiles below
+// profilesDir.
+func pullAllFrom(profilesDir, targetStoreDir string, dryRun, force bool) ([]Pulled, error) {
+	entries, err := os.ReadDir(profilesDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -118,33 +348,131 @@ func PullAll(targetStoreDir, repo, authMethod string) ([]string, error) {
 		return nil, fmt.Errorf("reading remote profiles: %w", err)
 	}
 
-	var pulled []string
+	var pulled []Pulled
+	failed := make(map[string]error)
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 		name := entry.Name()
-		if err := pullProfileFromCache(name, targetStoreDir); err != nil {
-			return pulled, fmt.Errorf("pulling %q: %w", name, err)
+		if err := profile.ValidateName(name); err != nil {
+			failed[name] = err
+			continue
+		}
+		src := filepath.Join(profilesDir, name)
+		var diff *ProfileDiff
+		if dryRun {
+			diff, err = previewPullDir(src, name, targetStoreDir)
+		} else {
+			err = pullProfileDir(src, name, targetStoreDir, force)
 		}
-		pulled = append(pulled, name)
+		if err != nil {
+			failed[name] = err
+			continue
+		}
+		pulled = append(pulled, Pulled{Name: name, Diff: diff})
 	}
 
+	if len(failed) > 0 {
+		return pulled, &PullError{Failed: failed}
+	}
 	return pulled, nil
 }
 
+// PullError reports the profiles that could not be pulled by PullAll.
+type PullError struct {
+	// Failed maps each profile name to the error that occurred.
+	Failed map[string]error
+}
+
+// Error implements the error interface.
+func (e *PullError) Error() string {
+	names := make([]string, 0, len(e.Failed))
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %v", name, e.Failed[name])
+	}
+	return fmt.Sprintf("%d profile(s) failed to pull: %s", len(names), strings.Join(parts, "; "))
+}
+
+// CachedProfileDir refreshes the sync cache and returns the path of the
+// named profile inside it.  The returned directory is owned by the cache
+// and must be treated as read-only; it is used to apply a remote profile
+// without storing it locally.
+func CachedProfileDir(name, repo, authMethod, ref string) (string, error) {
+	if err := profile.ValidateName(name); err != nil {
+		return "", err
+	}
+	if _, err := EnsureCache(repo, authMethod, ref); err != nil {
+		return "", err
+	}
+	return CachedProfilePath(name)
+}
+
+// CachedProfilePath is CachedProfileDir without refreshing the cache, for
+// callers that have just called EnsureCache themselves.
+func CachedProfilePath(name string) (string, error) {
+	if err := profile.ValidateName(name); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cacheProfilesDir(), name)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return "", fmt.Errorf("profile %q %w", name, ErrProfileNotFound)
+	}
+	return dir, nil
+}
+
+// previewPull returns the changes pullProfileFromCache would make to the
+// local copy of a profile, without making them.
+func previewPull(name, targetStoreDir string) (*ProfileDiff, error) {
+	return previewPullDir(filepath.Join(cacheProfilesDir(), name), name, targetStoreDir)
+}
+
+// previewPullDir is previewPull for the remote profile copy at src.
+func previewPullDir(src, name, targetStoreDir string) (*ProfileDiff, error) {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil, fmt.Errorf("profile %q %w", name, ErrProfileNotFound)
+	}
+	return compareProfileDirs(filepath.Join(targetStoreDir, name), src)
+}
+
+// CachedProfileNames lists the profiles in the sync cache as of the last
+// sync, without contacting the remote. It returns nil if there is no
+// cache yet.
+func CachedProfileNames() ([]string, error) {
+	return listProfileNames(cacheProfilesDir())
+}
+
 // pullProfileFromCache copies a profile from the already-ensured
 // cache to the local store.  Avoids redundant EnsureCache calls.
-func pullProfileFromCache(name, targetStoreDir string) error {
-	src := filepath.Join(cacheProfilesDir(), name)
+// A locked local copy is left alone unless force is set.
+func pullProfileFromCache(name, targetStoreDir string, force bool) error {
+	return pullProfileDir(filepath.Join(cacheProfilesDir(), name), name, targetStoreDir, force)
+}
+
+// pullProfileDir replaces the local copy of profile name with the remote
+// copy at src.
+func pullProfileDir(src, name, targetStoreDir string, force bool) error {
 	if _, err := os.Stat(src); os.IsNotExist(err) {
-		return fmt.Errorf("profile %q not found in remote repository", name)
+		return fmt.Errorf("profile %q %w", name, ErrProfileNotFound)
 	}
 
 	dst := filepath.Join(targetStoreDir, name)
+	if !force {
+		if err := profile.CheckUnlocked(dst); err != nil {
+			return err
+		}
+	}
 
 	// Remove local version so we get a clean copy.
-	_ = os.RemoveAll(dst)
+	if err := os.RemoveAll(dst); err != nil {
+		return fmt.Errorf("removing local copy: %w", err)
+	}
 
 	if err := CopyDirRecursive(src, dst); err != nil {
 		return fmt.Errorf("copying profile from cache: %w", err)
@@ -153,21 +481,110 @@ func pullProfileFromCache(name, targetStoreDir string) error {
 	return nil
 }
 
+// ProfileDiff describes how the local and remote copies of a profile
+// differ. File paths are relative to the profile root.
+type ProfileDiff struct {
+	// LocalDir and RemoteDir are the two profile directories compared.
+	// RemoteDir lives in the sync cache and must be treated as read-only.
+	LocalDir  string
+	RemoteDir string
+	// LocalOnly lists files that exist only in the local store.
+	LocalOnly []string
+	// RemoteOnly lists files that exist only in the remote repository.
+	RemoteOnly []string
+	// Modified lists files that exist in both but differ.
+	Modified []string
+}
+
+// DiffProfile refreshes the sync cache at ref (see EnsureCache) and
+// compares the named profile in localStoreDir with its remote copy.
+func DiffProfile(name, localStoreDir, repo, authMethod, ref string) (*ProfileDiff, error) {
+	if err := profile.ValidateName(name); err != nil {
+		return nil, err
+	}
+	if _, err := EnsureCache(repo, authMethod, ref); err != nil {
+		return nil, err
+	}
+
+	localDir := filepath.Join(localStoreDir, name)
+	remoteDir := filepath.Join(cacheProfilesDir(), name)
+	if _, err := os.Stat(localDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("profile %q not found locally", name)
+	}
+	if _, err := os.Stat(remoteDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("profile %q %w", name, ErrProfileNotFound)
+	}
+	return compareProfileDirs(localDir, remoteDir)
+}
+
+// compareProfileDirs compares the files of a local and a remote profile
+// directory by content hash. A missing local directory counts as empty.
+func compareProfileDirs(localDir, remoteDir string) (*ProfileDiff, error) {
+	d := &ProfileDiff{LocalDir: localDir, RemoteDir: remoteDir}
+
+	local := &copier.Manifest{Files: map[string]copier.ManifestEntry{}}
+	if _, err := os.Stat(localDir); err == nil {
+		m, err := copier.UpdateManifest(localDir)
+		if err != nil {
+			return nil, fmt.Errorf("scanning local profile: %w", err)
+		}
+		local = m
+	}
+
+	remote, err := copier.UpdateManifest(remoteDir)
+	if err != nil {
+		return nil, fmt.Errorf("scanning remote profile: %w", err)
+	}
+
+	for rel, le := range local.Files {
+		re, ok := remote.Files[rel]
+		switch {
+		case !ok:
+			d.LocalOnly = append(d.LocalOnly, rel)
+		case re.Hash != le.Hash:
+			d.Modified = append(d.Modified, rel)
+		}
+	}
+	for rel := range remote.Files {
+		if _, ok := local.Files[rel]; !ok {
+			d.RemoteOnly = append(d.RemoteOnly, rel)
+		}
+	}
+	sort.Strings(d.LocalOnly)
+	sort.Strings(d.RemoteOnly)
+	sort.Strings(d.Modified)
+
+	return d, nil
+}
+
 // SyncStatus describes the synchronisation state between local and
 // remote profiles.
 type SyncStatus struct {
-	LocalOnly  []string // exist locally but not remotely
-	RemoteOnly []string // exist remotely but not locally
-	Modified   []string // exist in both but differ
-	InSync     []string // exist in both and are identical
+	LocalOnly  []string `json:"local_only"`  // exist locally but not remotely
+	RemoteOnly []string `json:"remote_only"` // exist remotely but not locally
+	Modified   []string `json:"modified"`    // exist in both but differ
+	InSync     []string `json:"in_sync"`     // exist in both and are identical
+	// RemoteCommits counts, per remote profile, the commits that touched
+	// it since it was last pulled or pushed (or since the commit passed
+	// to Status). Profiles with no new commits, or no known starting
+	// point, are absent.
+	RemoteCommits map[string]int `json:"remote_commits,omitempty"`
 }
 
-// Status compares local profiles against the remote cache and returns
-// a SyncStatus summary.
-func Status(localStoreDir, repo, authMethod string) (*SyncStatus, error) {
-	if _, err := EnsureCache(repo, authMethod); err != nil {
+// Status compares local profiles against the remote cache, checked out
+// at ref (see EnsureCache), and returns a SyncStatus summary.
+//
+// New remote commits are counted from since, a commit, tag or anything
+// else git accepts as a revision, when it is set, and otherwise from the
+// commit each profile was last pulled or pushed at on this machine.
+func Status(localStoreDir, repo, authMethod, ref, since string) (*SyncStatus, error) {
+	dir, err := EnsureCache(repo, authMethod, ref)
+	if err != nil {
 		return nil, err
 	}
+	if since != "" && !gitRefExists(dir, since) {
+		return nil, fmt.Errorf("unknown revision %q", since)
+	}
 
 	local, err := listProfileNames(localStoreDir)
 	if err != nil {
@@ -187,7 +604,12 @@ func Status(localStoreDir, repo, authMethod string) (*SyncStatus, error) {
 		remoteSet[n] = true
 	}
 
-	status := &SyncStatus{}
+	status := &SyncStatus{
+		LocalOnly:  []string{},
+		RemoteOnly: []string{},
+		Modified:   []string{},
+		InSync:     []string{},
+	}
 	for _, n := range local {
 		if !remoteSet[n] {
 			status.LocalOnly = append(status.LocalOnly, n)
@@ -214,9 +636,140 @@ func Status(localStoreDir, repo, authMethod string) (*SyncStatus, error) {
 		}
 	}
 
+	synced := loadSynced(dir)
+	for _, n := range remote {
+		base := since
+		if base == "" {
+			base = synced[n]
+		}
+		if base == "" {
+			continue
+		}
+		if count := commitsSince(dir, base, n); count > 0 {
+			if status.RemoteCommits == nil {
+				status.RemoteCommits = make(map[string]int)
+			}
+			status.RemoteCommits[n] = count
+		}
+	}
+
 	return status, nil
 }
 
+// syncedFile is the file, inside the cache's .git directory, that maps
+// each profile to the commit it was last pulled or pushed at. Keeping it
+// there ties it to the clone it describes.
+const syncedFile = "ocmgr-synced.json"
+
+// loadSynced reads the synced commits recorded for the cache at dir. A
+// missing or unreadable file yields an empty map.
+func loadSynced(dir string) map[string]string {
+	synced := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(dir, ".git", syncedFile))
+	if err != nil {
+		return synced
+	}
+	if err := json.Unmarshal(data, &synced); err != nil || synced == nil {
+		return make(map[string]string)
+	}
+	return synced
+}
+
+// recordSynced records the commit checked out in the cache at dir as the
+// one the named profiles were last synced at. It is best effort: a
+// failure only means the next status cannot count new remote commits.
+func recordSynced(dir string, names ...string) {
+	if len(names) == 0 {
+		return
+	}
+	out, err := gitCommand(dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		logger.Printf("recording synced commit: %v", err)
+		return
+	}
+	head := strings.TrimSpace(string(out))
+
+	synced := loadSynced(dir)
+	for _, n := range names {
+		synced[n] = head
+	}
+	data, err := json.MarshalIndent(synced, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, ".git", syncedFile), data, 0o644)
+	}
+	if err != nil {
+		logger.Printf("recording synced commit: %v", err)
+	}
+}
+
+// MarkPulled records that the named profiles were brought up to date
+// with the commit checked out in the sync cache, so Status counts new
+// remote commits from there. PullProfile and PullAll do this themselves;
+// callers that merge from CachedProfileDir must call it.
+func MarkPulled(names ...string) {
+	recordSynced(cacheDir(), names...)
+}
+
+// commitsSince returns the number of commits in the cache at dir after
+// base that touched profiles/<name>, or 0 if base is not an ancestor of
+// the checked-out commit (for example after a force push).
+func commitsSince(dir, base, name string) int {
+	if gitCommand(dir, "merge-base", "--is-ancestor", base, "HEAD").Run() != nil {
+		return 0
+	}
+	out, err := gitCommand(dir, "rev-list", "--count", base+"..HEAD", "--", "profiles/"+name).Output()
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+	return n
+}
+
+// LogEntry is one commit in a profile's history.
+type LogEntry struct {
+	Hash    string `json:"hash"`
+	Date    string `json:"date"` // author date, YYYY-MM-DD
+	Author  string `json:"author"`
+	Subject string `json:"subject"`
+}
+
+// ProfileLog refreshes the sync cache at ref (see EnsureCache) and
+// returns the commits that touched the named profile, newest first.
+// A limit greater than zero caps the number of entries.
+func ProfileLog(name, repo, authMethod, ref string, limit int) ([]LogEntry, error) {
+	if err := profile.ValidateName(name); err != nil {
+		return nil, err
+	}
+	dir, err := EnsureCache(repo, authMethod, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"log", "--date=short", "--format=%h%x1f%ad%x1f%an%x1f%s"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-n%d", limit))
+	}
+	args = append(args, "--", "profiles/"+name)
+
+	out, err := gitCommand(dir, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	var entries []LogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		entries = append(entries, LogEntry{Hash: fields[0], Date: fields[1], Author: fields[2], Subject: fields[3]})
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("profile %q has no history in the remote repository", name)
+	}
+	return entries, nil
+}
+
 // ──────────────────────────────────────────────────────────────────
 // Git helpers — thin wrappers around the git CLI.
 //
@@ -231,6 +784,68 @@ func isGitRepo(dir string) bool {
 	return err == nil && info.IsDir()
 }
 
+// cacheMarker is the file, inside .git so it is never committed, that
+// marks a clone as a sync cache created by ocmgr.
+const cacheMarker = "ocmgr-cache"
+
+// IsCache reports whether dir is a sync cache created by ocmgr, and so
+// safe to reset, clean or delete. Caches cloned before the marker was
+// introduced are recognised by living at the default location.
+func IsCache(dir string) bool {
+	if !isGitRepo(dir) {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", cacheMarker)); err == nil {
+		return true
+	}
+	return filepath.Clean(dir) == filepath.Clean(config.DefaultCacheDir())
+}
+
+// markCache records that dir is a sync cache created by ocmgr. Failures
+// are ignored; an unmarked cache outside the default location is only
+// refused, never damaged.
+func markCache(dir string) {
+	_ = os.WriteFile(filepath.Join(dir, ".git", cacheMarker), nil, 0o644)
+}
+
+// checkCacheDir returns an error unless dir is absent or an empty
+// directory, the only places a new cache may be cloned into.
+func checkCacheDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("checking cache directory: %w", err)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("refusing to use %s as the sync cache: it is not empty and is not a sync cache created by ocmgr; set store.cache_dir or OCMGR_CACHE_DIR to a new directory", dir)
+	}
+	return nil
+}
+
+// excludeManifest adds the copier hash manifest to the repository's
+// .git/info/exclude so manifests written by sync status are never
+// committed.  Failures are ignored; the worst case is an untracked file.
+func excludeManifest(dir string) {
+	exclude := filepath.Join(dir, ".git", "info", "exclude")
+	data, _ := os.ReadFile(exclude)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == copier.ManifestFile {
+			return
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(exclude), 0o755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(exclude, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, copier.ManifestFile)
+}
+
 // gitAuthArgs returns extra git CLI arguments that inject an
 // Authorization header when a token is available.
 func gitAuthArgs(token string) []string {
@@ -240,9 +855,84 @@ func gitAuthArgs(token string) []string {
 	return []string{"-c", fmt.Sprintf("http.extraHeader=Authorization: Bearer %s", token)}
 }
 
-func gitClone(url, dir, token string) error {
-	args := append(gitAuthArgs(token), "clone", url, dir)
-	cmd := exec.Command("git", args...)
+// updateCache brings an existing cache clone up to date with ref (the
+// remote default branch if empty). Branches are checked out and pulled;
+// tags are checked out as a detached HEAD.
+func updateCache(dir, token, ref string) error {
+	target := ref
+	if target == "" {
+		// Fall back to a plain pull if the default branch is unknown.
+		target, _ = gitDefaultBranch(dir)
+	}
+
+	current, err := gitCurrentBranch(dir)
+	if err != nil {
+		return err
+	}
+
+	if target == "" || target == current {
+		return pullOrReset(dir, token)
+	}
+
+	if err := gitFetch(dir, token); err != nil {
+		return fmt.Errorf("fetching %s: %w", target, err)
+	}
+
+	switch {
+	case gitRefExists(dir, "refs/remotes/origin/"+target):
+		if err := gitRun(dir, "checkout", target); err != nil {
+			return fmt.Errorf("checking out branch %q: %w", target, err)
+		}
+		return pullOrReset(dir, token)
+	case gitRefExists(dir, "refs/tags/"+target):
+		if err := gitRun(dir, "checkout", "--detach", "refs/tags/"+target); err != nil {
+			return fmt.Errorf("checking out tag %q: %w", target, err)
+		}
+	default:
+		return fmt.Errorf("branch or tag %q not found in remote repository", target)
+	}
+	return nil
+}
+
+// pullOrReset fast-forwards the branch checked out in the cache. If that
+// fails, for example because the remote history was rewritten, the
+// branch is hard-reset to its remote counterpart instead: the cache is
+// disposable and never holds user edits, so nothing is lost.
+func pullOrReset(dir, token string) error {
+	pullErr := gitPull(dir, token)
+	if pullErr == nil {
+		return nil
+	}
+
+	branch, err := gitCurrentBranch(dir)
+	if err != nil || branch == "HEAD" {
+		return fmt.Errorf("pulling latest changes: %w", pullErr)
+	}
+	if err := gitFetch(dir, token); err != nil {
+		return fmt.Errorf("pulling latest changes: %w", pullErr)
+	}
+	remote := "origin/" + branch
+	if !gitRefExists(dir, "refs/remotes/"+remote) {
+		return fmt.Errorf("pulling latest changes: %w", pullErr)
+	}
+
+	logger.Printf("fast-forward of %s failed (%v); resetting sync cache to %s", branch, pullErr, remote)
+	if err := gitRun(dir, "reset", "-q", "--hard", remote); err != nil {
+		return fmt.Errorf("resetting cache to %s: %w", remote, err)
+	}
+	if err := gitRun(dir, "clean", "-fdq"); err != nil {
+		return fmt.Errorf("cleaning cache: %w", err)
+	}
+	return nil
+}
+
+func gitClone(url, dir, token, ref string) error {
+	args := append(gitAuthArgs(token), "clone")
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--", url, dir)
+	cmd := gitCommand("", args...)
 	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -250,17 +940,91 @@ func gitClone(url, dir, token string) error {
 
 func gitPull(dir, token string) error {
 	args := append(gitAuthArgs(token), "pull", "--ff-only")
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
+	cmd := gitCommand(dir, args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func gitFetch(dir, token string) error {
+	args := append(gitAuthArgs(token), "fetch", "--tags", "--prune", "origin")
+	cmd := gitCommand(dir, args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func gitRun(dir string, args ...string) error {
+	cmd := gitCommand(dir, args...)
 	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
-func gitAddCommitPush(repoDir, pathSpec, message, token string) error {
+func gitRefExists(dir, ref string) bool {
+	cmd := gitCommand(dir, "rev-parse", "--verify", "--quiet", ref)
+	return cmd.Run() == nil
+}
+
+// gitDefaultBranch returns the remote's default branch as recorded by
+// the clone (refs/remotes/origin/HEAD).
+func gitDefaultBranch(dir string) (string, error) {
+	cmd := gitCommand(dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git symbolic-ref: %w", err)
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/"), nil
+}
+
+func gitCurrentBranch(dir string) (string, error) {
+	cmd := gitCommand(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// previewPush stages pathSpecs in the cache, returns a description of
+// what would be committed, and then resets the cache to HEAD.
+func previewPush(repoDir string, pathSpecs ...string) (string, error) {
+	defer func() {
+		_ = gitCommand(repoDir, "reset", "-q", "--hard", "HEAD").Run()
+		_ = gitCommand(repoDir, append([]string{"clean", "-fdq", "--"}, pathSpecs...)...).Run()
+	}()
+
+	add := gitCommand(repoDir, append([]string{"add", "--"}, pathSpecs...)...)
+	add.Stderr = os.Stderr
+	if err := add.Run(); err != nil {
+		return "", fmt.Errorf("git add: %w", err)
+	}
+
+	check := gitCommand(repoDir, "diff", "--cached", "--quiet")
+	if err := check.Run(); err == nil {
+		return "Nothing to push: the remote copy is up to date.\n", nil
+	}
+
+	var preview strings.Builder
+	for _, args := range [][]string{
+		append([]string{"status", "--porcelain", "--"}, pathSpecs...),
+		append([]string{"diff", "--cached", "--stat", "--"}, pathSpecs...),
+	} {
+		cmd := gitCommand(repoDir, args...)
+		cmd.Stdout = &preview
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("git %s: %w", args[0], err)
+		}
+	}
+	return preview.String(), nil
+}
+
+// gitAddCommitPush stages pathSpecs with one "git add", commits them
+// with message if anything changed, and pushes.
+func gitAddCommitPush(repoDir, message, token string, pathSpecs ...string) error {
 	// git add
-	add := exec.Command("git", "add", pathSpec)
-	add.Dir = repoDir
+	add := gitCommand(repoDir, append([]string{"add", "--"}, pathSpecs...)...)
 	add.Stderr = os.Stderr
 	if err := add.Run(); err != nil {
 		return fmt.Errorf("git add: %w", err)
@@ -268,16 +1032,14 @@ func gitAddCommitPush(repoDir, pathSpec, message, token string) error {
 
 	// Check if there are staged changes to commit.
 	// Using `git diff --cached --quiet` — exits 1 if there ARE staged changes.
-	check := exec.Command("git", "diff", "--cached", "--quiet")
-	check.Dir = repoDir
+	check := gitCommand(repoDir, "diff", "--cached", "--quiet")
 	if err := check.Run(); err == nil {
 		// Exit 0 means nothing staged — skip commit and push.
 		return nil
 	}
 
 	// git commit
-	commit := exec.Command("git", "commit", "-m", message)
-	commit.Dir = repoDir
+	commit := gitCommand(repoDir, "commit", "-m", message)
 	commit.Stderr = os.Stderr
 	if err := commit.Run(); err != nil {
 		return fmt.Errorf("git commit: %w", err)
@@ -285,8 +1047,7 @@ func gitAddCommitPush(repoDir, pathSpec, message, token string) error {
 
 	// git push (with auth header)
 	pushArgs := append(gitAuthArgs(token), "push")
-	push := exec.Command("git", pushArgs...)
-	push.Dir = repoDir
+	push := gitCommand(repoDir, pushArgs...)
 	push.Stdout = os.Stderr
 	push.Stderr = os.Stderr
 	if err := push.Run(); err != nil {
@@ -314,6 +1075,11 @@ func CopyDirRecursive(src, dst string) error {
 			return filepath.SkipDir
 		}
 
+		// The hash manifest is local bookkeeping and is never copied.
+		if !info.IsDir() && info.Name() == copier.ManifestFile {
+			return nil
+		}
+
 		rel, err := filepath.Rel(src, path)
 		if err != nil {
 			return err
@@ -355,60 +1121,19 @@ func listProfileNames(dir string) ([]string, error) {
 }
 
 // dirsEqual reports whether every file under two directory trees is
-// identical.  Only regular files are compared.
+// identical.  Content hashes are cached in a per-profile manifest
+// (see copier.UpdateManifest) so only files whose size or mtime changed
+// since the last comparison are read again.
 func dirsEqual(a, b string) (bool, error) {
-	aFiles, err := collectFiles(a)
+	aManifest, err := copier.UpdateManifest(a)
 	if err != nil {
 		return false, err
 	}
-	bFiles, err := collectFiles(b)
+	bManifest, err := copier.UpdateManifest(b)
 	if err != nil {
 		return false, err
 	}
-
-	if len(aFiles) != len(bFiles) {
-		return false, nil
-	}
-
-	for rel, aPath := range aFiles {
-		bPath, ok := bFiles[rel]
-		if !ok {
-			return false, nil
-		}
-		eq, err := copier.FilesEqual(aPath, bPath)
-		if err != nil {
-			return false, err
-		}
-		if !eq {
-			return false, nil
-		}
-	}
-
-	return true, nil
-}
-
-// collectFiles walks a directory and returns a map of relative paths
-// to absolute paths for every regular file.
-func collectFiles(root string) (map[string]string, error) {
-	files := make(map[string]string)
-
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			return nil
-		}
-
-		rel, err := filepath.Rel(root, path)
-		if err != nil {
-			return err
-		}
-		files[rel] = path
-		return nil
-	})
-
-	return files, err
+	return aManifest.Equal(bManifest), nil
 }
 
 // ValidateProfileDir checks whether dir is not empty and is a
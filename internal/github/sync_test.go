@@ -0,0 +1,223 @@
+package github
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/acchapm1/ocmgr/internal/profile"
+)
+
+// isolate points the home and XDG directories at a fresh temporary
+// directory and makes it the working directory, so no real
+// configuration, store or cache is touched.
+func isolate(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("OCMGR_CACHE_DIR", "")
+	t.Chdir(home)
+	return home
+}
+
+func TestEnsureCacheRefusesForeignDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	home := isolate(t)
+
+	work := filepath.Join(home, "work")
+	keep := filepath.Join(work, "notes.txt")
+	if err := os.MkdirAll(work, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keep, []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OCMGR_CACHE_DIR", work)
+
+	_, err := EnsureCache("owner/repo", "env", "")
+	if err == nil || !strings.Contains(err.Error(), "refusing") {
+		t.Fatalf("EnsureCache() error = %v, want a refusal", err)
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Fatalf("user file was removed: %v", err)
+	}
+}
+
+func TestIsCache(t *testing.T) {
+	home := isolate(t)
+
+	repo := filepath.Join(home, "project")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if IsCache(repo) {
+		t.Error("IsCache() = true for an unmarked repository outside the default location")
+	}
+	markCache(repo)
+	if !IsCache(repo) {
+		t.Error("IsCache() = false for a marked repository")
+	}
+
+	// A clone at the default location predates the marker.
+	legacy := filepath.Join(home, ".ocmgr", ".sync-cache")
+	if err := os.MkdirAll(filepath.Join(legacy, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if !IsCache(legacy) {
+		t.Error("IsCache() = false for an unmarked cache at the default location")
+	}
+
+	if IsCache(home) {
+		t.Error("IsCache() = true for a directory that is not a repository")
+	}
+}
+
+func TestCheckCacheDir(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := checkCacheDir(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("absent dir: %v", err)
+	}
+	if err := checkCacheDir(dir); err != nil {
+		t.Errorf("empty dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "f"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkCacheDir(dir); err == nil {
+		t.Error("non-empty dir: no error")
+	}
+}
+
+// writeProfile creates a minimal profile directory at dir/name.
+func writeProfile(t *testing.T, dir, name string) {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Join(p, "agents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	toml := "[profile]\nname = \"" + name + "\"\n"
+	if err := os.WriteFile(filepath.Join(p, "profile.toml"), []byte(toml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(p, "agents", "a.md"), []byte(name), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCollisions(t *testing.T) {
+	remote, local := t.TempDir(), t.TempDir()
+	writeProfile(t, remote, "go")
+	writeProfile(t, remote, "web")
+
+	if err := checkCollisions(remote, local); err != nil {
+		t.Fatalf("empty store: %v", err)
+	}
+	writeProfile(t, local, "web")
+	err := checkCollisions(remote, local)
+	if err == nil || !strings.Contains(err.Error(), "web") || strings.Contains(err.Error(), "go") {
+		t.Fatalf("checkCollisions() = %v, want an error naming only web", err)
+	}
+}
+
+func TestPullAllFromRejectsInvalidNames(t *testing.T) {
+	remote, local := t.TempDir(), t.TempDir()
+	writeProfile(t, remote, "go")
+	writeProfile(t, remote, "-evil")
+
+	pulled, err := pullAllFrom(remote, local, false, false)
+	if len(pulled) != 1 || pulled[0].Name != "go" {
+		t.Errorf("pulled = %v, want [go]", pulled)
+	}
+	var pullErr *PullError
+	if !errors.As(err, &pullErr) || pullErr.Failed["-evil"] == nil {
+		t.Fatalf("err = %v, want a PullError for -evil", err)
+	}
+	if _, err := os.Stat(filepath.Join(local, "-evil")); !os.IsNotExist(err) {
+		t.Error("invalid profile was copied into the store")
+	}
+}
+
+func TestPullAllFromContinuesPastFailures(t *testing.T) {
+	remote, local := t.TempDir(), t.TempDir()
+	for _, name := range []string{"go", "rust", "web", "-evil"} {
+		writeProfile(t, remote, name)
+	}
+	// A locked local copy refuses to be replaced without force.
+	writeProfile(t, local, "web")
+	locked := "[profile]\nname = \"web\"\nlocked = true\n"
+	if err := os.WriteFile(filepath.Join(local, "web", "profile.toml"), []byte(locked), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pulled, err := pullAllFrom(remote, local, false, false)
+	var names []string
+	for _, p := range pulled {
+		names = append(names, p.Name)
+	}
+	if !slices.Equal(names, []string{"go", "rust"}) {
+		t.Errorf("pulled = %v, want [go rust]", names)
+	}
+	var pullErr *PullError
+	if !errors.As(err, &pullErr) {
+		t.Fatalf("err = %v, want a PullError", err)
+	}
+	if len(pullErr.Failed) != 2 || pullErr.Failed["web"] == nil || pullErr.Failed["-evil"] == nil {
+		t.Errorf("Failed = %v, want web and -evil", pullErr.Failed)
+	}
+	if !errors.Is(pullErr.Failed["web"], profile.ErrLocked) {
+		t.Errorf("web failed with %v, want ErrLocked", pullErr.Failed["web"])
+	}
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(local, name, "agents", "a.md")); err != nil {
+			t.Errorf("%s was not pulled: %v", name, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(local, "web", "profile.toml")); string(data) != locked {
+		t.Error("the locked profile was replaced")
+	}
+}
+
+func TestPullAllFromDryRunReturnsDiff(t *testing.T) {
+	remote, local := t.TempDir(), t.TempDir()
+	writeProfile(t, remote, "go")
+
+	pulled, err := pullAllFrom(remote, local, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pulled) != 1 || pulled[0].Diff == nil {
+		t.Fatalf("pulled = %+v, want one entry with a diff", pulled)
+	}
+	if got := pulled[0].Diff.RemoteOnly; len(got) != 2 {
+		t.Errorf("RemoteOnly = %v, want agents/a.md and profile.toml", got)
+	}
+	if _, err := os.Stat(filepath.Join(local, "go")); !os.IsNotExist(err) {
+		t.Error("dry run wrote to the store")
+	}
+}
+
+func TestEntryPointsRejectInvalidNames(t *testing.T) {
+	isolate(t)
+	store := t.TempDir()
+
+	for _, name := range []string{"../x", "a/b", ""} {
+		if _, err := CachedProfileDir(name, "owner/repo", "env", ""); err == nil {
+			t.Errorf("CachedProfileDir(%q) succeeded", name)
+		}
+		if _, err := DiffProfile(name, store, "owner/repo", "env", ""); err == nil {
+			t.Errorf("DiffProfile(%q) succeeded", name)
+		}
+		if _, err := PullProfile(name, store, "owner/repo", "env", "", false, false); err == nil {
+			t.Errorf("PullProfile(%q) succeeded", name)
+		}
+	}
+}
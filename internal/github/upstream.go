@@ -0,0 +1,99 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CloneRef performs a shallow clone of a single branch or tag of the
+// given GitHub repository into dir and returns the commit SHA that was
+// checked out.  It is used by profile import to fetch a profile from a
+// GitHub tree URL.
+func CloneRef(repo, ref, dir string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is required to import from GitHub but was not found in PATH")
+	}
+
+	cloneURL := fmt.Sprintf("https://github.com/%s.git", repo)
+	clone := gitCommand("", "clone", "--depth", "1", "--branch", ref, cloneURL, dir)
+	clone.Stderr = os.Stderr
+	if err := clone.Run(); err != nil {
+		return "", fmt.Errorf("cloning %s: %w", repo, err)
+	}
+
+	return HeadCommit(dir)
+}
+
+// CloneURL performs a shallow clone of an arbitrary git remote (for
+// example an SSH URL such as git@github.com:owner/repo.git) into dir and
+// returns the commit SHA that was checked out. ref selects a branch or
+// tag; when empty the remote's default branch is used. Authentication is
+// left to git, so SSH remotes use the user's SSH keys.
+func CloneURL(remote, ref, dir string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is required to import from a git remote but was not found in PATH")
+	}
+
+	args := []string{"clone", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	// "--" keeps a remote starting with "-" from being read as an option.
+	clone := gitCommand("", append(args, "--", remote, dir)...)
+	clone.Stderr = os.Stderr
+	if err := clone.Run(); err != nil {
+		return "", fmt.Errorf("cloning %s: %w", remote, err)
+	}
+
+	return HeadCommit(dir)
+}
+
+// HeadCommit returns the commit SHA of HEAD in the git repository at dir.
+func HeadCommit(dir string) (string, error) {
+	cmd := gitCommand(dir, "rev-parse", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RemoteCommit returns the commit SHA that ref (a branch or tag name)
+// currently points to in the given GitHub repository.  It uses
+// `git ls-remote`, so no objects are downloaded.
+func RemoteCommit(repo, ref string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is required to check for updates but was not found in PATH")
+	}
+
+	remoteURL := fmt.Sprintf("https://github.com/%s.git", repo)
+	out, err := gitCommand("", "ls-remote", remoteURL, ref).Output()
+	if err != nil {
+		return "", fmt.Errorf("git ls-remote %s: %w", repo, err)
+	}
+
+	// Each line is "<sha>\t<refname>".  Annotated tags appear twice; the
+	// peeled "^{}" entry holds the commit the tag points to.
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		refs[fields[1]] = fields[0]
+	}
+
+	for _, name := range []string{
+		"refs/heads/" + ref,
+		"refs/tags/" + ref + "^{}",
+		"refs/tags/" + ref,
+	} {
+		if sha, ok := refs[name]; ok {
+			return sha, nil
+		}
+	}
+
+	return "", fmt.Errorf("ref %q not found in %s", ref, repo)
+}
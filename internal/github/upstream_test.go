@@ -0,0 +1,36 @@
+package github
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCloneURLRejectsOptionRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tmp := t.TempDir()
+	marker := filepath.Join(tmp, "pwned")
+
+	// Without "--", git would take the remote as an option and dir as
+	// the repository, running the command to serve it.
+	repo := filepath.Join(tmp, "repo")
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	work := filepath.Join(tmp, "work")
+	if err := os.Mkdir(work, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(work)
+
+	_, err := CloneURL("--upload-pack=touch "+marker+";", "", repo)
+	if err == nil {
+		t.Fatal("CloneURL() succeeded for a remote starting with --")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("remote was interpreted as a git option")
+	}
+}
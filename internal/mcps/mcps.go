@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/acchapm1/ocmgr/internal/config"
 )
 
 // Definition represents an MCP server definition file.
@@ -21,15 +23,11 @@ type Registry struct {
 	Servers []Definition
 }
 
-// Load reads all MCP definitions from ~/.ocmgr/mcps/*.json.
+// Load reads all MCP definitions from mcps/*.json in the configuration
+// directory (~/.ocmgr by default).
 // Returns an empty registry if the directory doesn't exist.
 func Load() (*Registry, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("getting home directory: %w", err)
-	}
-
-	mcpsDir := filepath.Join(home, ".ocmgr", "mcps")
+	mcpsDir := filepath.Join(config.ConfigDir(), "mcps")
 
 	// Check if directory exists
 	if _, err := os.Stat(mcpsDir); os.IsNotExist(err) {
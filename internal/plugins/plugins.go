@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/acchapm1/ocmgr/internal/config"
 )
 
 // Plugin represents an available plugin from the registry.
@@ -20,15 +22,11 @@ type Registry struct {
 	Plugins []Plugin `toml:"plugin"`
 }
 
-// Load reads the plugin registry from ~/.ocmgr/plugins/plugins.toml.
+// Load reads the plugin registry from plugins/plugins.toml in the
+// configuration directory (~/.ocmgr by default).
 // Returns an empty registry if the file doesn't exist.
 func Load() (*Registry, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("getting home directory: %w", err)
-	}
-
-	pluginsFile := filepath.Join(home, ".ocmgr", "plugins", "plugins.toml")
+	pluginsFile := filepath.Join(config.ConfigDir(), "plugins", "plugins.toml")
 
 	// Check if file exists
 	if _, err := os.Stat(pluginsFile); os.IsNotExist(err) {
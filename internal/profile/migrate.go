@@ -0,0 +1,68 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurrentSchemaVersion is the profile.toml format version written by this
+// build of ocmgr.
+const CurrentSchemaVersion = 2
+
+// migration upgrades a profile from schema version from to from+1.
+type migration struct {
+	from  int
+	apply func(p *Profile) error
+}
+
+// migrations holds one step per schema version bump, in order. To change
+// the format, bump CurrentSchemaVersion and append a step here.
+var migrations = []migration{
+	{from: 1, apply: migrateV1},
+}
+
+// Migrate upgrades p in place from its SchemaVersion to
+// CurrentSchemaVersion, running every registered step in order. A
+// missing version is treated as 1. Profiles written by a newer ocmgr are
+// rejected rather than silently misread.
+func Migrate(p *Profile) error {
+	if p.SchemaVersion == 0 {
+		p.SchemaVersion = 1
+	}
+	if p.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("schema_version %d is newer than this ocmgr supports (%d); upgrade ocmgr", p.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.from != p.SchemaVersion {
+			continue
+		}
+		if err := m.apply(p); err != nil {
+			return fmt.Errorf("migrating schema_version %d to %d: %w", m.from, m.from+1, err)
+		}
+		p.SchemaVersion = m.from + 1
+	}
+
+	if p.SchemaVersion != CurrentSchemaVersion {
+		return fmt.Errorf("no migration from schema_version %d", p.SchemaVersion)
+	}
+	return nil
+}
+
+// migrateV1 upgrades version 1 profiles, where extends was a single
+// free-form string, to the list form: names are trimmed and empty or
+// repeated entries dropped.
+func migrateV1(p *Profile) error {
+	var parents Parents
+	seen := make(map[string]bool, len(p.Extends))
+	for _, name := range p.Extends {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		parents = append(parents, name)
+	}
+	p.Extends = parents
+	return nil
+}
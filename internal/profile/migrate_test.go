@@ -0,0 +1,85 @@
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		extends Parents
+		want    Parents
+	}{
+		{"missing version", 0, Parents{" base "}, Parents{"base"}},
+		{"v1 single parent", 1, Parents{"base"}, Parents{"base"}},
+		{"v1 blank and repeated parents", 1, Parents{"base", " ", "go", "base"}, Parents{"base", "go"}},
+		{"v1 no parents", 1, nil, nil},
+		{"current version is left alone", CurrentSchemaVersion, Parents{" base "}, Parents{" base "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Profile{SchemaVersion: tt.version, Extends: tt.extends}
+			if err := Migrate(p); err != nil {
+				t.Fatal(err)
+			}
+			if p.SchemaVersion != CurrentSchemaVersion {
+				t.Errorf("SchemaVersion = %d, want %d", p.SchemaVersion, CurrentSchemaVersion)
+			}
+			if !slices.Equal(p.Extends, tt.want) {
+				t.Errorf("Extends = %q, want %q", p.Extends, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateRejectsNewerSchema(t *testing.T) {
+	p := &Profile{SchemaVersion: CurrentSchemaVersion + 1}
+	if err := Migrate(p); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Migrate() = %v, want a newer-schema error", err)
+	}
+}
+
+func TestMigrateRequiresEveryStep(t *testing.T) {
+	orig := migrations
+	migrations = nil
+	t.Cleanup(func() { migrations = orig })
+
+	p := &Profile{SchemaVersion: 1}
+	if err := Migrate(p); err == nil || !strings.Contains(err.Error(), "no migration from schema_version 1") {
+		t.Errorf("Migrate() = %v, want a missing-migration error", err)
+	}
+}
+
+func TestLoadMigratesAndSaveStamps(t *testing.T) {
+	dir := t.TempDir()
+	v1 := "[profile]\nname = \"go\"\nextends = \"base\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "profile.toml"), []byte(v1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadProfile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.SchemaVersion != CurrentSchemaVersion || !slices.Equal(p.Extends, Parents{"base"}) {
+		t.Fatalf("loaded schema_version %d, extends %q", p.SchemaVersion, p.Extends)
+	}
+
+	p.SchemaVersion = 1
+	if err := SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "profile.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("schema_version = %d", CurrentSchemaVersion); !strings.Contains(string(data), want) {
+		t.Errorf("saved profile.toml does not contain %q:\n%s", want, data)
+	}
+}
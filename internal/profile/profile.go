@@ -2,8 +2,9 @@
 // reading, writing, validating, scaffolding, and listing profile contents.
 //
 // A profile is a directory (e.g. ~/.ocmgr/profiles/go/) that contains a
-// profile.toml metadata file and up to four content subdirectories:
-// agents/, commands/, skills/, and plugins/.
+// profile.toml metadata file and content subdirectories: agents/,
+// commands/, skills/ and plugins/, plus any others listed in the
+// defaults.content_dirs setting.
 package profile
 
 import (
@@ -13,6 +14,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -37,8 +40,75 @@ func ValidateName(name string) error {
 	return nil
 }
 
+// validVersion matches a semantic version (https://semver.org), such as
+// 1.2.3, 1.0.0-beta.1 or 2.0.0+build.5.
+var validVersion = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// ValidateVersion checks that version is empty or a semantic version.
+func ValidateVersion(version string) error {
+	if version == "" || validVersion.MatchString(version) {
+		return nil
+	}
+	return fmt.Errorf("invalid version %q: must be a semantic version like 1.2.3", version)
+}
+
+// InitialVersion is the version given to a newly snapshotted profile.
+const InitialVersion = "0.1.0"
+
+// BumpVersion increments the "major", "minor" or "patch" component of
+// the semantic version, resetting the components after it and dropping
+// any prerelease or build suffix: bumping the minor version of 1.2.3
+// gives 1.3.0. A prerelease is bumped to its release when that is of the
+// requested part, as in semver tools: a patch bump of 1.2.4-rc.1 gives
+// 1.2.4 and a minor bump of 1.3.0-rc.1 gives 1.3.0. An empty version
+// counts as 0.0.0.
+func BumpVersion(version, part string) (string, error) {
+	if version == "" {
+		version = "0.0.0"
+	}
+	m := validVersion.FindStringSubmatch(version)
+	if m == nil {
+		return "", fmt.Errorf("cannot bump version %q: not a semantic version like 1.2.3; set a valid version in profile.toml first", version)
+	}
+	var nums [3]int
+	for i := range nums {
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return "", fmt.Errorf("cannot bump version %q: %w", version, err)
+		}
+		nums[i] = n
+	}
+	major, minor, patch := nums[0], nums[1], nums[2]
+	prerelease := m[4] != ""
+
+	switch part {
+	case "major":
+		if !prerelease || minor != 0 || patch != 0 {
+			major++
+		}
+		minor, patch = 0, 0
+	case "minor":
+		if !prerelease || patch != 0 {
+			minor++
+		}
+		patch = 0
+	case "patch":
+		if !prerelease {
+			patch++
+		}
+	default:
+		return "", fmt.Errorf("invalid version part %q; must be one of: major, minor, patch", part)
+	}
+	return fmt.Sprintf("%d.%d.%d", major, minor, patch), nil
+}
+
 // Profile represents the metadata and location of an ocmgr profile.
 type Profile struct {
+	// SchemaVersion is the profile.toml format version the profile was
+	// written with. Files without it are version 1; LoadProfile migrates
+	// older versions and SaveProfile stamps CurrentSchemaVersion.
+	SchemaVersion int `toml:"schema_version"`
 	// Name is the short identifier for the profile (required).
 	Name string `toml:"name"`
 	// Description is a human-readable summary of the profile.
@@ -49,12 +119,91 @@ type Profile struct {
 	Author string `toml:"author"`
 	// Tags is an optional list of keywords for discovery.
 	Tags []string `toml:"tags"`
-	// Extends names another profile that this one inherits from.
-	Extends string `toml:"extends"`
+	// Extends names the profiles that this one inherits from. In
+	// profile.toml it may be written as a single string or as an array.
+	Extends Parents `toml:"extends"`
+	// Locked marks a profile as protected: commands that would delete or
+	// overwrite it refuse to unless forced. It is advisory only.
+	Locked bool `toml:"locked,omitempty"`
+	// Source records where the profile was imported from. It is only set
+	// for profiles imported from a GitHub URL.
+	Source *Source `toml:"source,omitempty"`
 	// Path is the absolute directory path on disk. It is not serialized to TOML.
 	Path string `toml:"-"`
 }
 
+// Source describes the upstream location of an imported profile so that
+// later runs can check whether the upstream copy has changed.
+type Source struct {
+	// Repo is the owner/repo slug on GitHub.
+	Repo string `toml:"repo"`
+	// Branch is the branch (or tag) the profile was imported from.
+	Branch string `toml:"branch"`
+	// Path is the profile directory relative to the repository root.
+	Path string `toml:"path"`
+	// Commit is the commit SHA that was checked out at import time.
+	Commit string `toml:"commit"`
+}
+
+// URL returns the GitHub tree URL that the profile was imported from.
+func (s *Source) URL() string {
+	return fmt.Sprintf("https://github.com/%s/tree/%s/%s", s.Repo, s.Branch, s.Path)
+}
+
+// Parents is the list of profiles named by a profile's extends field.
+// It decodes from either a single TOML string or an array of strings and
+// is written back as a string when there is only one parent, so existing
+// profile.toml files keep their original form.
+type Parents []string
+
+// UnmarshalTOML implements toml.Unmarshaler.
+func (ps *Parents) UnmarshalTOML(v interface{}) error {
+	switch val := v.(type) {
+	case string:
+		if strings.TrimSpace(val) == "" {
+			*ps = nil
+		} else {
+			*ps = Parents{val}
+		}
+	case []interface{}:
+		out := make(Parents, 0, len(val))
+		for _, item := range val {
+			name, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("extends: expected string, got %T", item)
+			}
+			out = append(out, name)
+		}
+		*ps = out
+	default:
+		return fmt.Errorf("extends: expected string or array of strings, got %T", v)
+	}
+	return nil
+}
+
+// MarshalTOML implements toml.Marshaler.
+func (ps Parents) MarshalTOML() ([]byte, error) {
+	var v interface{} = []string(ps)
+	switch len(ps) {
+	case 0:
+		v = ""
+	case 1:
+		v = ps[0]
+	}
+
+	// Let the encoder handle quoting, then strip the "v = " key prefix.
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"v": v}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(bytes.TrimPrefix(buf.Bytes(), []byte("v = "))), nil
+}
+
+// String returns the parents as a comma-separated list.
+func (ps Parents) String() string {
+	return strings.Join(ps, ", ")
+}
+
 // profileTOML is the on-disk TOML representation that wraps Profile
 // in a [profile] table.
 type profileTOML struct {
@@ -73,14 +222,57 @@ type Contents struct {
 	Plugins []string
 	// HasPackageJSON indicates whether plugins/package.json exists.
 	HasPackageJSON bool
+	// Other lists relative paths to every file under the configured
+	// content directories other than the four built-in ones, such as
+	// "rules/go.md".
+	Other []string
 }
 
-// ContentDirs returns the four content subdirectory names that a profile
-// may contain.
-func ContentDirs() []string {
+// ErrLocked is wrapped by the errors returned when a command refuses to
+// modify a locked profile.
+var ErrLocked = errors.New("profile is locked")
+
+// CheckUnlocked returns an error wrapping ErrLocked if the profile in
+// dir is locked. A missing or unreadable profile.toml counts as
+// unlocked, so callers can use it before creating a profile too.
+func CheckUnlocked(dir string) error {
+	p, err := LoadProfile(dir)
+	if err != nil || !p.Locked {
+		return nil
+	}
+	return LockedError(p.Name)
+}
+
+// LockedError returns the error reported for the locked profile name.
+func LockedError(name string) error {
+	return fmt.Errorf("%w: %s (run \"ocmgr profile unlock %s\" or pass --force)", ErrLocked, name, name)
+}
+
+// DefaultContentDirs returns the four built-in content subdirectory
+// names that a profile may contain.
+func DefaultContentDirs() []string {
 	return []string{"agents", "commands", "skills", "plugins"}
 }
 
+// contentDirs is the configured list returned by ContentDirs.
+var contentDirs = DefaultContentDirs()
+
+// ContentDirs returns the content subdirectory names that a profile may
+// contain: the four built-in ones unless SetContentDirs replaced them
+// with the defaults.content_dirs setting.
+func ContentDirs() []string {
+	return slices.Clone(contentDirs)
+}
+
+// SetContentDirs replaces the list returned by ContentDirs. An empty
+// list restores the defaults.
+func SetContentDirs(dirs []string) {
+	if len(dirs) == 0 {
+		dirs = DefaultContentDirs()
+	}
+	contentDirs = slices.Clone(dirs)
+}
+
 // LoadProfile reads profile.toml from dir and returns the parsed Profile.
 // The returned Profile's Path field is set to the absolute path of dir.
 func LoadProfile(dir string) (*Profile, error) {
@@ -103,6 +295,9 @@ func LoadProfile(dir string) (*Profile, error) {
 
 	p := &doc.Profile
 	p.Path = absDir
+	if err := Migrate(p); err != nil {
+		return nil, fmt.Errorf("%s: %w", tomlPath, err)
+	}
 	return p, nil
 }
 
@@ -117,6 +312,7 @@ func SaveProfile(p *Profile) error {
 		return fmt.Errorf("creating profile directory: %w", err)
 	}
 
+	p.SchemaVersion = CurrentSchemaVersion
 	doc := profileTOML{Profile: *p}
 
 	var buf bytes.Buffer
@@ -233,11 +429,36 @@ func ListContents(p *Profile) (*Contents, error) {
 		}
 	}
 
+	// Configured extra directories — every file, recursively.
+	for _, dir := range ContentDirs() {
+		if slices.Contains(DefaultContentDirs(), dir) {
+			continue
+		}
+		err := filepath.WalkDir(filepath.Join(p.Path, dir), func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(p.Path, path)
+			if err != nil {
+				return err
+			}
+			c.Other = append(c.Other, rel)
+			return nil
+		})
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("listing %s: %w", dir, err)
+		}
+	}
+
 	return c, nil
 }
 
 // ScaffoldProfile creates an empty profile directory at dir/<name>
-// containing a profile.toml and the four empty content subdirectories.
+// containing a profile.toml and an empty directory for each content
+// directory.
 // It returns the newly created Profile.
 func ScaffoldProfile(dir string, name string) (*Profile, error) {
 	if err := ValidateName(name); err != nil {
@@ -270,6 +491,43 @@ func ScaffoldProfile(dir string, name string) (*Profile, error) {
 	return p, nil
 }
 
+// PruneEmptyDirs removes content directories of p that contain no files
+// (directories holding only empty subdirectories count as empty). It
+// returns the names of the directories that were removed.
+func PruneEmptyDirs(p *Profile) ([]string, error) {
+	var pruned []string
+	for _, d := range ContentDirs() {
+		dirPath := filepath.Join(p.Path, d)
+		if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+			continue
+		}
+
+		hasFiles := false
+		err := filepath.WalkDir(dirPath, func(_ string, e os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !e.IsDir() {
+				hasFiles = true
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			return pruned, fmt.Errorf("scanning %s: %w", d, err)
+		}
+		if hasFiles {
+			continue
+		}
+
+		if err := os.RemoveAll(dirPath); err != nil {
+			return pruned, fmt.Errorf("removing %s: %w", d, err)
+		}
+		pruned = append(pruned, d)
+	}
+	return pruned, nil
+}
+
 // listMD returns the names of all *.md files in the given directory.
 // If the directory does not exist the underlying os error is returned
 // so callers can check with errors.Is(err, os.ErrNotExist).
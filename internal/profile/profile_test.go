@@ -0,0 +1,38 @@
+package profile
+
+import "testing"
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		version, part, want string
+	}{
+		{"", "patch", "0.0.1"},
+		{"1.2.3", "patch", "1.2.4"},
+		{"1.2.3", "minor", "1.3.0"},
+		{"1.2.3", "major", "2.0.0"},
+		{"1.2.3+build.5", "patch", "1.2.4"},
+		{"1.2.4-rc.1", "patch", "1.2.4"},
+		{"1.2.4-rc.1", "minor", "1.3.0"},
+		{"1.3.0-rc.1", "minor", "1.3.0"},
+		{"1.3.0-rc.1", "major", "2.0.0"},
+		{"2.0.0-rc.1", "major", "2.0.0"},
+	}
+	for _, tt := range tests {
+		got, err := BumpVersion(tt.version, tt.part)
+		if err != nil || got != tt.want {
+			t.Errorf("BumpVersion(%q, %q) = %q, %v; want %q", tt.version, tt.part, got, err, tt.want)
+		}
+	}
+}
+
+func TestBumpVersionErrors(t *testing.T) {
+	for _, tt := range []struct{ version, part string }{
+		{"1.2", "patch"},
+		{"1.2.3", "build"},
+		{"99999999999999999999.0.0", "patch"},
+	} {
+		if got, err := BumpVersion(tt.version, tt.part); err == nil {
+			t.Errorf("BumpVersion(%q, %q) = %q, want an error", tt.version, tt.part, got)
+		}
+	}
+}
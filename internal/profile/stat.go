@@ -0,0 +1,90 @@
+package profile
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// DirStat summarises the files below one content directory.
+type DirStat struct {
+	// Dir is the content directory name, e.g. "agents".
+	Dir   string
+	Files int
+	Bytes int64
+}
+
+// Stats summarises the size of a profile.
+type Stats struct {
+	// Dirs has one entry per content directory, in ContentDirs order,
+	// including empty and missing ones.
+	Dirs []DirStat
+	// Files and Bytes cover every file in the profile, including
+	// profile.toml and anything outside the content directories.
+	Files int
+	Bytes int64
+}
+
+// Stat walks p.Path and counts the regular files and their total size,
+// per content directory and overall. .git directories are skipped.
+func Stat(p *Profile) (*Stats, error) {
+	dirs := ContentDirs()
+	st := &Stats{Dirs: make([]DirStat, len(dirs))}
+	index := make(map[string]int, len(dirs))
+	for i, d := range dirs {
+		st.Dirs[i].Dir = d
+		index[d] = i
+	}
+
+	err := filepath.WalkDir(p.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		st.Files++
+		st.Bytes += info.Size()
+
+		rel, err := filepath.Rel(p.Path, path)
+		if err != nil {
+			return err
+		}
+		top, _, nested := strings.Cut(filepath.ToSlash(rel), "/")
+		if i, ok := index[top]; ok && nested {
+			st.Dirs[i].Files++
+			st.Dirs[i].Bytes += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("measuring profile %q: %w", p.Name, err)
+	}
+	return st, nil
+}
+
+// FormatSize renders a size in bytes with a binary unit, e.g. "40.0 KiB".
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
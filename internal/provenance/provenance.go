@@ -0,0 +1,144 @@
+// Package provenance records which profiles were applied to a project's
+// .opencode/ directory, and the files each of them wrote, in a small
+// .ocmgr-applied.toml file, so anyone inspecting the repository can tell
+// how its OpenCode configuration was generated and commands such as
+// uninit know what a profile added.
+package provenance
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileName is the name of the provenance file inside .opencode/.
+const FileName = ".ocmgr-applied.toml"
+
+// Entry describes a single profile application.
+type Entry struct {
+	// Name is the profile name.
+	Name string `toml:"name"`
+	// Version is the profile version at the time it was applied.
+	Version string `toml:"version,omitempty"`
+	// AppliedAt is when the profile was last applied.
+	AppliedAt time.Time `toml:"applied_at"`
+	// Files lists the paths, relative to .opencode/, that the profile
+	// wrote, sorted. It is empty for entries recorded by older versions.
+	Files []string `toml:"files,omitempty"`
+	// Hashes maps paths in Files to the SHA-256 of the content the
+	// profile wrote, so a later upgrade can tell whether a file has been
+	// changed in the project since.
+	Hashes map[string]string `toml:"hashes,omitempty"`
+	// Vars are the --var values the profile's templates were rendered
+	// with, so an upgrade renders the new version the same way.
+	Vars map[string]string `toml:"vars,omitempty"`
+}
+
+// Provenance is the on-disk representation of .ocmgr-applied.toml.
+type Provenance struct {
+	// Profiles lists the applied profiles in the order they were first
+	// applied.
+	Profiles []Entry `toml:"profile"`
+}
+
+// Load reads the provenance file from targetDir (a .opencode/ directory).
+// An empty Provenance is returned if the file does not exist.
+func Load(targetDir string) (*Provenance, error) {
+	p := &Provenance{}
+
+	data, err := os.ReadFile(filepath.Join(targetDir, FileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", FileName, err)
+	}
+
+	if _, err := toml.Decode(string(data), p); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", FileName, err)
+	}
+	return p, nil
+}
+
+// Record adds or updates the entry for the named profile. Existing entries
+// keep their position so the file reflects the original layering order.
+// files maps the paths written to their content hash ("" if unknown);
+// they are added to those already recorded, since a re-run that skips
+// existing files leaves the earlier ones in place.
+func (p *Provenance) Record(name, version string, at time.Time, files map[string]string) {
+	e := p.Find(name)
+	if e == nil {
+		p.Profiles = append(p.Profiles, Entry{Name: name})
+		e = &p.Profiles[len(p.Profiles)-1]
+	}
+	e.Version = version
+	e.AppliedAt = at
+
+	paths := make([]string, 0, len(files))
+	for f, hash := range files {
+		f = filepath.ToSlash(f)
+		paths = append(paths, f)
+		if hash == "" {
+			continue
+		}
+		if e.Hashes == nil {
+			e.Hashes = make(map[string]string)
+		}
+		e.Hashes[f] = hash
+	}
+	e.Files = mergeFiles(e.Files, paths)
+}
+
+// Find returns the entry for the named profile, or nil if it has not
+// been applied.
+func (p *Provenance) Find(name string) *Entry {
+	for i := range p.Profiles {
+		if p.Profiles[i].Name == name {
+			return &p.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// mergeFiles returns the sorted union of a and b.
+func mergeFiles(a, b []string) []string {
+	out := append(slices.Clone(a), b...)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// Remove deletes the entry for the named profile and reports whether
+// there was one.
+func (p *Provenance) Remove(name string) bool {
+	for i := range p.Profiles {
+		if p.Profiles[i].Name == name {
+			p.Profiles = append(p.Profiles[:i], p.Profiles[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the provenance file into targetDir, creating the directory
+// if needed.
+func (p *Provenance) Save(targetDir string) error {
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by ocmgr. Lists the profiles applied to this directory.\n")
+	if err := toml.NewEncoder(&buf).Encode(p); err != nil {
+		return fmt.Errorf("encoding %s: %w", FileName, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(targetDir, FileName), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", FileName, err)
+	}
+	return nil
+}
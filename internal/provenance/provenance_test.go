@@ -0,0 +1,77 @@
+package provenance
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecordMultipleProfiles(t *testing.T) {
+	dir := t.TempDir()
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := first.Add(time.Hour)
+
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Record("base", "1.0.0", first, map[string]string{"agents/base.md": "h1"})
+	p.Record("go", "0.2.0", first, map[string]string{"agents/go.md": "h2", "commands/test.md": ""})
+	if err := p.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// A later init re-applies base, which now writes one more file.
+	p, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Record("base", "1.1.0", later, map[string]string{"skills/s/SKILL.md": "h3"})
+	if err := p.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Profiles) != 2 || p.Profiles[0].Name != "base" || p.Profiles[1].Name != "go" {
+		t.Fatalf("Profiles = %+v, want base then go", p.Profiles)
+	}
+
+	base := p.Find("base")
+	if base.Version != "1.1.0" || !base.AppliedAt.Equal(later) {
+		t.Errorf("base = %s at %v, want 1.1.0 at %v", base.Version, base.AppliedAt, later)
+	}
+	if want := []string{"agents/base.md", "skills/s/SKILL.md"}; !slices.Equal(base.Files, want) {
+		t.Errorf("base files = %v, want %v", base.Files, want)
+	}
+	if base.Hashes["agents/base.md"] != "h1" || base.Hashes["skills/s/SKILL.md"] != "h3" {
+		t.Errorf("base hashes = %v", base.Hashes)
+	}
+
+	goEntry := p.Find("go")
+	if goEntry.Version != "0.2.0" || !goEntry.AppliedAt.Equal(first) {
+		t.Errorf("go = %s at %v, want 0.2.0 at %v", goEntry.Version, goEntry.AppliedAt, first)
+	}
+	if want := []string{"agents/go.md", "commands/test.md"}; !slices.Equal(goEntry.Files, want) {
+		t.Errorf("go files = %v, want %v", goEntry.Files, want)
+	}
+	if _, ok := goEntry.Hashes["commands/test.md"]; ok {
+		t.Error("an unknown hash was recorded")
+	}
+
+	if !p.Remove("base") || p.Find("base") != nil || len(p.Profiles) != 1 {
+		t.Errorf("Remove(base) left %+v", p.Profiles)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	p, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Profiles) != 0 {
+		t.Errorf("Profiles = %+v, want none", p.Profiles)
+	}
+}
@@ -1,4 +1,4 @@
-// Package resolver walks the profile "extends" dependency chain and
+// Package resolver walks the profile "extends" dependency graph and
 // returns a flattened, de-duplicated list of profile names in apply
 // order (parents first, children last).
 package resolver
@@ -10,82 +10,164 @@ import (
 
 // Loader retrieves the extends field for a given profile name.
 // It is typically backed by store.Get(name).Extends.
-type Loader func(name string) (extends string, err error)
+type Loader func(name string) (parents []string, err error)
+
+// MaxDepth is the longest extends chain Resolve accepts, counting the
+// requested profile itself. A graph that goes deeper is reported as an
+// error, which catches runaway graphs before they exhaust the stack.
+const MaxDepth = 32
 
 // Resolve expands the requested profile names by walking each
-// profile's extends chain.  The returned slice is ordered so that
+// profile's extends graph.  The returned slice is ordered so that
 // parent profiles appear before their children and no name appears
-// more than once.
+// more than once.  When a profile extends several parents they are
+// visited depth-first in declaration order, so the result is
+// deterministic, and an ancestor shared by several parents (a diamond)
+// is included once, before the first profile that needs it.
 //
 // A circular dependency (e.g. a → b → a) is detected and reported
-// as an error.
+// as an error, as is a chain deeper than MaxDepth.
 func Resolve(names []string, load Loader) ([]string, error) {
-	// seen tracks profiles already placed in the result list so we
-	// never add a duplicate.
-	seen := make(map[string]bool)
-
-	var result []string
+	w := &walker{
+		load: load,
+		seen: make(map[string]bool),
+	}
 
 	for _, name := range names {
-		chain, err := walkChain(name, load)
-		if err != nil {
+		if err := w.walk(name, nil); err != nil {
 			return nil, err
 		}
-		// chain is ordered [root ancestor … name].
-		for _, n := range chain {
-			if !seen[n] {
-				seen[n] = true
-				result = append(result, n)
-			}
-		}
 	}
 
-	return result, nil
+	return w.result, nil
 }
 
-// walkChain builds the full dependency chain for a single profile,
-// from the root ancestor down to the given name.
+// walker holds the state shared by one Resolve call.
+type walker struct {
+	load Loader
+
+	// seen tracks profiles already placed in the result list so we
+	// never add a duplicate.
+	seen   map[string]bool
+	result []string
+}
+
+// walk visits name's parents depth-first and then appends name itself
+// to result, so every ancestor precedes its descendants.
 //
-// It returns an error if a circular dependency is detected or the
-// loader fails.
-func walkChain(name string, load Loader) ([]string, error) {
-	// visiting tracks the current chain to detect cycles.
-	visiting := make(map[string]bool)
-	var chain []string
-
-	current := name
-	for current != "" {
-		if visiting[current] {
-			return nil, fmt.Errorf("circular dependency detected: %s", formatCycle(chain, current))
+// chain holds the profiles on the current path from the requested name
+// down to (but excluding) name and is used to detect cycles and limit
+// the depth.  It returns an error if a circular dependency is detected,
+// the chain is too deep, or the loader fails.
+func (w *walker) walk(name string, chain []string) error {
+	for i, n := range chain {
+		if n == name {
+			return fmt.Errorf("circular dependency detected: %s", formatCycle(chain, i))
 		}
-		visiting[current] = true
-		chain = append(chain, current)
+	}
+	if w.seen[name] {
+		return nil
+	}
+	if len(chain) >= MaxDepth {
+		return fmt.Errorf("extends chain is deeper than %d profiles: %s → %s …",
+			MaxDepth, strings.Join(chain, " → "), name)
+	}
+
+	parents, err := w.load(name)
+	if err != nil {
+		return fmt.Errorf("resolving profile %q: %w", name, err)
+	}
 
-		extends, err := load(current)
-		if err != nil {
-			return nil, fmt.Errorf("resolving profile %q: %w", current, err)
+	chain = append(chain, name)
+	for _, parent := range parents {
+		parent = strings.TrimSpace(parent)
+		if parent == "" {
+			continue
+		}
+		if err := w.walk(parent, chain); err != nil {
+			return err
 		}
-		current = strings.TrimSpace(extends)
 	}
 
-	// chain is [name, parent, grandparent, …].
-	// Reverse it so the root ancestor comes first.
-	reverse(chain)
-	return chain, nil
+	w.seen[name] = true
+	w.result = append(w.result, name)
+	return nil
 }
 
-// reverse reverses a string slice in place.
-func reverse(s []string) {
-	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
-		s[i], s[j] = s[j], s[i]
+// formatCycle describes the cycle that closes back on chain[start], along
+// with the path that led into it, like "b → c → b (reached via a → b)".
+func formatCycle(chain []string, start int) string {
+	loop := append(append([]string{}, chain[start:]...), chain[start])
+	msg := strings.Join(loop, " → ")
+	if start > 0 {
+		msg += fmt.Sprintf(" (reached via %s)", strings.Join(chain[:start+1], " → "))
 	}
+	return msg
 }
 
-// formatCycle produces a human-readable cycle description like
-// "a → b → c → a".
-func formatCycle(chain []string, loopBack string) string {
-	parts := make([]string, len(chain)+1)
-	copy(parts, chain)
-	parts[len(chain)] = loopBack
-	return strings.Join(parts, " → ")
+// Node is one profile in the extends tree built by Tree.
+type Node struct {
+	// Name is the profile name.
+	Name string
+	// Parents are the profiles Name extends, in declaration order.
+	Parents []*Node
+	// Cycle marks a profile that already appears higher up on the same
+	// path; its parents are not expanded again.
+	Cycle bool
+	// Repeat marks a profile shared with an earlier branch of the tree
+	// (a diamond); its parents are only expanded the first time.
+	Repeat bool
+	// Err is set when the profile's extends could not be loaded.
+	Err error
+}
+
+// Tree builds the extends graph below name as a tree, for display.
+// Unlike Resolve it does not stop at problems: cycles, shared ancestors
+// and profiles that fail to load are marked on their nodes instead.
+func Tree(name string, load Loader) *Node {
+	return buildTree(name, load, nil, make(map[string]bool))
+}
+
+func buildTree(name string, load Loader, chain []string, expanded map[string]bool) *Node {
+	node := &Node{Name: name}
+	for _, n := range chain {
+		if n == name {
+			node.Cycle = true
+			return node
+		}
+	}
+	if expanded[name] {
+		node.Repeat = true
+		return node
+	}
+	expanded[name] = true
+
+	parents, err := load(name)
+	if err != nil {
+		node.Err = err
+		return node
+	}
+
+	chain = append(chain, name)
+	for _, parent := range parents {
+		parent = strings.TrimSpace(parent)
+		if parent == "" {
+			continue
+		}
+		node.Parents = append(node.Parents, buildTree(parent, load, chain, expanded))
+	}
+	return node
+}
+
+// HasCycle reports whether any node in the tree is marked as a cycle.
+func (n *Node) HasCycle() bool {
+	if n.Cycle {
+		return true
+	}
+	for _, p := range n.Parents {
+		if p.HasCycle() {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,69 @@
+package resolver
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// graph returns a Loader backed by a map of profile name to parents.
+func graph(g map[string][]string) Loader {
+	return func(name string) ([]string, error) {
+		parents, ok := g[name]
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found", name)
+		}
+		return parents, nil
+	}
+}
+
+func TestResolveDiamond(t *testing.T) {
+	load := graph(map[string][]string{
+		"base":   nil,
+		"go":     {"base"},
+		"python": {"base"},
+		"app":    {"go", "python"},
+	})
+
+	got, err := Resolve([]string{"app"}, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"base", "go", "python", "app"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve = %v, want %v", got, want)
+	}
+}
+
+func TestResolveCycle(t *testing.T) {
+	load := graph(map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"b"},
+	})
+
+	_, err := Resolve([]string{"a"}, load)
+	if err == nil {
+		t.Fatal("no error for a cycle")
+	}
+	if want := "b → c → b (reached via a → b)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("err = %v, want it to contain %q", err, want)
+	}
+}
+
+func TestResolveMaxDepth(t *testing.T) {
+	g := make(map[string][]string)
+	for i := range MaxDepth + 1 {
+		g[fmt.Sprint(i)] = []string{fmt.Sprint(i + 1)}
+	}
+	g[fmt.Sprint(MaxDepth+1)] = nil
+
+	if _, err := Resolve([]string{"0"}, graph(g)); err == nil || !strings.Contains(err.Error(), "deeper than") {
+		t.Errorf("err = %v, want a depth error", err)
+	}
+
+	if _, err := Resolve([]string{"2"}, graph(g)); err != nil {
+		t.Errorf("chain of exactly MaxDepth profiles: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package store
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/acchapm1/ocmgr/internal/config"
+)
+
+// LockFile is the name of the lock file, in the ocmgr config directory,
+// that serialises commands modifying the store.
+const LockFile = ".lock"
+
+// lockTimeout is how long Lock waits for another holder to release the
+// lock before giving up.
+var lockTimeout = 5 * time.Second
+
+// lockStaleAge is how old a lock file without a readable PID must be
+// before Lock treats it as stale. Its owner writes the PID straight
+// after creating it, so this only has to outlast that write.
+const lockStaleAge = time.Second
+
+// lockPoll is how often Lock retries while the lock is held.
+const lockPoll = 100 * time.Millisecond
+
+// ErrBusy is wrapped by the error Lock returns when another process
+// holds the store lock.
+var ErrBusy = errors.New("another ocmgr process is modifying the store")
+
+// lockPath returns the path of the store lock file.
+func lockPath() string {
+	return filepath.Join(config.ConfigDir(), LockFile)
+}
+
+// Lock acquires the store lock, a file containing the PID of its owner,
+// and returns a function that releases it. It should wrap operations
+// that modify profiles (delete, import, pull, snapshot); read-only
+// operations do not need it.
+//
+// If the lock is held, by another process or by another holder in this
+// one, Lock retries for a few seconds and then fails with an error
+// wrapping ErrBusy. A lock file left behind by a process that no longer
+// exists, or one that has held no PID for over a second, is removed. Lock is
+// not reentrant; use Store.Lock to nest.
+func Lock() (func(), error) {
+	path := lockPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		token, err := createLockFile(path)
+		if err == nil {
+			var once sync.Once
+			return func() { once.Do(func() { removeLockFile(path, token) }) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("creating lock file: %w", err)
+		}
+
+		pid, ok := lockOwner(path)
+		if lockStale(path, pid, ok) {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("removing stale lock file: %w", err)
+			}
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			owner := ""
+			if ok {
+				owner = fmt.Sprintf(" (pid %d)", pid)
+			}
+			return nil, fmt.Errorf("%w%s; try again once it has finished, or remove %s if no ocmgr is running", ErrBusy, owner, path)
+		}
+		time.Sleep(lockPoll)
+	}
+}
+
+// Lock acquires the store lock as Lock does, on behalf of s. It is
+// reentrant for s: while s holds the lock, further s.Lock calls (a
+// command locking the store and then calling s.Delete) succeed at once,
+// and the lock is released when the outermost call's function is.
+// Holders other than s wait as for Lock.
+func (s *Store) Lock() (func(), error) {
+	s.lock.mu.Lock()
+	defer s.lock.mu.Unlock()
+
+	if s.lock.depth == 0 {
+		release, err := Lock()
+		if err != nil {
+			return nil, err
+		}
+		s.lock.release = release
+	}
+	s.lock.depth++
+
+	var once sync.Once
+	return func() { once.Do(s.unlock) }, nil
+}
+
+// unlock releases one level of the lock taken by s.Lock.
+func (s *Store) unlock() {
+	s.lock.mu.Lock()
+	defer s.lock.mu.Unlock()
+
+	s.lock.depth--
+	if s.lock.depth == 0 {
+		s.lock.release()
+		s.lock.release = nil
+	}
+}
+
+// lockStale reports whether the lock file at path may be removed: its
+// owner pid (valid if ok) no longer exists, or it has held no PID for
+// longer than lockStaleAge.
+func lockStale(path string, pid int, ok bool) bool {
+	if ok {
+		return !processAlive(pid)
+	}
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) > lockStaleAge
+}
+
+// removeLockFile removes the lock file at path if it is still the one
+// created with token.
+func removeLockFile(path, token string) {
+	if data, err := os.ReadFile(path); err == nil && string(data) == token {
+		_ = os.Remove(path)
+	}
+}
+
+// createLockFile atomically creates the lock file at path and writes the
+// current PID to it, followed by a random token identifying this holder,
+// which it returns. It fails with an os.ErrExist error if the file
+// already exists.
+func createLockFile(path string) (string, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	token := fmt.Sprintf("%d %s\n", os.Getpid(), rand.Text())
+	_, err = f.WriteString(token)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return token, nil
+}
+
+// lockOwner returns the PID recorded in the lock file at path. ok is
+// false if the file cannot be read or does not hold a PID, for example
+// because its owner is still writing it.
+func lockOwner(path string) (pid int, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	field, _, _ := strings.Cut(strings.TrimSpace(string(data)), " ")
+	pid, err = strconv.Atoi(field)
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+// processAlive reports whether a process with the given PID exists. On
+// Unix this sends signal 0, which checks for existence without
+// affecting the process; on Windows finding the process is enough.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// shortLock points the config directory at a temporary directory and
+// shortens the lock timeout for the duration of the test.
+func shortLock(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	old := lockTimeout
+	lockTimeout = 300 * time.Millisecond
+	t.Cleanup(func() { lockTimeout = old })
+}
+
+func TestStoreLockIsReentrantForItsHolder(t *testing.T) {
+	shortLock(t)
+	s := &Store{Dir: t.TempDir()}
+
+	outer, err := s.Lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner, err := s.Lock()
+	if err != nil {
+		t.Fatalf("nested s.Lock: %v", err)
+	}
+	inner()
+	inner() // releasing twice must not release the outer level
+	if _, err := os.Stat(lockPath()); err != nil {
+		t.Fatalf("lock released by the inner level: %v", err)
+	}
+	outer()
+	if _, err := os.Stat(lockPath()); !os.IsNotExist(err) {
+		t.Errorf("lock file left after release: %v", err)
+	}
+}
+
+func TestLockExcludesOtherHoldersInProcess(t *testing.T) {
+	shortLock(t)
+	s := &Store{Dir: t.TempDir()}
+
+	unlock, err := s.Lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	if _, err := Lock(); !errors.Is(err, ErrBusy) {
+		t.Errorf("Lock while s holds it: err = %v, want ErrBusy", err)
+	}
+	other := &Store{Dir: s.Dir}
+	if _, err := other.Lock(); !errors.Is(err, ErrBusy) {
+		t.Errorf("other.Lock while s holds it: err = %v, want ErrBusy", err)
+	}
+}
+
+func TestLockRemovesOldLockWithoutPID(t *testing.T) {
+	shortLock(t)
+	if err := os.MkdirAll(filepath.Dir(lockPath()), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath(), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh empty lock may still be being written by its owner.
+	if lockStale(lockPath(), 0, false) {
+		t.Fatal("fresh empty lock treated as stale")
+	}
+
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath(), old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := Lock()
+	if err != nil {
+		t.Fatalf("old empty lock was not treated as stale: %v", err)
+	}
+	unlock()
+}
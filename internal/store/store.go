@@ -1,4 +1,5 @@
-// Package store manages the local profile store at ~/.ocmgr/profiles/.
+// Package store manages the local profile store, by default the
+// profiles directory in config.DataDir.
 //
 // Each subdirectory under the profiles directory represents a single profile
 // and is expected to contain a profile.toml file that can be loaded by the
@@ -6,30 +7,58 @@
 package store
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
 
 	"github.com/acchapm1/ocmgr/internal/config"
 	"github.com/acchapm1/ocmgr/internal/profile"
 )
 
+// ErrProfileNotFound is matched, via errors.Is, by the errors returned
+// when a profile does not exist in the store.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// NotFoundError reports that the named profile does not exist in the
+// store. It matches ErrProfileNotFound.
+type NotFoundError struct {
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("profile %q not found", e.Name)
+}
+
+// Is makes errors.Is(err, ErrProfileNotFound) true for a NotFoundError.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrProfileNotFound
+}
+
 // Store provides access to locally stored profiles on disk.
 type Store struct {
-	// Dir is the absolute path to the profiles directory (e.g. ~/.ocmgr/profiles).
+	// Dir is the absolute path to the profiles directory.
 	Dir string
+
+	// lock is the store lock held through this Store; see Store.Lock.
+	lock struct {
+		mu      sync.Mutex
+		depth   int
+		release func()
+	}
 }
 
 // NewStore creates a Store pointing to the configured profiles directory.
-// It reads the store path from config.toml, falling back to ~/.ocmgr/profiles
-// if the config cannot be loaded. The directory is created if it does not
+// It reads the store path from config.toml, falling back to the profiles
+// directory in config.DataDir if the config cannot be loaded. The directory is created if it does not
 // already exist.
 func NewStore() (*Store, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		// Fall back to default location if config can't be loaded.
-		dir := filepath.Join(config.ConfigDir(), "profiles")
+		dir := filepath.Join(config.DataDir(), "profiles")
 		return NewStoreAt(dir)
 	}
 	dir := config.ExpandPath(cfg.Store.Path)
@@ -91,7 +120,7 @@ func (s *Store) Get(name string) (*profile.Profile, error) {
 	dir := s.ProfileDir(name)
 
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("profile %q not found", name)
+		return nil, &NotFoundError{Name: name}
 	}
 
 	p, err := profile.LoadProfile(dir)
@@ -109,8 +138,9 @@ func (s *Store) Exists(name string) bool {
 }
 
 // Delete removes the profile directory for the given name. An error is
-// returned if the profile does not exist.
-func (s *Store) Delete(name string) error {
+// returned if the profile does not exist, or if it is locked and force
+// is false.
+func (s *Store) Delete(name string, force bool) error {
 	if err := profile.ValidateName(name); err != nil {
 		return err
 	}
@@ -118,8 +148,19 @@ func (s *Store) Delete(name string) error {
 	dir := s.ProfileDir(name)
 
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return fmt.Errorf("profile %q not found", name)
+		return &NotFoundError{Name: name}
+	}
+	if !force {
+		if err := profile.CheckUnlocked(dir); err != nil {
+			return err
+		}
+	}
+
+	unlock, err := s.Lock()
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
 	if err := os.RemoveAll(dir); err != nil {
 		return fmt.Errorf("deleting profile %q: %w", name, err)
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps the number of names returned by Suggest.
+const maxSuggestions = 3
+
+// Suggest returns the names of stored profiles that are close to name,
+// for "did you mean" hints: names within a small edit distance of it
+// (scaled with its length) and names that start with it or contain it.
+// Matches are ordered by distance, closest first. It returns nil if
+// the store cannot be listed or nothing is close.
+func (s *Store) Suggest(name string) []string {
+	profiles, err := s.List()
+	if err != nil {
+		return nil
+	}
+
+	lower := strings.ToLower(name)
+	threshold := len([]rune(name))/3 + 1
+
+	type match struct {
+		name string
+		dist int
+	}
+	var matches []match
+	for _, p := range profiles {
+		if p.Name == name {
+			continue
+		}
+		candidate := strings.ToLower(p.Name)
+		d := levenshtein(lower, candidate)
+		if d > threshold && !strings.Contains(candidate, lower) {
+			continue
+		}
+		matches = append(matches, match{name: p.Name, dist: d})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].dist < matches[j].dist
+	})
+	if len(matches) > maxSuggestions {
+		matches = matches[:maxSuggestions]
+	}
+
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// levenshtein returns the edit distance between a and b: the number of
+// single-rune insertions, deletions and substitutions needed to turn one
+// into the other.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
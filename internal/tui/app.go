@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -25,6 +26,7 @@ const (
 	viewEditor
 	viewSync
 	viewSnapshot
+	viewProfileCreate
 )
 
 // menuItem implements list.Item for the main menu.
@@ -44,7 +46,12 @@ type profileItem struct {
 
 func (i profileItem) Title() string       { return i.profile.Name }
 func (i profileItem) Description() string { return i.profile.Description }
-func (i profileItem) FilterValue() string { return i.profile.Name }
+
+// FilterValue lets the "/" filter match on description and tags as well
+// as the name.
+func (i profileItem) FilterValue() string {
+	return strings.Join(append([]string{i.profile.Name, i.profile.Description}, i.profile.Tags...), " ")
+}
 
 // Model is the top-level Bubble Tea model for the ocmgr TUI.
 type Model struct {
@@ -58,7 +65,19 @@ type Model struct {
 	profileList     list.Model
 	profiles        []*profile.Profile
 	selectedProfile *profile.Profile
-	profileDetail   string
+
+	// Profile detail: the fixed header and the scrolling file tree
+	profileDetail string
+	detailView    viewport.Model
+
+	// Pending profile deletion awaiting confirmation, and the profiles
+	// that extend it
+	deleteTarget     *profile.Profile
+	deleteDependents []string
+
+	// Tag picker overlay, and the tags the browser is narrowed to
+	tagPick   *tagPicker
+	tagFilter []string
 
 	// Init wizard
 	initWiz *initWizard
@@ -72,6 +91,19 @@ type Model struct {
 	// Snapshot wizard
 	snapWiz *snapshotWizard
 
+	// Create-profile wizard and the view to return to when it is cancelled
+	createWiz  *createWizard
+	createFrom view
+
+	// Editor command line from --editor; empty to use the configured one
+	editorOverride string
+
+	// Whether the keyboard help overlay is shown over the current view
+	showHelp bool
+
+	// OSC 52 clipboard sequence View emits until osc52SentMsg arrives
+	osc52 string
+
 	// Dimensions
 	width  int
 	height int
@@ -84,6 +116,12 @@ type Model struct {
 	store *store.Store
 }
 
+// SetEditor makes the profile editor open files with the given command
+// line (e.g. "code --wait") instead of the configured editor.
+func (m *Model) SetEditor(editor string) {
+	m.editorOverride = editor
+}
+
 // NewModel creates and returns a new TUI model.
 func NewModel() (Model, error) {
 	s, err := store.NewStore()
@@ -100,6 +138,7 @@ func NewModel() (Model, error) {
 	menuItems := []list.Item{
 		menuItem{title: "Init", desc: "Initialize .opencode/ from a profile"},
 		menuItem{title: "Profiles", desc: "Browse and manage profiles"},
+		menuItem{title: "New Profile", desc: "Create an empty profile"},
 		menuItem{title: "Sync", desc: "Synchronize profiles with GitHub"},
 		menuItem{title: "Snapshot", desc: "Capture .opencode/ as a new profile"},
 		menuItem{title: "Config", desc: "View and edit configuration"},
@@ -134,13 +173,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.menuList.SetSize(msg.Width, msg.Height-2)
-		if m.profileList.Items() != nil {
-			m.profileList.SetSize(msg.Width, msg.Height-2)
-		}
+		m.resize()
 		return m, nil
 
+	case osc52SentMsg:
+		m.osc52 = ""
+		return m, nil
+
+	case tea.MouseMsg:
+		if m.showHelp {
+			return m, nil
+		}
+
 	case tea.KeyMsg:
+		// The help overlay swallows every key except those that close it.
+		if m.showHelp {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "?", "esc":
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if msg.String() == "?" && !m.isTextInputActive() {
+			m.showHelp = true
+			return m, nil
+		}
+
 		// Global keys
 		switch {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+c"))):
@@ -180,6 +240,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateSync(msg)
 	case viewSnapshot:
 		return m.updateSnapshot(msg)
+	case viewProfileCreate:
+		return m.updateCreate(msg)
 	}
 
 	return m, nil
@@ -187,6 +249,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.Model.
 func (m Model) View() string {
+	return m.osc52 + m.viewCurrent()
+}
+
+// viewCurrent renders the current view, or the help overlay over it.
+func (m Model) viewCurrent() string {
+	if m.showHelp {
+		return m.viewHelp()
+	}
 	switch m.currentView {
 	case viewMenu:
 		return m.viewMenu()
@@ -202,10 +272,74 @@ func (m Model) View() string {
 		return m.viewSync()
 	case viewSnapshot:
 		return m.viewSnapshot()
+	case viewProfileCreate:
+		return m.viewCreate()
 	}
 	return ""
 }
 
+// inputMargin is the room left beside a text input for its label and
+// prompt when it is narrowed to fit the window.
+const inputMargin = 20
+
+// inputWidth returns the width for a text input that would like to be
+// preferred columns wide, narrowed to fit the window once its size is
+// known.
+func (m Model) inputWidth(preferred int) int {
+	if m.width == 0 {
+		return preferred
+	}
+	return min(preferred, max(m.width-inputMargin, 10))
+}
+
+// resize fits every list, text input and viewport that currently exists
+// to the window size in m.width and m.height. Views created later read
+// the same fields when they are built.
+func (m *Model) resize() {
+	m.menuList.SetSize(m.width, m.height-2)
+	if m.profileList.Items() != nil {
+		m.profileList.SetSize(m.width, m.height-2)
+	}
+	if m.currentView == viewProfileDetail {
+		m.detailView.Width = m.width
+		m.detailView.Height = m.detailViewHeight()
+	}
+	if m.syncSt != nil {
+		m.syncSt.list.SetSize(m.width, m.syncListHeight())
+		m.syncSt.errView.Width = m.width
+		m.syncSt.errView.Height = m.syncListHeight()
+	}
+	if wiz := m.initWiz; wiz != nil {
+		wiz.profileList.SetSize(m.width, m.height-4)
+		wiz.dirInput.Width = m.inputWidth(50)
+		if wiz.step == initStepDone {
+			offset := wiz.resultView.YOffset
+			wiz.resultView = m.newResultView(wiz)
+			wiz.resultView.SetYOffset(offset)
+		}
+	}
+	if ed := m.editor; ed != nil {
+		ed.fileList.SetSize(m.width, m.height-4)
+		ed.pathInput.Width = m.inputWidth(50)
+		if ed.meta != nil {
+			for i := range ed.meta.inputs {
+				ed.meta.inputs[i].Width = m.inputWidth(50)
+			}
+		}
+	}
+	if wiz := m.createWiz; wiz != nil {
+		wiz.nameInput.Width = m.inputWidth(40)
+		wiz.descInput.Width = m.inputWidth(50)
+		wiz.tagsInput.Width = m.inputWidth(50)
+	}
+	if wiz := m.snapWiz; wiz != nil {
+		wiz.nameInput.Width = m.inputWidth(40)
+		wiz.dirInput.Width = m.inputWidth(50)
+		wiz.descInput.Width = m.inputWidth(50)
+		wiz.tagsInput.Width = m.inputWidth(50)
+	}
+}
+
 // isTextInputActive returns true if a text input or list filter is focused.
 func (m Model) isTextInputActive() bool {
 	switch m.currentView {
@@ -222,7 +356,9 @@ func (m Model) isTextInputActive() bool {
 		}
 	case viewEditor:
 		if m.editor != nil {
-			return m.editor.fileList.FilterState() == list.Filtering
+			return m.editor.step == editorStepMeta ||
+				m.editor.step == editorStepNewFile ||
+				m.editor.fileList.FilterState() == list.Filtering
 		}
 	case viewSnapshot:
 		if m.snapWiz != nil {
@@ -231,6 +367,12 @@ func (m Model) isTextInputActive() bool {
 				return true
 			}
 		}
+	case viewProfileCreate:
+		return m.createWiz != nil
+	case viewSync:
+		if m.syncSt != nil {
+			return m.syncSt.list.FilterState() == list.Filtering
+		}
 	}
 	return false
 }
@@ -243,11 +385,28 @@ func (m Model) goBack() Model {
 		m.selectedProfile = nil
 		m.profileDetail = ""
 	case viewProfiles:
+		if m.deleteTarget != nil {
+			m.deleteTarget = nil
+			m.deleteDependents = nil
+			break
+		}
+		if m.tagPick != nil {
+			m.tagPick = nil
+			break
+		}
 		m.currentView = viewMenu
+		m.tagFilter = nil
 	case viewInit:
 		m.currentView = viewMenu
 		m.initWiz = nil
 	case viewEditor:
+		if m.editor != nil && m.editor.step != editorStepFileList {
+			m.editor.step = editorStepFileList
+			m.editor.meta = nil
+			m.editor.deleteTarget = ""
+			m.editor.pathInput.Blur()
+			break
+		}
 		m.currentView = viewProfiles
 		m.editor = nil
 	case viewSync:
@@ -256,6 +415,9 @@ func (m Model) goBack() Model {
 	case viewSnapshot:
 		m.currentView = viewMenu
 		m.snapWiz = nil
+	case viewProfileCreate:
+		m.currentView = m.createFrom
+		m.createWiz = nil
 	}
 	return m
 }
@@ -266,26 +428,13 @@ func (m Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if key.Matches(msg, key.NewBinding(key.WithKeys("enter"))) {
-			selected, ok := m.menuList.SelectedItem().(menuItem)
-			if !ok {
-				return m, nil
-			}
-			m.statusMsg = ""
-			m.errMsg = ""
-			switch selected.title {
-			case "Init":
-				return m.loadInitWizard()
-			case "Profiles":
-				return m.loadProfiles()
-			case "Sync":
-				return m.loadSyncStatus()
-			case "Snapshot":
-				return m.loadSnapshotWizard()
-			case "Config":
-				m.statusMsg = "Use CLI: ocmgr config show|set|init"
-				return m, nil
-			}
+			return m.openMenuItem()
+		}
+	case tea.MouseMsg:
+		if listClick(&m.menuList, msg) {
+			return m.openMenuItem()
 		}
+		return m, nil
 	}
 
 	var cmd tea.Cmd
@@ -293,6 +442,32 @@ func (m Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// openMenuItem opens the view for the selected menu item.
+func (m Model) openMenuItem() (tea.Model, tea.Cmd) {
+	selected, ok := m.menuList.SelectedItem().(menuItem)
+	if !ok {
+		return m, nil
+	}
+	m.statusMsg = ""
+	m.errMsg = ""
+	switch selected.title {
+	case "Init":
+		return m.loadInitWizard()
+	case "Profiles":
+		return m.loadProfiles()
+	case "New Profile":
+		m.createFrom = viewMenu
+		return m.loadCreateWizard()
+	case "Sync":
+		return m.loadSyncStatus()
+	case "Snapshot":
+		return m.loadSnapshotWizard()
+	case "Config":
+		m.statusMsg = "Use CLI: ocmgr config show|set|init"
+	}
+	return m, nil
+}
+
 func (m Model) viewMenu() string {
 	var b strings.Builder
 	b.WriteString(m.menuList.View())
@@ -307,7 +482,7 @@ func (m Model) viewMenu() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("enter: select • q: quit"))
+	b.WriteString(HelpStyle.Render("enter: select • ?: help • q: quit"))
 
 	return b.String()
 }
@@ -326,19 +501,13 @@ func (m Model) loadProfiles() (tea.Model, tea.Cmd) {
 	m.statusMsg = ""
 	m.errMsg = ""
 
-	items := make([]list.Item, len(profiles))
-	for i, p := range profiles {
-		items[i] = profileItem{profile: p}
-	}
-
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
 		Foreground(ColorPrimary).BorderLeftForeground(ColorPrimary)
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
 		Foreground(ColorSecondary).BorderLeftForeground(ColorPrimary)
 
-	m.profileList = list.New(items, delegate, m.width, m.height-2)
-	m.profileList.Title = "Profiles"
+	m.profileList = list.New(nil, delegate, m.width, m.height-2)
 	m.profileList.SetShowStatusBar(true)
 	m.profileList.SetFilteringEnabled(true)
 	m.profileList.Styles.Title = TitleStyle.Copy().
@@ -346,16 +515,25 @@ func (m Model) loadProfiles() (tea.Model, tea.Cmd) {
 		Foreground(lipgloss.Color("#FFFFFF")).
 		Padding(0, 1)
 
-	return m, nil
+	return m, m.applyTagFilter()
 }
 
 func (m Model) updateProfiles(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.deleteTarget != nil {
+			return m.updateDeleteConfirm(msg)
+		}
+		if m.tagPick != nil {
+			return m.updateTagPicker(msg)
+		}
 		// Don't intercept keys when filtering
 		if m.profileList.FilterState() == list.Filtering {
 			break
 		}
+		if key.Matches(msg, key.NewBinding(key.WithKeys("t"))) {
+			return m.openTagPicker()
+		}
 		if key.Matches(msg, key.NewBinding(key.WithKeys("enter"))) {
 			selected, ok := m.profileList.SelectedItem().(profileItem)
 			if !ok {
@@ -370,6 +548,27 @@ func (m Model) updateProfiles(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m.loadEditor(selected.profile)
 		}
+		if key.Matches(msg, key.NewBinding(key.WithKeys("c"))) {
+			m.createFrom = viewProfiles
+			return m.loadCreateWizard()
+		}
+		if key.Matches(msg, key.NewBinding(key.WithKeys("d"))) {
+			selected, ok := m.profileList.SelectedItem().(profileItem)
+			if !ok {
+				return m, nil
+			}
+			return m.confirmDelete(selected.profile)
+		}
+	case tea.MouseMsg:
+		if m.deleteTarget != nil || m.tagPick != nil || m.profileList.FilterState() == list.Filtering {
+			return m, nil
+		}
+		if listClick(&m.profileList, msg) {
+			if selected, ok := m.profileList.SelectedItem().(profileItem); ok {
+				return m.loadProfileDetail(selected.profile)
+			}
+		}
+		return m, nil
 	}
 
 	var cmd tea.Cmd
@@ -378,13 +577,119 @@ func (m Model) updateProfiles(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) viewProfiles() string {
+	if m.deleteTarget != nil {
+		return m.viewDeleteConfirm()
+	}
+	if m.tagPick != nil {
+		return m.viewTagPicker()
+	}
+
 	var b strings.Builder
 	b.WriteString(m.profileList.View())
+	if m.statusMsg != "" {
+		b.WriteString("\n")
+		b.WriteString(StatusStyle.Render("✓ " + m.statusMsg))
+	}
+	if m.errMsg != "" {
+		b.WriteString("\n")
+		b.WriteString(ErrorStyle.Render("✗ " + m.errMsg))
+	}
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("enter: view • e: edit • /: filter • esc: back"))
+	b.WriteString(HelpStyle.Render("enter: view • e: edit • c: create • d: delete • t: tags • /: filter • esc: back"))
 	return b.String()
 }
 
+// confirmDelete asks for confirmation before deleting p. Profiles that
+// extend p are listed in the prompt, since they will no longer resolve.
+func (m Model) confirmDelete(p *profile.Profile) (tea.Model, tea.Cmd) {
+	m.statusMsg = ""
+	m.errMsg = ""
+
+	all, err := m.store.List()
+	if err != nil {
+		m.errMsg = fmt.Sprintf("loading profiles: %v", err)
+		return m, nil
+	}
+
+	var dependents []string
+	for _, other := range all {
+		for _, parent := range other.Extends {
+			if parent == p.Name {
+				dependents = append(dependents, other.Name)
+				break
+			}
+		}
+	}
+
+	m.deleteTarget = p
+	m.deleteDependents = dependents
+	return m, nil
+}
+
+// updateDeleteConfirm deletes the pending profile on an explicit "y";
+// any other key cancels.
+func (m Model) updateDeleteConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	name := m.deleteTarget.Name
+	m.deleteTarget = nil
+	m.deleteDependents = nil
+
+	if !key.Matches(msg, key.NewBinding(key.WithKeys("y"))) {
+		return m, nil
+	}
+
+	if err := m.store.Delete(name, false); err != nil {
+		m.errMsg = fmt.Sprintf("deleting profile: %v", err)
+		return m, nil
+	}
+
+	index := m.profileList.Index()
+	next, cmd := m.loadProfiles()
+	nm := next.(Model)
+	if n := len(nm.profileList.Items()); index >= n && n > 0 {
+		index = n - 1
+	}
+	nm.profileList.Select(index)
+	nm.statusMsg = fmt.Sprintf("Deleted profile '%s'", name)
+	return nm, cmd
+}
+
+func (m Model) viewDeleteConfirm() string {
+	var b strings.Builder
+	b.WriteString(SubtitleStyle.Render("Delete Profile — Confirm"))
+	b.WriteString("\n\n")
+	b.WriteString("  ")
+	b.WriteString(MutedStyle.Render(fmt.Sprintf("Name: %s", m.deleteTarget.Name)))
+	b.WriteString("\n")
+	b.WriteString("  ")
+	b.WriteString(MutedStyle.Render(fmt.Sprintf("Path: %s", m.deleteTarget.Path)))
+	b.WriteString("\n")
+
+	if len(m.deleteDependents) > 0 {
+		b.WriteString("\n")
+		b.WriteString(WarningStyle.Render(fmt.Sprintf(
+			"  ⚠ Extended by: %s", strings.Join(m.deleteDependents, ", "))))
+		b.WriteString("\n")
+		b.WriteString(WarningStyle.Render("    These profiles will fail to resolve after deletion."))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(StatusStyle.Render("Delete this profile? This cannot be undone."))
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("y: delete • any other key: cancel"))
+	return b.String()
+}
+
+// selectProfile moves the profile browser cursor to the named profile.
+func (m *Model) selectProfile(name string) {
+	for i, item := range m.profileList.Items() {
+		if pi, ok := item.(profileItem); ok && pi.profile.Name == name {
+			m.profileList.Select(i)
+			return
+		}
+	}
+}
+
 // ── Profile Detail ───────────────────────────────────────────────────
 
 func (m Model) loadProfileDetail(p *profile.Profile) (tea.Model, tea.Cmd) {
@@ -414,9 +719,9 @@ func (m Model) loadProfileDetail(p *profile.Profile) (tea.Model, tea.Cmd) {
 		b.WriteString(DetailValueStyle.Render(p.Description))
 		b.WriteString("\n")
 	}
-	if p.Extends != "" {
+	if len(p.Extends) > 0 {
 		b.WriteString(DetailLabelStyle.Render("Extends"))
-		b.WriteString(DetailValueStyle.Render(p.Extends))
+		b.WriteString(DetailValueStyle.Render(p.Extends.String()))
 		b.WriteString("\n")
 	}
 	if len(p.Tags) > 0 {
@@ -429,18 +734,22 @@ func (m Model) loadProfileDetail(p *profile.Profile) (tea.Model, tea.Cmd) {
 		b.WriteString(DetailValueStyle.Render(p.Version))
 		b.WriteString("\n")
 	}
-	b.WriteString("\n")
+	if st, err := profile.Stat(p); err == nil {
+		b.WriteString(DetailLabelStyle.Render("Size"))
+		b.WriteString(DetailValueStyle.Render(statSummary(st)))
+		b.WriteString("\n")
+	}
+	m.profileDetail = b.String()
 
 	// File tree
+	var tree []string
 	writeSection := func(label string, files []string) {
 		if len(files) == 0 {
 			return
 		}
-		b.WriteString(SubtitleStyle.Render(fmt.Sprintf("%s (%d)", label, len(files))))
-		b.WriteString("\n")
+		tree = append(tree, SubtitleStyle.Render(fmt.Sprintf("%s (%d)", label, len(files))))
 		for _, f := range files {
-			b.WriteString(MutedStyle.Render("    " + f))
-			b.WriteString("\n")
+			tree = append(tree, MutedStyle.Render("    "+f))
 		}
 	}
 
@@ -448,11 +757,40 @@ func (m Model) loadProfileDetail(p *profile.Profile) (tea.Model, tea.Cmd) {
 	writeSection("Commands", contents.Commands)
 	writeSection("Skills", contents.Skills)
 	writeSection("Plugins", contents.Plugins)
+	writeSection("Other", contents.Other)
 
-	m.profileDetail = b.String()
+	m.detailView = viewport.New(m.width, 0)
+	m.detailView.SetContent(strings.Join(tree, "\n"))
+	m.detailView.Height = m.detailViewHeight()
 	return m, nil
 }
 
+// detailViewHeight is the height of the profile detail file tree: the
+// window less the header and help bar, or the whole tree while the
+// window size is unknown.
+func (m Model) detailViewHeight() int {
+	if m.height == 0 {
+		return m.detailView.TotalLineCount()
+	}
+	return max(m.height-lipgloss.Height(m.profileDetail)-3, 3)
+}
+
+// statSummary renders a profile's size for the detail header, e.g.
+// "16 files, 50.0 KiB (agents 12, skills 3, plugins 1)".
+func statSummary(st *profile.Stats) string {
+	var parts []string
+	for _, d := range st.Dirs {
+		if d.Files > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", d.Dir, d.Files))
+		}
+	}
+	out := fmt.Sprintf("%d files, %s", st.Files, profile.FormatSize(st.Bytes))
+	if len(parts) > 0 {
+		out += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return out
+}
+
 func (m Model) updateProfileDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -462,13 +800,21 @@ func (m Model) updateProfileDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	}
-	return m, nil
+	var cmd tea.Cmd
+	m.detailView, cmd = m.detailView.Update(msg)
+	return m, cmd
 }
 
 func (m Model) viewProfileDetail() string {
 	var b strings.Builder
 	b.WriteString(m.profileDetail)
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("e: edit files • esc: back • q: back"))
+	b.WriteString(m.detailView.View())
+	b.WriteString("\n")
+	help := "e: edit files • esc: back • q: back"
+	if m.detailView.TotalLineCount() > m.detailView.Height {
+		help = fmt.Sprintf("↑/↓/pgup/pgdn: scroll (%.0f%%) • %s", m.detailView.ScrollPercent()*100, help)
+	}
+	b.WriteString(HelpStyle.Render(help))
 	return b.String()
 }
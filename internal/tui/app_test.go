@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/acchapm1/ocmgr/internal/profile"
+)
+
+func TestEscCancelsMetadataForm(t *testing.T) {
+	m := Model{
+		currentView: viewEditor,
+		editor: &profileEditor{
+			step:    editorStepMeta,
+			meta:    &metadataForm{},
+			profile: &profile.Profile{Name: "go"},
+		},
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	got := next.(Model)
+	if got.currentView != viewEditor || got.editor == nil || got.editor.step != editorStepFileList {
+		t.Errorf("after esc: view %v, editor %+v; want the editor's file list", got.currentView, got.editor)
+	}
+	if got.editor != nil && got.editor.meta != nil {
+		t.Error("metadata form still open")
+	}
+}
+
+func TestEscIsLeftToOtherTextInputs(t *testing.T) {
+	m := Model{
+		currentView: viewInit,
+		initWiz:     &initWizard{step: initStepDir},
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if got := next.(Model); got.currentView != viewInit || got.initWiz == nil {
+		t.Error("esc in the init target directory left the wizard")
+	}
+}
+
+func TestEscCancelsNewFilePrompt(t *testing.T) {
+	m := Model{
+		currentView: viewEditor,
+		editor: &profileEditor{
+			step:    editorStepNewFile,
+			profile: &profile.Profile{Name: "go"},
+		},
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	got := next.(Model)
+	if got.currentView != viewEditor || got.editor == nil || got.editor.step != editorStepFileList {
+		t.Errorf("after esc: view %v, editor %+v; want the editor's file list", got.currentView, got.editor)
+	}
+}
+
+func TestWindowSizeResizesSubViews(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m, err := NewModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newList := func() list.Model { return list.New(nil, list.NewDefaultDelegate(), 0, 0) }
+	m.profileList = list.New([]list.Item{profileItem{profile: &profile.Profile{Name: "go"}}}, list.NewDefaultDelegate(), 0, 0)
+	m.initWiz = &initWizard{profileList: newList(), dirInput: textinput.New()}
+	m.editor = &profileEditor{
+		fileList:  newList(),
+		pathInput: textinput.New(),
+		meta:      &metadataForm{},
+	}
+	m.createWiz = &createWizard{nameInput: textinput.New()}
+
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 30, Height: 20})
+	m = next.(Model)
+
+	lists := []struct {
+		name   string
+		l      list.Model
+		height int
+	}{
+		{"menu", m.menuList, 18},
+		{"profiles", m.profileList, 18},
+		{"init profiles", m.initWiz.profileList, 16},
+		{"editor files", m.editor.fileList, 16},
+	}
+	for _, tc := range lists {
+		if tc.l.Width() != 30 || tc.l.Height() != tc.height {
+			t.Errorf("%s list is %dx%d, want 30x%d", tc.name, tc.l.Width(), tc.l.Height(), tc.height)
+		}
+	}
+
+	want := 30 - inputMargin
+	inputs := map[string]int{
+		"init dir":       m.initWiz.dirInput.Width,
+		"editor path":    m.editor.pathInput.Width,
+		"metadata field": m.editor.meta.inputs[0].Width,
+		"profile name":   m.createWiz.nameInput.Width,
+	}
+	for name, w := range inputs {
+		if w != want {
+			t.Errorf("%s input width = %d, want %d", name, w, want)
+		}
+	}
+
+	// Views opened after the resize use the stored size.
+	m.editor = nil
+	next, _ = m.loadEditor(&profile.Profile{Name: "go", Path: t.TempDir()})
+	fl := next.(Model).editor.fileList
+	if fl.Width() != 30 || fl.Height() != 16 {
+		t.Errorf("new editor list is %dx%d, want 30x16", fl.Width(), fl.Height())
+	}
+}
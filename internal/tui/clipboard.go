@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardMsg reports that copyToClipboard placed text on the
+// clipboard. method is a short description of how; osc52 is the escape
+// sequence View still has to emit when the terminal does the copying.
+type clipboardMsg struct {
+	method string
+	osc52  string
+}
+
+// osc52SentMsg tells the model that the frame carrying its OSC 52
+// sequence has been drawn, so the sequence can be dropped.
+type osc52SentMsg struct{}
+
+// osc52Hold is how long an OSC 52 sequence stays in the view: long
+// enough for the renderer to draw at least one frame with it.
+const osc52Hold = 100 * time.Millisecond
+
+// copyToClipboard returns a command that places text on the system
+// clipboard. It uses the platform clipboard tools (pbcopy, xclip, xsel,
+// wl-copy, …) when they are available and falls back to an OSC 52
+// escape sequence, which most modern terminals (including over SSH)
+// understand. The sequence is written by View, through the program's
+// renderer, rather than straight to stdout.
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		if !clipboard.Unsupported && clipboard.WriteAll(text) == nil {
+			return clipboardMsg{method: "clipboard"}
+		}
+		seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+		return clipboardMsg{method: "terminal (OSC 52)", osc52: seq}
+	}
+}
+
+// emitOSC52 makes View emit seq, if any, until the returned command
+// delivers osc52SentMsg.
+func (m *Model) emitOSC52(seq string) tea.Cmd {
+	if seq == "" {
+		return nil
+	}
+	m.osc52 = seq
+	return tea.Tick(osc52Hold, func(time.Time) tea.Msg { return osc52SentMsg{} })
+}
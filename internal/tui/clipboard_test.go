@@ -0,0 +1,22 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOSC52IsEmittedThroughView(t *testing.T) {
+	m := Model{currentView: viewMenu}
+	cmd := m.emitOSC52("\x1b]52;c;aGk=\a")
+	if cmd == nil {
+		t.Fatal("no command to clear the sequence")
+	}
+	if !strings.HasPrefix(m.View(), "\x1b]52;c;aGk=\a") {
+		t.Error("View does not start with the OSC 52 sequence")
+	}
+
+	next, _ := m.Update(osc52SentMsg{})
+	if strings.Contains(next.View(), "\x1b]52") {
+		t.Error("sequence still in View after osc52SentMsg")
+	}
+}
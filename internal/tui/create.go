@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/acchapm1/ocmgr/internal/profile"
+)
+
+// createStep tracks the current step in the create-profile wizard.
+type createStep int
+
+const (
+	createStepName createStep = iota
+	createStepMeta
+)
+
+// createWizard holds state for the create-profile flow.
+type createWizard struct {
+	step      createStep
+	nameInput textinput.Model
+	descInput textinput.Model
+	tagsInput textinput.Model
+	metaField int // 0=desc, 1=tags
+	name      string
+	errMsg    string
+}
+
+// ── Load ─────────────────────────────────────────────────────────────
+
+func (m Model) loadCreateWizard() (tea.Model, tea.Cmd) {
+	m.currentView = viewProfileCreate
+	m.statusMsg = ""
+	m.errMsg = ""
+
+	ni := textinput.New()
+	ni.Placeholder = "my-profile"
+	ni.CharLimit = 64
+	ni.Width = m.inputWidth(40)
+	ni.Focus()
+
+	desc := textinput.New()
+	desc.Placeholder = "A brief description"
+	desc.CharLimit = 200
+	desc.Width = m.inputWidth(50)
+
+	tags := textinput.New()
+	tags.Placeholder = "go, backend, api"
+	tags.CharLimit = 200
+	tags.Width = m.inputWidth(50)
+
+	m.createWiz = &createWizard{
+		step:      createStepName,
+		nameInput: ni,
+		descInput: desc,
+		tagsInput: tags,
+	}
+
+	return m, ni.Focus()
+}
+
+// ── Update ───────────────────────────────────────────────────────────
+
+func (m Model) updateCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	wiz := m.createWiz
+	if wiz == nil {
+		return m, nil
+	}
+
+	switch wiz.step {
+	case createStepName:
+		return m.updateCreateName(msg)
+	case createStepMeta:
+		return m.updateCreateMeta(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateCreateName(msg tea.Msg) (tea.Model, tea.Cmd) {
+	wiz := m.createWiz
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, key.NewBinding(key.WithKeys("enter"))) {
+			name := strings.TrimSpace(wiz.nameInput.Value())
+			if err := profile.ValidateName(name); err != nil {
+				wiz.errMsg = err.Error()
+				return m, nil
+			}
+			if m.store.Exists(name) {
+				wiz.errMsg = fmt.Sprintf("Profile %q already exists", name)
+				return m, nil
+			}
+			wiz.name = name
+			wiz.errMsg = ""
+			wiz.step = createStepMeta
+			wiz.nameInput.Blur()
+			return m, wiz.descInput.Focus()
+		}
+	}
+	var cmd tea.Cmd
+	wiz.nameInput, cmd = wiz.nameInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateCreateMeta(msg tea.Msg) (tea.Model, tea.Cmd) {
+	wiz := m.createWiz
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, key.NewBinding(key.WithKeys("tab"))) {
+			if wiz.metaField == 0 {
+				wiz.metaField = 1
+				wiz.descInput.Blur()
+				return m, wiz.tagsInput.Focus()
+			}
+			wiz.metaField = 0
+			wiz.tagsInput.Blur()
+			return m, wiz.descInput.Focus()
+		}
+		if key.Matches(msg, key.NewBinding(key.WithKeys("enter"))) {
+			if err := m.createProfile(); err != nil {
+				wiz.errMsg = err.Error()
+				return m, nil
+			}
+			name := wiz.name
+			m.createWiz = nil
+			next, cmd := m.loadProfiles()
+			nm := next.(Model)
+			nm.statusMsg = fmt.Sprintf("Created profile '%s'", name)
+			nm.selectProfile(name)
+			return nm, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	if wiz.metaField == 0 {
+		wiz.descInput, cmd = wiz.descInput.Update(msg)
+	} else {
+		wiz.tagsInput, cmd = wiz.tagsInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// createProfile scaffolds the profile described by the wizard and writes
+// its metadata. The profile directory is removed again if saving fails.
+func (m Model) createProfile() error {
+	wiz := m.createWiz
+
+	// Re-check in case the profile appeared while the wizard was open.
+	if m.store.Exists(wiz.name) {
+		return fmt.Errorf("Profile %q already exists", wiz.name)
+	}
+
+	p, err := profile.ScaffoldProfile(m.store.Dir, wiz.name)
+	if err != nil {
+		return fmt.Errorf("creating profile: %w", err)
+	}
+
+	p.Description = strings.TrimSpace(wiz.descInput.Value())
+	for _, t := range strings.Split(wiz.tagsInput.Value(), ",") {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			p.Tags = append(p.Tags, t)
+		}
+	}
+
+	if err := profile.SaveProfile(p); err != nil {
+		_ = os.RemoveAll(p.Path)
+		return fmt.Errorf("saving metadata: %w", err)
+	}
+	return nil
+}
+
+// ── View ─────────────────────────────────────────────────────────────
+
+func (m Model) viewCreate() string {
+	wiz := m.createWiz
+	if wiz == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	switch wiz.step {
+	case createStepName:
+		b.WriteString(SubtitleStyle.Render("New Profile — Name"))
+		b.WriteString("\n\n")
+		b.WriteString("  Name: ")
+		b.WriteString(wiz.nameInput.View())
+		if wiz.errMsg != "" {
+			b.WriteString("\n\n")
+			b.WriteString(ErrorStyle.Render("  ✗ " + wiz.errMsg))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render("enter: continue • esc: cancel"))
+	case createStepMeta:
+		b.WriteString(SubtitleStyle.Render("New Profile — Metadata"))
+		b.WriteString("\n\n")
+		b.WriteString("  Name:        ")
+		b.WriteString(DetailValueStyle.Render(wiz.name))
+		b.WriteString("\n")
+		b.WriteString("  Description: ")
+		b.WriteString(wiz.descInput.View())
+		b.WriteString("\n")
+		b.WriteString("  Tags:        ")
+		b.WriteString(wiz.tagsInput.View())
+		if wiz.errMsg != "" {
+			b.WriteString("\n\n")
+			b.WriteString(ErrorStyle.Render("  ✗ " + wiz.errMsg))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render("tab: next field • enter: create • esc: cancel"))
+	}
+	return b.String()
+}
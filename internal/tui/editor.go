@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/acchapm1/ocmgr/internal/config"
 	"github.com/acchapm1/ocmgr/internal/profile"
 )
 
@@ -19,6 +23,9 @@ type editorStep int
 const (
 	editorStepFileList editorStep = iota
 	editorStepEditing
+	editorStepMeta
+	editorStepNewFile
+	editorStepConfirmDelete
 )
 
 // profileEditor holds state for the profile editor view.
@@ -27,7 +34,16 @@ type profileEditor struct {
 	profile  *profile.Profile
 	fileList list.Model
 	files    []string
+	meta     *metadataForm
 	errMsg   string
+
+	// pathInput reads the path of a new file; deleteTarget is the file
+	// awaiting delete confirmation. Both are relative to the profile.
+	pathInput    textinput.Model
+	deleteTarget string
+
+	// statusMsg confirms the last metadata save.
+	statusMsg string
 }
 
 // fileItem implements list.Item for the file browser.
@@ -39,50 +55,101 @@ func (i fileItem) Title() string       { return i.path }
 func (i fileItem) Description() string { return "" }
 func (i fileItem) FilterValue() string { return i.path }
 
-// editorDoneMsg is sent when the external editor exits.
-type editorDoneMsg struct{ err error }
+// editorDoneMsg is sent when the external editor exits. path is the
+// edited file, relative to the profile.
+type editorDoneMsg struct {
+	path string
+	err  error
+}
 
 // ── Load ─────────────────────────────────────────────────────────────
 
 func (m Model) loadEditor(p *profile.Profile) (tea.Model, tea.Cmd) {
-	contents, err := profile.ListContents(p)
+	files, err := contentFiles(p)
 	if err != nil {
-		m.errMsg = fmt.Sprintf("listing contents: %v", err)
+		m.errMsg = err.Error()
 		return m, nil
 	}
 
-	var files []string
-	files = append(files, contents.Agents...)
-	files = append(files, contents.Commands...)
-	files = append(files, contents.Skills...)
-	files = append(files, contents.Plugins...)
-
-	items := make([]list.Item, len(files))
-	for i, f := range files {
-		items[i] = fileItem{path: f}
-	}
-
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
 		Foreground(ColorPrimary).BorderLeftForeground(ColorPrimary)
 	delegate.ShowDescription = false
 
-	fl := list.New(items, delegate, m.width, m.height-4)
+	fl := list.New(fileItems(files), delegate, m.width, m.height-4)
 	fl.Title = fmt.Sprintf("Edit: %s", p.Name)
 	fl.SetShowStatusBar(true)
 	fl.SetFilteringEnabled(true)
 
+	pi := textinput.New()
+	pi.Placeholder = "agents/new-agent.md"
+	pi.CharLimit = 200
+	pi.Width = m.inputWidth(50)
+
 	m.currentView = viewEditor
 	m.editor = &profileEditor{
-		step:     editorStepFileList,
-		profile:  p,
-		fileList: fl,
-		files:    files,
+		step:      editorStepFileList,
+		profile:   p,
+		fileList:  fl,
+		files:     files,
+		pathInput: pi,
 	}
 
 	return m, nil
 }
 
+// contentFiles lists the content files of p, relative to the profile.
+func contentFiles(p *profile.Profile) ([]string, error) {
+	contents, err := profile.ListContents(p)
+	if err != nil {
+		return nil, fmt.Errorf("listing contents: %w", err)
+	}
+
+	var files []string
+	files = append(files, contents.Agents...)
+	files = append(files, contents.Commands...)
+	files = append(files, contents.Skills...)
+	files = append(files, contents.Plugins...)
+	files = append(files, contents.Other...)
+	return files, nil
+}
+
+// fileItems builds the editor list: the metadata entry, then the files.
+func fileItems(files []string) []list.Item {
+	items := make([]list.Item, 0, len(files)+1)
+	items = append(items, metaItem{})
+	for _, f := range files {
+		items = append(items, fileItem{path: f})
+	}
+	return items
+}
+
+// refreshFiles re-reads the profile's files after one was added or
+// removed, selecting the file at path if it is listed and otherwise
+// keeping the cursor where it was.
+func (ed *profileEditor) refreshFiles(path string) tea.Cmd {
+	files, err := contentFiles(ed.profile)
+	if err != nil {
+		ed.errMsg = err.Error()
+		return nil
+	}
+	ed.files = files
+
+	index := ed.fileList.Index()
+	items := fileItems(files)
+	for i, f := range files {
+		if f == path {
+			index = i + 1 // after the metadata entry
+		}
+	}
+	if index >= len(items) {
+		index = len(items) - 1
+	}
+	cmd := ed.fileList.SetItems(items)
+	ed.fileList.Select(index)
+	return cmd
+}
+
 // ── Update ───────────────────────────────────────────────────────────
 
 func (m Model) updateEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -99,6 +166,9 @@ func (m Model) updateEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 			if key.Matches(msg, key.NewBinding(key.WithKeys("enter"))) {
+				if _, ok := ed.fileList.SelectedItem().(metaItem); ok {
+					return m.openMetadataForm()
+				}
 				selected, ok := ed.fileList.SelectedItem().(fileItem)
 				if !ok {
 					return m, nil
@@ -112,16 +182,25 @@ func (m Model) updateEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
-				ed.step = editorStepEditing
-				// Launch editor
-				editor := os.Getenv("EDITOR")
-				if editor == "" {
-					editor = "nvim"
+				return m, m.openInEditor(absPath)
+			}
+			if key.Matches(msg, key.NewBinding(key.WithKeys("n"))) {
+				ed.step = editorStepNewFile
+				ed.errMsg = ""
+				ed.statusMsg = ""
+				ed.pathInput.SetValue("")
+				return m, ed.pathInput.Focus()
+			}
+			if key.Matches(msg, key.NewBinding(key.WithKeys("d"))) {
+				selected, ok := ed.fileList.SelectedItem().(fileItem)
+				if !ok {
+					return m, nil
 				}
-				c := exec.Command(editor, absPath)
-				return m, tea.ExecProcess(c, func(err error) tea.Msg {
-					return editorDoneMsg{err: err}
-				})
+				ed.step = editorStepConfirmDelete
+				ed.deleteTarget = selected.path
+				ed.errMsg = ""
+				ed.statusMsg = ""
+				return m, nil
 			}
 		}
 
@@ -130,16 +209,137 @@ func (m Model) updateEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case editorStepEditing:
-		switch msg.(type) {
+		switch msg := msg.(type) {
 		case editorDoneMsg:
 			ed.step = editorStepFileList
-			return m, nil
+			if msg.err != nil {
+				ed.errMsg = fmt.Sprintf("editor: %v", msg.err)
+			}
+			return m, ed.refreshFiles(msg.path)
+		}
+
+	case editorStepMeta:
+		return m.updateMetadataForm(msg)
+
+	case editorStepNewFile:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+				rel, err := ed.createFile(ed.pathInput.Value())
+				if err != nil {
+					ed.errMsg = err.Error()
+					return m, nil
+				}
+				ed.pathInput.Blur()
+				ed.errMsg = ""
+				ed.statusMsg = fmt.Sprintf("Created %s", rel)
+				return m, m.openInEditor(filepath.Join(ed.profile.Path, rel))
+			case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
+				ed.errMsg = ""
+				return m.goBack(), nil
+			}
+		}
+		var cmd tea.Cmd
+		ed.pathInput, cmd = ed.pathInput.Update(msg)
+		return m, cmd
+
+	case editorStepConfirmDelete:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			target := ed.deleteTarget
+			ed.deleteTarget = ""
+			ed.step = editorStepFileList
+			if !key.Matches(msg, key.NewBinding(key.WithKeys("y"))) {
+				return m, nil
+			}
+			if err := ed.deleteFile(target); err != nil {
+				ed.errMsg = err.Error()
+				return m, nil
+			}
+			ed.statusMsg = fmt.Sprintf("Deleted %s", target)
+			return m, ed.refreshFiles("")
 		}
 	}
 
 	return m, nil
 }
 
+// openInEditor suspends the TUI and opens absPath in the editor.
+func (m Model) openInEditor(absPath string) tea.Cmd {
+	m.editor.step = editorStepEditing
+	rel, _ := filepath.Rel(m.editor.profile.Path, absPath)
+	argv := m.editorCommand()
+	c := exec.Command(argv[0], append(argv[1:], absPath)...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return editorDoneMsg{path: filepath.ToSlash(rel), err: err}
+	})
+}
+
+// createFile creates an empty file at rel (relative to the profile, and
+// inside one of its content directories), along with any missing parent
+// directories. It returns the cleaned relative path.
+func (ed *profileEditor) createFile(rel string) (string, error) {
+	rel = strings.TrimSpace(rel)
+	if rel == "" {
+		return "", fmt.Errorf("enter a path such as agents/new-agent.md")
+	}
+	if filepath.IsAbs(rel) {
+		return "", fmt.Errorf("path must be relative to the profile")
+	}
+	clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(rel)))
+	parts := strings.Split(clean, "/")
+	if len(parts) < 2 || !slices.Contains(profile.ContentDirs(), parts[0]) {
+		return "", fmt.Errorf("path must be inside %s/", strings.Join(profile.ContentDirs(), "/, "))
+	}
+
+	abs := filepath.Join(ed.profile.Path, filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+		return "", fmt.Errorf("creating directories: %w", err)
+	}
+	f, err := os.OpenFile(abs, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if os.IsExist(err) {
+			return "", fmt.Errorf("%s already exists", clean)
+		}
+		return "", fmt.Errorf("creating file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("creating file: %w", err)
+	}
+	return clean, nil
+}
+
+// deleteFile removes the file at rel and then any directories it leaves
+// empty, stopping at the content directory (e.g. an emptied
+// skills/<name>/ is removed, skills/ itself is kept).
+func (ed *profileEditor) deleteFile(rel string) error {
+	abs := filepath.Join(ed.profile.Path, filepath.FromSlash(rel))
+	if err := os.Remove(abs); err != nil {
+		return fmt.Errorf("deleting %s: %w", rel, err)
+	}
+
+	top := filepath.Join(ed.profile.Path, strings.SplitN(rel, "/", 2)[0])
+	for dir := filepath.Dir(abs); dir != top && strings.HasPrefix(dir, top); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break // not empty
+		}
+	}
+	return nil
+}
+
+// editorCommand returns the editor command line to open files with: the
+// --editor override if given, otherwise the configured one (see
+// config.Config.EditorCommand).
+func (m Model) editorCommand() []string {
+	if args := config.SplitCommand(m.editorOverride); len(args) > 0 {
+		return args
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	return cfg.EditorCommand()
+}
+
 // ── View ─────────────────────────────────────────────────────────────
 
 func (m Model) viewEditor() string {
@@ -153,15 +353,46 @@ func (m Model) viewEditor() string {
 	switch ed.step {
 	case editorStepFileList:
 		b.WriteString(ed.fileList.View())
+		if ed.statusMsg != "" {
+			b.WriteString("\n")
+			b.WriteString(StatusStyle.Render("✓ " + ed.statusMsg))
+		}
 		if ed.errMsg != "" {
 			b.WriteString("\n")
 			b.WriteString(ErrorStyle.Render("✗ " + ed.errMsg))
 		}
 		b.WriteString("\n")
-		b.WriteString(HelpStyle.Render("enter: edit in $EDITOR • /: filter • esc: back"))
+		b.WriteString(HelpStyle.Render("enter: edit • n: new file • d: delete • /: filter • esc: back"))
 
 	case editorStepEditing:
 		b.WriteString(StatusStyle.Render("Editing file... (return to TUI when editor closes)"))
+
+	case editorStepMeta:
+		b.WriteString(m.viewMetadataForm())
+
+	case editorStepNewFile:
+		b.WriteString(SubtitleStyle.Render("New File — " + ed.profile.Name))
+		b.WriteString("\n\n")
+		b.WriteString("  Path: ")
+		b.WriteString(ed.pathInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(MutedStyle.Render("  Relative to the profile, inside agents/, commands/, skills/ or plugins/."))
+		if ed.errMsg != "" {
+			b.WriteString("\n\n")
+			b.WriteString(ErrorStyle.Render("  ✗ " + ed.errMsg))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render("enter: create and edit • esc: cancel"))
+
+	case editorStepConfirmDelete:
+		b.WriteString(SubtitleStyle.Render("Delete File — Confirm"))
+		b.WriteString("\n\n")
+		b.WriteString("  ")
+		b.WriteString(MutedStyle.Render(fmt.Sprintf("%s/%s", ed.profile.Name, ed.deleteTarget)))
+		b.WriteString("\n\n")
+		b.WriteString(StatusStyle.Render("Delete this file? This cannot be undone."))
+		b.WriteString("\n")
+		b.WriteString(HelpStyle.Render("y: delete • any other key: cancel"))
 	}
 
 	return b.String()
@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpBinding is one line of the help overlay: a key and what it does.
+type helpBinding struct {
+	keys string
+	desc string
+}
+
+// helpSection is a titled group of bindings in the help overlay.
+type helpSection struct {
+	title    string
+	bindings []helpBinding
+}
+
+// helpSections returns the key bindings of the current view, followed by
+// the keys that work everywhere.
+func (m Model) helpSections() []helpSection {
+	var sections []helpSection
+	add := func(title string, bindings ...helpBinding) {
+		sections = append(sections, helpSection{title: title, bindings: bindings})
+	}
+
+	listKeys := []helpBinding{
+		{"↑/↓ k/j", "move up/down"},
+		{"←/→ pgup/pgdn", "previous/next page"},
+		{"g/G home/end", "first/last item"},
+	}
+	filterKeys := []helpBinding{
+		{"/", "filter"},
+		{"esc", "clear the filter"},
+	}
+
+	switch m.currentView {
+	case viewMenu:
+		add("Main menu", append([]helpBinding{
+			{"enter/click", "open the item"},
+			{"mouse wheel", "move up/down"},
+		}, listKeys...)...)
+	case viewProfiles:
+		switch {
+		case m.deleteTarget != nil:
+			add("Delete profile",
+				helpBinding{"y", "delete the profile"},
+				helpBinding{"any other key", "cancel"})
+		case m.tagPick != nil:
+			add("Tag filter",
+				helpBinding{"↑/↓", "move"},
+				helpBinding{"space", "toggle the tag"},
+				helpBinding{"c", "clear all tags"},
+				helpBinding{"enter", "apply"},
+				helpBinding{"esc", "cancel"})
+		default:
+			add("Profiles",
+				helpBinding{"enter/click", "view the profile"},
+				helpBinding{"mouse wheel", "move up/down"},
+				helpBinding{"e", "edit the profile"},
+				helpBinding{"c", "create a profile"},
+				helpBinding{"d", "delete the profile"},
+				helpBinding{"t", "filter by tags"},
+				helpBinding{"esc", "back to the menu"})
+			add("List", append(listKeys, filterKeys...)...)
+		}
+	case viewProfileDetail:
+		add("Profile",
+			helpBinding{"↑/↓ pgup/pgdn", "scroll the file list"},
+			helpBinding{"e", "edit the profile's files"},
+			helpBinding{"esc/q", "back to the profiles"})
+	case viewInit:
+		m.initHelp(add, listKeys, filterKeys)
+	case viewEditor:
+		m.editorHelp(add, listKeys, filterKeys)
+	case viewSync:
+		add("Sync",
+			helpBinding{"p", "push the selected profile"},
+			helpBinding{"P", "pull the selected profile"},
+			helpBinding{"esc", "back to the menu"})
+		add("List", append(listKeys, filterKeys...)...)
+	case viewSnapshot:
+		m.snapshotHelp(add)
+	case viewProfileCreate:
+		add("New profile",
+			helpBinding{"tab", "next field"},
+			helpBinding{"enter", "continue / create"},
+			helpBinding{"esc", "cancel"})
+	}
+
+	add("Global",
+		helpBinding{"?", "toggle this help"},
+		helpBinding{"esc/q", "back / cancel"},
+		helpBinding{"q", "quit (from the menu)"},
+		helpBinding{"ctrl+c", "quit"})
+	return sections
+}
+
+// initHelp adds the help for the current step of the init wizard.
+func (m Model) initHelp(add func(string, ...helpBinding), listKeys, filterKeys []helpBinding) {
+	step := initStepProfile
+	if m.initWiz != nil {
+		step = m.initWiz.step
+	}
+	switch step {
+	case initStepProfile:
+		add("Init: choose profiles",
+			helpBinding{"enter", "select the profile"},
+			helpBinding{"esc", "cancel"})
+		add("List", append(listKeys, filterKeys...)...)
+	case initStepDir:
+		add("Init: target directory",
+			helpBinding{"enter", "continue"},
+			helpBinding{"esc", "cancel"})
+	case initStepPreview:
+		add("Init: preview",
+			helpBinding{"y/enter", "apply"},
+			helpBinding{"n/esc", "cancel"})
+	default:
+		add("Init: results",
+			helpBinding{"↑/↓ pgup/pgdn", "scroll"},
+			helpBinding{"c", "copy errors to the clipboard"},
+			helpBinding{"enter/esc", "back to the menu"})
+	}
+}
+
+// editorHelp adds the help for the current step of the profile editor.
+func (m Model) editorHelp(add func(string, ...helpBinding), listKeys, filterKeys []helpBinding) {
+	step := editorStepFileList
+	if m.editor != nil {
+		step = m.editor.step
+	}
+	switch step {
+	case editorStepMeta:
+		add("Edit metadata",
+			helpBinding{"tab/↓", "next field"},
+			helpBinding{"shift+tab/↑", "previous field"},
+			helpBinding{"enter", "save"},
+			helpBinding{"esc", "cancel"})
+	case editorStepNewFile:
+		add("New file",
+			helpBinding{"enter", "create and edit"},
+			helpBinding{"esc", "cancel"})
+	case editorStepConfirmDelete:
+		add("Delete file",
+			helpBinding{"y", "delete the file"},
+			helpBinding{"any other key", "cancel"})
+	default:
+		add("Edit profile",
+			helpBinding{"enter", "edit the file or metadata"},
+			helpBinding{"n", "new file"},
+			helpBinding{"d", "delete the file"},
+			helpBinding{"esc", "back"})
+		add("List", append(listKeys, filterKeys...)...)
+	}
+}
+
+// snapshotHelp adds the help for the current step of the snapshot wizard.
+func (m Model) snapshotHelp(add func(string, ...helpBinding)) {
+	step := snapStepName
+	if m.snapWiz != nil {
+		step = m.snapWiz.step
+	}
+	switch step {
+	case snapStepName, snapStepDir:
+		add("Snapshot",
+			helpBinding{"enter", "continue"},
+			helpBinding{"esc", "cancel"})
+	case snapStepMeta:
+		add("Snapshot: metadata",
+			helpBinding{"tab", "next field"},
+			helpBinding{"enter", "continue"},
+			helpBinding{"esc", "cancel"})
+	case snapStepPreview:
+		add("Snapshot: preview",
+			helpBinding{"y/enter", "create the profile"},
+			helpBinding{"n/esc", "cancel"})
+	default:
+		add("Snapshot", helpBinding{"any key", "back to the menu"})
+	}
+}
+
+// viewHelp renders the help overlay, centred in the window when its size
+// is known.
+func (m Model) viewHelp() string {
+	sections := m.helpSections()
+	width := 0
+	for _, sec := range sections {
+		for _, b := range sec.bindings {
+			width = max(width, lipgloss.Width(b.keys))
+		}
+	}
+	keyStyle := DetailLabelStyle.Copy().Width(width + 2)
+
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("Keyboard shortcuts"))
+	b.WriteString("\n")
+	for _, sec := range sections {
+		b.WriteString("\n")
+		b.WriteString(SubtitleStyle.Render(sec.title))
+		b.WriteString("\n")
+		for _, hb := range sec.bindings {
+			b.WriteString("  " + keyStyle.Render(hb.keys) + DetailValueStyle.Render(hb.desc) + "\n")
+		}
+	}
+	b.WriteString(HelpStyle.Render("?/esc: close help"))
+
+	box := BorderStyle.Render(b.String())
+	if m.width == 0 || m.height == 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/acchapm1/ocmgr/internal/copier"
@@ -37,6 +38,9 @@ type initWizard struct {
 	resolvedNames []string
 	previewLines  []string
 	resultLines   []string
+	skippedFiles  []string
+	resultView    viewport.Model
+	clipMsg       string
 	errMsg        string
 	copyCount     int
 	skipCount     int
@@ -45,9 +49,10 @@ type initWizard struct {
 // ── Messages ─────────────────────────────────────────────────────────
 
 type initCopyDoneMsg struct {
-	copied  int
-	skipped int
-	errors  []string
+	copied       int
+	skipped      int
+	skippedFiles []string
+	errors       []string
 }
 
 type initCopyErrMsg struct {
@@ -92,7 +97,7 @@ func (m Model) loadInitWizard() (tea.Model, tea.Cmd) {
 	ti := textinput.New()
 	ti.Placeholder = "."
 	ti.CharLimit = 256
-	ti.Width = 50
+	ti.Width = m.inputWidth(50)
 
 	m.initWiz = &initWizard{
 		step:        initStepProfile,
@@ -125,7 +130,9 @@ func (m Model) updateInit(msg tea.Msg) (tea.Model, tea.Cmd) {
 			wiz.step = initStepDone
 			wiz.copyCount = msg.copied
 			wiz.skipCount = msg.skipped
+			wiz.skippedFiles = msg.skippedFiles
 			wiz.resultLines = msg.errors
+			wiz.resultView = m.newResultView(wiz)
 			return m, nil
 		case initCopyErrMsg:
 			wiz.step = initStepDone
@@ -141,6 +148,18 @@ func (m Model) updateInit(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.initWiz = nil
 				return m, nil
 			}
+			if key.Matches(msg, key.NewBinding(key.WithKeys("c"))) && len(wiz.resultLines) > 0 {
+				return m, copyToClipboard(errorSummary(wiz))
+			}
+		case clipboardMsg:
+			wiz.clipMsg = fmt.Sprintf("Copied %d errors to %s", len(wiz.resultLines), msg.method)
+			cmd := m.emitOSC52(msg.osc52)
+			return m, cmd
+		}
+		if hasResultDetail(wiz) {
+			var cmd tea.Cmd
+			wiz.resultView, cmd = wiz.resultView.Update(msg)
+			return m, cmd
 		}
 		return m, nil
 	}
@@ -164,10 +183,10 @@ func (m Model) updateInitProfile(msg tea.Msg) (tea.Model, tea.Cmd) {
 			wiz.selectedNames = []string{selected.profile.Name}
 
 			// Resolve extends chain
-			resolved, err := resolver.Resolve(wiz.selectedNames, func(name string) (string, error) {
+			resolved, err := resolver.Resolve(wiz.selectedNames, func(name string) ([]string, error) {
 				p, err := m.store.Get(name)
 				if err != nil {
-					return "", err
+					return nil, err
 				}
 				return p.Extends, nil
 			})
@@ -234,12 +253,13 @@ func (m Model) updateInitDir(msg tea.Msg) (tea.Model, tea.Cmd) {
 					DryRun:   true,
 				})
 				if result != nil {
+					changed := len(result.Overwritten)
 					wiz.previewLines = append(wiz.previewLines,
-						fmt.Sprintf("  %s: %d files", name, len(result.Copied)))
+						fmt.Sprintf("  %s: %d new, %d changed, %d unchanged",
+							name, len(result.Copied)-changed, changed, len(result.Unchanged)))
 					for _, f := range result.Copied {
-						rel, _ := filepath.Rel(targetOpencode, f)
 						wiz.previewLines = append(wiz.previewLines,
-							fmt.Sprintf("    %s", rel))
+							fmt.Sprintf("    %s", f))
 					}
 				}
 			}
@@ -289,6 +309,7 @@ func (m Model) runInitCopy() tea.Cmd {
 	return func() tea.Msg {
 		totalCopied := 0
 		totalSkipped := 0
+		var allSkipped []string
 		var allErrors []string
 
 		for _, name := range resolvedNames {
@@ -306,13 +327,15 @@ func (m Model) runInitCopy() tea.Cmd {
 			}
 			totalCopied += len(result.Copied)
 			totalSkipped += len(result.Skipped)
-			allErrors = append(allErrors, result.Errors...)
+			allSkipped = append(allSkipped, result.Skipped...)
+			allErrors = append(allErrors, result.Errors.Strings()...)
 		}
 
 		return initCopyDoneMsg{
-			copied:  totalCopied,
-			skipped: totalSkipped,
-			errors:  allErrors,
+			copied:       totalCopied,
+			skipped:      totalSkipped,
+			skippedFiles: allSkipped,
+			errors:       allErrors,
 		}
 	}
 }
@@ -416,11 +439,15 @@ func (m Model) viewInitDone() string {
 		}
 		if len(wiz.resultLines) > 0 {
 			b.WriteString("\n")
-			b.WriteString(ErrorStyle.Render(fmt.Sprintf("  %d errors:", len(wiz.resultLines))))
-			for _, e := range wiz.resultLines {
-				b.WriteString("\n    ")
-				b.WriteString(MutedStyle.Render(e))
-			}
+			b.WriteString(ErrorStyle.Render(fmt.Sprintf("  %d errors", len(wiz.resultLines))))
+		}
+		if hasResultDetail(wiz) {
+			b.WriteString("\n\n")
+			b.WriteString(BorderStyle.Render(wiz.resultView.View()))
+		}
+		if wiz.clipMsg != "" {
+			b.WriteString("\n")
+			b.WriteString(StatusStyle.Render(wiz.clipMsg))
 		}
 
 		dir := strings.TrimSpace(wiz.dirInput.Value())
@@ -438,6 +465,67 @@ func (m Model) viewInitDone() string {
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(HelpStyle.Render("press any key to return to menu"))
+	switch {
+	case len(wiz.resultLines) > 0:
+		b.WriteString(HelpStyle.Render("↑/↓/pgup/pgdn: scroll • c: copy errors • enter/esc: back to menu"))
+	case hasResultDetail(wiz):
+		b.WriteString(HelpStyle.Render("↑/↓/pgup/pgdn: scroll • enter/esc: back to menu"))
+	default:
+		b.WriteString(HelpStyle.Render("enter/esc: back to menu"))
+	}
+	return b.String()
+}
+
+// hasResultDetail reports whether the done view has skipped files or
+// errors worth showing in the scrollable detail pane.
+func hasResultDetail(wiz *initWizard) bool {
+	return len(wiz.resultLines) > 0 || len(wiz.skippedFiles) > 0
+}
+
+// newResultView builds the scrollable viewport listing the errors and
+// skipped files of a finished init.
+func (m Model) newResultView(wiz *initWizard) viewport.Model {
+	var lines []string
+	if len(wiz.resultLines) > 0 {
+		lines = append(lines, ErrorStyle.Render("Errors:"))
+		for _, e := range wiz.resultLines {
+			lines = append(lines, "  "+e)
+		}
+	}
+	if len(wiz.skippedFiles) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, MutedStyle.Render("Skipped:"))
+		for _, f := range wiz.skippedFiles {
+			lines = append(lines, "  "+MutedStyle.Render(f))
+		}
+	}
+
+	width, height := m.width-4, m.height-12
+	if width < 40 {
+		width = 76
+	}
+	if height < 5 {
+		height = 12
+	}
+	if len(lines) < height {
+		height = len(lines)
+	}
+
+	vp := viewport.New(width, height)
+	vp.SetContent(strings.Join(lines, "\n"))
+	return vp
+}
+
+// errorSummary formats the errors of a finished init as plain text for
+// pasting into a bug report.
+func errorSummary(wiz *initWizard) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ocmgr init: %s\n", strings.Join(wiz.resolvedNames, " → "))
+	fmt.Fprintf(&b, "copied %d, skipped %d, %d errors\n", wiz.copyCount, wiz.skipCount, len(wiz.resultLines))
+	for _, e := range wiz.resultLines {
+		fmt.Fprintf(&b, "  %s\n", e)
+	}
 	return b.String()
 }
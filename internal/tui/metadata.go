@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/resolver"
+)
+
+// metaItem is the synthetic first entry of the editor's file list that
+// opens the metadata form.
+type metaItem struct{}
+
+func (metaItem) Title() string       { return "⚙ Edit metadata" }
+func (metaItem) Description() string { return "" }
+func (metaItem) FilterValue() string { return "metadata profile.toml" }
+
+// Fields of the metadata form, in tab order.
+const (
+	metaDescription = iota
+	metaTags
+	metaVersion
+	metaExtends
+	metaFieldCount
+)
+
+var metaLabels = [metaFieldCount]string{
+	"Description: ",
+	"Tags:        ",
+	"Version:     ",
+	"Extends:     ",
+}
+
+// metadataForm holds the inputs of the metadata form.
+type metadataForm struct {
+	inputs [metaFieldCount]textinput.Model
+	focus  int
+	errMsg string
+}
+
+// openMetadataForm fills the form from the profile being edited.
+func (m Model) openMetadataForm() (tea.Model, tea.Cmd) {
+	ed := m.editor
+	p := ed.profile
+
+	values := [metaFieldCount]string{
+		metaDescription: p.Description,
+		metaTags:        strings.Join(p.Tags, ", "),
+		metaVersion:     p.Version,
+		metaExtends:     strings.Join(p.Extends, ", "),
+	}
+	placeholders := [metaFieldCount]string{
+		metaDescription: "A brief description",
+		metaTags:        "go, backend, api",
+		metaVersion:     "1.0.0",
+		metaExtends:     "base, go",
+	}
+
+	form := &metadataForm{}
+	for i := range form.inputs {
+		ti := textinput.New()
+		ti.Placeholder = placeholders[i]
+		ti.CharLimit = 200
+		ti.Width = m.inputWidth(50)
+		ti.SetValue(values[i])
+		form.inputs[i] = ti
+	}
+
+	ed.meta = form
+	ed.step = editorStepMeta
+	ed.errMsg = ""
+	ed.statusMsg = ""
+	return m, form.inputs[0].Focus()
+}
+
+func (m Model) updateMetadataForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	ed := m.editor
+	form := ed.meta
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("tab", "down"))):
+			return m, form.focusField((form.focus + 1) % metaFieldCount)
+		case key.Matches(msg, key.NewBinding(key.WithKeys("shift+tab", "up"))):
+			return m, form.focusField((form.focus + metaFieldCount - 1) % metaFieldCount)
+		case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+			if err := m.saveMetadata(); err != nil {
+				form.errMsg = err.Error()
+				return m, nil
+			}
+			ed.meta = nil
+			ed.step = editorStepFileList
+			ed.statusMsg = "Saved profile.toml"
+			return m, nil
+		case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
+			return m.goBack(), nil
+		}
+	}
+
+	var cmd tea.Cmd
+	form.inputs[form.focus], cmd = form.inputs[form.focus].Update(msg)
+	return m, cmd
+}
+
+// focusField moves the cursor to input i.
+func (f *metadataForm) focusField(i int) tea.Cmd {
+	f.inputs[f.focus].Blur()
+	f.focus = i
+	return f.inputs[i].Focus()
+}
+
+// saveMetadata validates the form and writes it to profile.toml. The
+// profile is only modified once every field is valid.
+func (m Model) saveMetadata() error {
+	ed := m.editor
+	form := ed.meta
+	p := ed.profile
+
+	version := strings.TrimSpace(form.inputs[metaVersion].Value())
+	if err := profile.ValidateVersion(version); err != nil {
+		return err
+	}
+
+	extends := splitList(form.inputs[metaExtends].Value())
+	for _, parent := range extends {
+		if parent == p.Name {
+			return fmt.Errorf("a profile cannot extend itself")
+		}
+		if !m.store.Exists(parent) {
+			return fmt.Errorf("extends: profile %q not found", parent)
+		}
+	}
+
+	// Make sure the new parents do not lead back to this profile.
+	load := func(name string) ([]string, error) {
+		if name == p.Name {
+			return extends, nil
+		}
+		other, err := m.store.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return other.Extends, nil
+	}
+	if _, err := resolver.Resolve([]string{p.Name}, load); err != nil {
+		return err
+	}
+
+	updated := *p
+	updated.Description = strings.TrimSpace(form.inputs[metaDescription].Value())
+	updated.Tags = splitList(form.inputs[metaTags].Value())
+	updated.Version = version
+	updated.Extends = extends
+	if err := profile.SaveProfile(&updated); err != nil {
+		return fmt.Errorf("saving profile.toml: %w", err)
+	}
+	*p = updated
+	return nil
+}
+
+// splitList splits a comma-separated value, dropping blanks and
+// duplicates.
+func splitList(s string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+func (m Model) viewMetadataForm() string {
+	ed := m.editor
+	form := ed.meta
+
+	var b strings.Builder
+	b.WriteString(SubtitleStyle.Render("Edit Metadata — " + ed.profile.Name))
+	b.WriteString("\n\n")
+	for i, in := range form.inputs {
+		b.WriteString("  " + metaLabels[i])
+		b.WriteString(in.View())
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(MutedStyle.Render("  Tags and extends are comma-separated; version is semver (1.2.3)."))
+	if form.errMsg != "" {
+		b.WriteString("\n\n")
+		b.WriteString(ErrorStyle.Render("  ✗ " + form.errMsg))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(HelpStyle.Render("tab/↓: next field • shift+tab/↑: previous • enter: save • esc: cancel"))
+	return b.String()
+}
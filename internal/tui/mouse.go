@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listClick handles a mouse event over l, which is drawn at the top of
+// the screen. The wheel moves the cursor; a left click selects the
+// item under the pointer and reports it as clicked so the caller can
+// activate it.
+func listClick(l *list.Model, msg tea.MouseMsg) (clicked bool) {
+	if msg.Action != tea.MouseActionPress {
+		return false
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		l.CursorUp()
+		return false
+	case tea.MouseButtonWheelDown:
+		l.CursorDown()
+		return false
+	case tea.MouseButtonLeft:
+		index, ok := listIndexAt(*l, msg.Y)
+		if !ok {
+			return false
+		}
+		l.Select(index)
+		return true
+	}
+	return false
+}
+
+// listIndexAt returns the index, among the visible items of l, of the
+// item drawn at row y of the list's own output, if any.
+func listIndexAt(l list.Model, y int) (int, bool) {
+	// Items start below the title and status bar.
+	if l.ShowTitle() || (l.ShowFilter() && l.FilteringEnabled()) {
+		y -= lipgloss.Height(l.Styles.TitleBar.Render(l.Styles.Title.Render(l.Title)))
+	}
+	if l.ShowStatusBar() {
+		y -= lipgloss.Height(l.Styles.StatusBar.Render(" "))
+	}
+	if y < 0 {
+		return 0, false
+	}
+
+	// Every list in the TUI uses the default delegate: each item takes
+	// its height in rows, followed by blank spacing rows.
+	d := list.NewDefaultDelegate()
+	row := y / (d.Height() + d.Spacing())
+	if y%(d.Height()+d.Spacing()) >= d.Height() || row >= l.Paginator.PerPage {
+		return 0, false
+	}
+	index := l.Paginator.Page*l.Paginator.PerPage + row
+	if index >= len(l.VisibleItems()) {
+		return 0, false
+	}
+	return index, true
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/acchapm1/ocmgr/internal/copier"
 	"github.com/acchapm1/ocmgr/internal/profile"
+	"github.com/acchapm1/ocmgr/internal/provenance"
 )
 
 // snapStep tracks the current step in the snapshot wizard.
@@ -55,23 +56,23 @@ func (m Model) loadSnapshotWizard() (tea.Model, tea.Cmd) {
 	ni := textinput.New()
 	ni.Placeholder = "my-profile"
 	ni.CharLimit = 64
-	ni.Width = 40
+	ni.Width = m.inputWidth(40)
 	ni.Focus()
 
 	di := textinput.New()
 	di.Placeholder = "."
 	di.CharLimit = 256
-	di.Width = 50
+	di.Width = m.inputWidth(50)
 
 	desc := textinput.New()
 	desc.Placeholder = "A brief description"
 	desc.CharLimit = 200
-	desc.Width = 50
+	desc.Width = m.inputWidth(50)
 
 	tags := textinput.New()
 	tags.Placeholder = "go, backend, api"
 	tags.CharLimit = 200
-	tags.Width = 50
+	tags.Width = m.inputWidth(50)
 
 	m.snapWiz = &snapshotWizard{
 		step:      snapStepName,
@@ -290,6 +291,11 @@ func (m Model) runSnapshot() tea.Cmd {
 			}
 		}()
 
+		ignore, err := copier.LoadIgnore(openCodeDir)
+		if err != nil {
+			return snapDoneMsg{err: fmt.Errorf("reading %s: %w", copier.IgnoreFile, err)}
+		}
+
 		totalFiles := 0
 		for _, dir := range profile.ContentDirs() {
 			srcDir := filepath.Join(openCodeDir, dir)
@@ -305,7 +311,10 @@ func (m Model) runSnapshot() tea.Cmd {
 				}
 				// Skip infrastructure files
 				switch info.Name() {
-				case "node_modules", "package.json", "bun.lock", ".gitignore":
+				case "node_modules", "package.json", "bun.lock", ".gitignore", provenance.FileName:
+					return nil
+				}
+				if ocRel, err := filepath.Rel(openCodeDir, path); err == nil && ignore.Match(ocRel, false) {
 					return nil
 				}
 				rel, err := filepath.Rel(srcDir, path)
@@ -324,7 +333,12 @@ func (m Model) runSnapshot() tea.Cmd {
 			}
 		}
 
+		if _, err := profile.PruneEmptyDirs(p); err != nil {
+			return snapDoneMsg{err: fmt.Errorf("pruning empty directories: %w", err)}
+		}
+
 		p.Description = desc
+		p.Version = profile.InitialVersion
 		if tagsRaw != "" {
 			for _, t := range strings.Split(tagsRaw, ",") {
 				t = strings.TrimSpace(t)
@@ -2,36 +2,147 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/acchapm1/ocmgr/internal/config"
 	gh "github.com/acchapm1/ocmgr/internal/github"
+	"github.com/acchapm1/ocmgr/internal/store"
 )
 
+// syncState is the sync state of a single profile, one per category of
+// github.SyncStatus. The order is the order items are listed in, so
+// profiles that need attention come first and in-sync ones last.
+type syncState int
+
+const (
+	syncModified syncState = iota
+	syncLocalOnly
+	syncRemoteOnly
+	syncInSync
+)
+
+// label returns the marker and description shown in the status list.
+func (s syncState) label() string {
+	switch s {
+	case syncModified:
+		return "~ modified"
+	case syncLocalOnly:
+		return "● local only"
+	case syncRemoteOnly:
+		return "○ remote only"
+	}
+	return "✓ in sync"
+}
+
+// canPush reports whether a profile in this state has local changes to push.
+func (s syncState) canPush() bool { return s == syncLocalOnly || s == syncModified }
+
+// canPull reports whether a profile in this state has remote changes to pull.
+func (s syncState) canPull() bool { return s == syncRemoteOnly || s == syncModified }
+
+// syncItem implements list.Item for the sync status list.
+type syncItem struct {
+	name  string
+	state syncState
+	// remoteCommits is the number of remote commits to the profile since
+	// it was last pulled or pushed.
+	remoteCommits int
+}
+
+func (i syncItem) Title() string { return i.name }
+func (i syncItem) Description() string {
+	switch i.remoteCommits {
+	case 0:
+		return i.state.label()
+	case 1:
+		return i.state.label() + " · 1 new remote commit"
+	}
+	return fmt.Sprintf("%s · %d new remote commits", i.state.label(), i.remoteCommits)
+}
+func (i syncItem) FilterValue() string { return i.name }
+
 // syncStatus holds state for the sync status view.
 type syncStatus struct {
-	lines  []string
+	repo   string
+	list   list.Model
 	errMsg string
 	loaded bool
+
+	// errView scrolls errMsg, which can hold the whole output of a
+	// failed git command.
+	errView viewport.Model
+
+	// busy is set while a push or pull runs; busyMsg describes it.
+	busy    bool
+	busyMsg string
+	spinner spinner.Model
+
+	// Result of the last push or pull.
+	resultMsg string
+	resultErr string
 }
 
 // syncLoadedMsg is sent when sync status finishes loading.
 type syncLoadedMsg struct {
-	lines []string
+	repo  string
+	items []syncItem
 	err   error
 }
 
+// syncOpDoneMsg is sent when a push or pull started from the sync view
+// completes.
+type syncOpDoneMsg struct {
+	msg string
+	err error
+}
+
 // ── Load ─────────────────────────────────────────────────────────────
 
 func (m Model) loadSyncStatus() (tea.Model, tea.Cmd) {
 	m.currentView = viewSync
-	m.syncSt = &syncStatus{loaded: false}
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = StatusStyle
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(ColorPrimary).BorderLeftForeground(ColorPrimary)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(ColorSecondary).BorderLeftForeground(ColorPrimary)
+
+	l := list.New(nil, delegate, m.width, m.syncListHeight())
+	l.Title = "Sync Status"
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(true)
+	l.SetShowHelp(false)
+	l.Styles.Title = TitleStyle.Copy().
+		Background(ColorSecondary).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Padding(0, 1)
+
+	m.syncSt = &syncStatus{loaded: false, spinner: sp, list: l}
 	return m, m.fetchSyncStatus()
 }
 
+// syncListHeight leaves room below the list for the repository line,
+// the operation status and the help bar, including every line of a
+// multi-line error from the last push or pull.
+func (m Model) syncListHeight() int {
+	extra := 0
+	if m.syncSt != nil {
+		extra = strings.Count(m.syncSt.resultErr, "\n")
+	}
+	return max(m.height-6-extra, 5)
+}
+
 func (m Model) fetchSyncStatus() tea.Cmd {
 	storeDir := m.store.Dir
 	return func() tea.Msg {
@@ -43,37 +154,82 @@ func (m Model) fetchSyncStatus() tea.Cmd {
 			return syncLoadedMsg{err: fmt.Errorf("github.repo is not configured; run: ocmgr config set github.repo <owner/repo>")}
 		}
 
-		status, err := gh.Status(storeDir, cfg.GitHub.Repo, cfg.GitHub.Auth)
+		status, err := gh.Status(storeDir, cfg.GitHub.Repo, cfg.GitHub.Auth, "", "")
 		if err != nil {
 			return syncLoadedMsg{err: err}
 		}
 
-		var lines []string
-		lines = append(lines, fmt.Sprintf("Repository: %s", cfg.GitHub.Repo))
-		lines = append(lines, "")
-
-		total := len(status.InSync) + len(status.Modified) + len(status.LocalOnly) + len(status.RemoteOnly)
-		if total == 0 {
-			lines = append(lines, "No profiles found locally or remotely.")
-		} else {
-			lines = append(lines, fmt.Sprintf("%-20s %s", "PROFILE", "STATUS"))
-			lines = append(lines, strings.Repeat("─", 45))
-
-			for _, n := range status.InSync {
-				lines = append(lines, fmt.Sprintf("%-20s ✓ in sync", n))
+		var items []syncItem
+		add := func(names []string, state syncState) {
+			for _, n := range names {
+				items = append(items, syncItem{name: n, state: state, remoteCommits: status.RemoteCommits[n]})
 			}
-			for _, n := range status.Modified {
-				lines = append(lines, fmt.Sprintf("%-20s ~ modified", n))
+		}
+		add(status.InSync, syncInSync)
+		add(status.Modified, syncModified)
+		add(status.LocalOnly, syncLocalOnly)
+		add(status.RemoteOnly, syncRemoteOnly)
+
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].state != items[j].state {
+				return items[i].state < items[j].state
 			}
-			for _, n := range status.LocalOnly {
-				lines = append(lines, fmt.Sprintf("%-20s ● local only", n))
+			return items[i].name < items[j].name
+		})
+
+		return syncLoadedMsg{repo: cfg.GitHub.Repo, items: items}
+	}
+}
+
+// pushProfile pushes the named local profile, refusing protected
+// branches the same way "ocmgr sync push" does without --allow-protected.
+func (m Model) pushProfile(name string) tea.Cmd {
+	s := m.store
+	return func() tea.Msg {
+		cfg, err := config.Load()
+		if err != nil {
+			return syncOpDoneMsg{err: fmt.Errorf("loading config: %w", err)}
+		}
+		p, err := s.Get(name)
+		if err != nil {
+			return syncOpDoneMsg{err: err}
+		}
+
+		if len(cfg.GitHub.ProtectedBranches) > 0 {
+			branch, err := gh.CacheBranch(cfg.GitHub.Repo, cfg.GitHub.Auth, cfg.GitHub.Branch)
+			if err != nil {
+				return syncOpDoneMsg{err: fmt.Errorf("push failed: %w", err)}
 			}
-			for _, n := range status.RemoteOnly {
-				lines = append(lines, fmt.Sprintf("%-20s ○ remote only", n))
+			if cfg.GitHub.IsProtectedBranch(branch) {
+				return syncOpDoneMsg{err: fmt.Errorf("branch %q is protected; use: ocmgr sync push %s --allow-protected", branch, name)}
 			}
 		}
 
-		return syncLoadedMsg{lines: lines}
+		if err := gh.PushProfile(name, p.Path, cfg.GitHub.Repo, cfg.GitHub.Auth, cfg.GitHub.Branch); err != nil {
+			return syncOpDoneMsg{err: fmt.Errorf("push failed: %w", err)}
+		}
+		return syncOpDoneMsg{msg: fmt.Sprintf("Pushed profile '%s'", name)}
+	}
+}
+
+// pullProfile pulls the named profile from the remote into the store,
+// replacing any local copy.
+func (m Model) pullProfile(name string) tea.Cmd {
+	storeDir := m.store.Dir
+	return func() tea.Msg {
+		cfg, err := config.Load()
+		if err != nil {
+			return syncOpDoneMsg{err: fmt.Errorf("loading config: %w", err)}
+		}
+		unlock, err := store.Lock()
+		if err != nil {
+			return syncOpDoneMsg{err: err}
+		}
+		defer unlock()
+		if _, err := gh.PullProfile(name, storeDir, cfg.GitHub.Repo, cfg.GitHub.Auth, "", false, false); err != nil {
+			return syncOpDoneMsg{err: fmt.Errorf("pull failed: %w", err)}
+		}
+		return syncOpDoneMsg{msg: fmt.Sprintf("Pulled profile '%s'", name)}
 	}
 }
 
@@ -88,24 +244,106 @@ func (m Model) updateSync(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case syncLoadedMsg:
 		ss.loaded = true
+		ss.busy = false
 		if msg.err != nil {
 			ss.errMsg = msg.err.Error()
+			style := ErrorStyle
+			if m.width > 0 {
+				style = style.Copy().Width(m.width)
+			}
+			ss.errView = viewport.New(m.width, m.syncListHeight())
+			ss.errView.SetContent(style.Render("✗ " + ss.errMsg))
+			return m, nil
+		}
+
+		// Keep the cursor on the same profile across refreshes.
+		var current string
+		if item, ok := ss.selected(); ok {
+			current = item.name
+		}
+		ss.repo = msg.repo
+		items := make([]list.Item, len(msg.items))
+		index := 0
+		for i, item := range msg.items {
+			items[i] = item
+			if item.name == current {
+				index = i
+			}
+		}
+		cmd := ss.list.SetItems(items)
+		ss.list.Select(index)
+		return m, cmd
+
+	case syncOpDoneMsg:
+		if msg.err != nil {
+			ss.resultMsg = ""
+			ss.resultErr = msg.err.Error()
 		} else {
-			ss.lines = msg.lines
+			ss.resultMsg = msg.msg
+			ss.resultErr = ""
 		}
-		return m, nil
+		ss.list.SetSize(m.width, m.syncListHeight())
+		ss.busyMsg = "Refreshing status..."
+		return m, m.fetchSyncStatus()
+
+	case spinner.TickMsg:
+		if !ss.busy {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		ss.spinner, cmd = ss.spinner.Update(msg)
+		return m, cmd
 
 	case tea.KeyMsg:
-		if ss.loaded {
-			if key.Matches(msg, key.NewBinding(key.WithKeys("esc", "q"))) {
-				m.currentView = viewMenu
-				m.syncSt = nil
-				return m, nil
+		if !ss.loaded || ss.busy {
+			return m, nil
+		}
+		// Don't intercept keys when filtering
+		if ss.list.FilterState() == list.Filtering {
+			break
+		}
+		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("p"))):
+			if item, ok := ss.selected(); ok && item.state.canPush() {
+				return m, ss.start(fmt.Sprintf("Pushing %s...", item.name), m.pushProfile(item.name))
+			}
+			return m, nil
+		case key.Matches(msg, key.NewBinding(key.WithKeys("P"))):
+			if item, ok := ss.selected(); ok && item.state.canPull() {
+				return m, ss.start(fmt.Sprintf("Pulling %s...", item.name), m.pullProfile(item.name))
 			}
+			return m, nil
 		}
 	}
 
-	return m, nil
+	if !ss.loaded {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	if ss.errMsg != "" {
+		ss.errView, cmd = ss.errView.Update(msg)
+		return m, cmd
+	}
+	ss.list, cmd = ss.list.Update(msg)
+	return m, cmd
+}
+
+// selected returns the profile item under the cursor, if any.
+func (ss *syncStatus) selected() (syncItem, bool) {
+	if ss.errMsg != "" {
+		return syncItem{}, false
+	}
+	item, ok := ss.list.SelectedItem().(syncItem)
+	return item, ok
+}
+
+// start marks the view busy and runs op alongside the spinner.
+func (ss *syncStatus) start(desc string, op tea.Cmd) tea.Cmd {
+	ss.busy = true
+	ss.busyMsg = desc
+	ss.resultMsg = ""
+	ss.resultErr = ""
+	return tea.Batch(ss.spinner.Tick, op)
 }
 
 // ── View ─────────────────────────────────────────────────────────────
@@ -118,31 +356,56 @@ func (m Model) viewSync() string {
 
 	var b strings.Builder
 
-	title := SubtitleStyle.Render("Sync Status")
-	b.WriteString(title)
-	b.WriteString("\n\n")
-
 	if !ss.loaded {
+		b.WriteString(SubtitleStyle.Render("Sync Status"))
+		b.WriteString("\n\n")
 		b.WriteString(StatusStyle.Render("⏳ Loading sync status..."))
 		return b.String()
 	}
 
 	if ss.errMsg != "" {
-		b.WriteString(ErrorStyle.Render("✗ " + ss.errMsg))
+		b.WriteString(SubtitleStyle.Render("Sync Status"))
 		b.WriteString("\n\n")
-		b.WriteString(HelpStyle.Render("esc: back"))
+		b.WriteString(ss.errView.View())
+		b.WriteString("\n")
+		help := "esc: back"
+		if ss.errView.TotalLineCount() > ss.errView.Height {
+			help = "↑/↓/pgup/pgdn: scroll • " + help
+		}
+		b.WriteString(HelpStyle.Render(help))
 		return b.String()
 	}
 
-	for _, line := range ss.lines {
-		b.WriteString("  ")
-		b.WriteString(line)
-		b.WriteString("\n")
+	b.WriteString(MutedStyle.Render("  Repository: " + ss.repo))
+	b.WriteString("\n")
+	if len(ss.list.Items()) == 0 {
+		b.WriteString("\n  No profiles found locally or remotely.\n")
+		b.WriteString(HelpStyle.Render("esc: back • q: back"))
+		return b.String()
 	}
-
+	b.WriteString(ss.list.View())
 	b.WriteString("\n")
-	b.WriteString(MutedStyle.Render("  Use CLI for push/pull: ocmgr sync push|pull <name>"))
+
+	switch {
+	case ss.busy:
+		b.WriteString(" " + ss.spinner.View() + " " + ss.busyMsg)
+	case ss.resultErr != "":
+		b.WriteString(ErrorStyle.Render("✗ " + ss.resultErr))
+	case ss.resultMsg != "":
+		b.WriteString(StatusStyle.Render("✓ " + ss.resultMsg))
+	}
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("esc: back • q: back"))
+
+	help := []string{"↑/↓: select"}
+	if item, ok := ss.selected(); ok {
+		if item.state.canPush() {
+			help = append(help, "p: push")
+		}
+		if item.state.canPull() {
+			help = append(help, "P: pull")
+		}
+	}
+	help = append(help, "/: filter", "esc: back")
+	b.WriteString(HelpStyle.Render(strings.Join(help, " • ")))
 	return b.String()
 }
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/acchapm1/ocmgr/internal/profile"
+)
+
+// tagPicker holds state for the tag filter overlay of the profile
+// browser.
+type tagPicker struct {
+	tags     []string
+	selected map[string]bool // keyed by lower-cased tag
+	cursor   int
+}
+
+// openTagPicker lists every tag used in the store, with the tags of the
+// current filter already selected.
+func (m Model) openTagPicker() (tea.Model, tea.Cmd) {
+	m.statusMsg = ""
+	m.errMsg = ""
+
+	tags := collectTags(m.profiles)
+	if len(tags) == 0 {
+		m.statusMsg = "No profiles have tags"
+		return m, nil
+	}
+
+	tp := &tagPicker{tags: tags, selected: make(map[string]bool)}
+	for _, t := range m.tagFilter {
+		tp.selected[strings.ToLower(t)] = true
+	}
+	m.tagPick = tp
+	return m, nil
+}
+
+func (m Model) updateTagPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tp := m.tagPick
+	switch {
+	case key.Matches(msg, key.NewBinding(key.WithKeys("up", "k"))):
+		if tp.cursor > 0 {
+			tp.cursor--
+		}
+	case key.Matches(msg, key.NewBinding(key.WithKeys("down", "j"))):
+		if tp.cursor < len(tp.tags)-1 {
+			tp.cursor++
+		}
+	case key.Matches(msg, key.NewBinding(key.WithKeys(" ", "x"))):
+		k := strings.ToLower(tp.tags[tp.cursor])
+		tp.selected[k] = !tp.selected[k]
+	case key.Matches(msg, key.NewBinding(key.WithKeys("c"))):
+		tp.selected = make(map[string]bool)
+	case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+		var filter []string
+		for _, t := range tp.tags {
+			if tp.selected[strings.ToLower(t)] {
+				filter = append(filter, t)
+			}
+		}
+		m.tagPick = nil
+		m.tagFilter = filter
+		cmd := m.applyTagFilter()
+		return m, cmd
+	}
+	return m, nil
+}
+
+// applyTagFilter replaces the browser's items with the profiles that
+// carry every tag in m.tagFilter.
+func (m *Model) applyTagFilter() tea.Cmd {
+	var items []list.Item
+	for _, p := range m.profiles {
+		if hasAllTags(p, m.tagFilter) {
+			items = append(items, profileItem{profile: p})
+		}
+	}
+
+	m.profileList.Title = "Profiles"
+	if len(m.tagFilter) > 0 {
+		m.profileList.Title += " · " + strings.Join(m.tagFilter, ", ")
+	}
+	m.profileList.ResetFilter()
+	cmd := m.profileList.SetItems(items)
+	m.profileList.Select(0)
+	return cmd
+}
+
+func (m Model) viewTagPicker() string {
+	tp := m.tagPick
+	var b strings.Builder
+	b.WriteString(SubtitleStyle.Render("Filter by Tags"))
+	b.WriteString("\n\n")
+	for i, t := range tp.tags {
+		cursor := " "
+		if i == tp.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if tp.selected[strings.ToLower(t)] {
+			check = "[x]"
+		}
+		line := cursor + check + " " + t
+		if i == tp.cursor {
+			b.WriteString(MenuSelectedStyle.Render(line))
+		} else {
+			b.WriteString(MenuItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(MutedStyle.Render("  Profiles must carry every selected tag."))
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("space: toggle • c: clear • enter: apply • esc: cancel"))
+	return b.String()
+}
+
+// collectTags returns the distinct tags of profiles, sorted and compared
+// case-insensitively; the first spelling seen is kept.
+func collectTags(profiles []*profile.Profile) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, p := range profiles {
+		for _, t := range p.Tags {
+			k := strings.ToLower(t)
+			if t == "" || seen[k] {
+				continue
+			}
+			seen[k] = true
+			tags = append(tags, t)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+	return tags
+}
+
+// hasAllTags reports whether p carries every tag in want, ignoring case.
+func hasAllTags(p *profile.Profile, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, t := range p.Tags {
+			if strings.EqualFold(t, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -1,76 +1,204 @@
 // Package tui provides the lipgloss theme for the ocmgr TUI.
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
 
-// Colors
+	"github.com/acchapm1/ocmgr/internal/config"
+)
+
+// Theme is a set of colours for the TUI.
+type Theme struct {
+	Primary   lipgloss.Color
+	Secondary lipgloss.Color
+	Success   lipgloss.Color
+	Warning   lipgloss.Color
+	Error     lipgloss.Color
+	Muted     lipgloss.Color
+	Text      lipgloss.Color
+	Bg        lipgloss.Color
+}
+
+// Themes are the colours of the built-in themes named by
+// config.ThemeNames, selected with theme.name in config.toml or the
+// --theme flag.
+var Themes = map[string]Theme{
+	"dark": {
+		Primary:   "#7C3AED", // violet
+		Secondary: "#06B6D4", // cyan
+		Success:   "#10B981", // green
+		Warning:   "#F59E0B", // amber
+		Error:     "#EF4444", // red
+		Muted:     "#6B7280", // gray
+		Text:      "#E5E7EB", // light gray
+		Bg:        "#1F2937", // dark gray
+	},
+	"light": {
+		Primary:   "#6D28D9", // deep violet
+		Secondary: "#0E7490", // dark cyan
+		Success:   "#047857", // dark green
+		Warning:   "#B45309", // dark amber
+		Error:     "#B91C1C", // dark red
+		Muted:     "#4B5563", // slate gray
+		Text:      "#111827", // near black
+		Bg:        "#F9FAFB", // off white
+	},
+}
+
+// ConfigTheme returns the theme described by the [theme] section of
+// config.toml: the named built-in theme, dark when no name is set, with
+// the colours the section sets replacing the theme's own.
+func ConfigTheme(c config.Theme) (Theme, error) {
+	if err := c.Validate(); err != nil {
+		return Theme{}, err
+	}
+	name := c.Name
+	if name == "" {
+		name = config.DefaultThemeName
+	}
+	t := Themes[name]
+
+	overrides := []struct {
+		value string
+		color *lipgloss.Color
+	}{
+		{c.Primary, &t.Primary},
+		{c.Secondary, &t.Secondary},
+		{c.Success, &t.Success},
+		{c.Warning, &t.Warning},
+		{c.Error, &t.Error},
+		{c.Muted, &t.Muted},
+		{c.Text, &t.Text},
+		{c.Background, &t.Bg},
+	}
+	for _, o := range overrides {
+		if o.value != "" {
+			*o.color = lipgloss.Color(o.value)
+		}
+	}
+	return t, nil
+}
+
+// SetTheme replaces the colours below with those of t and rebuilds the
+// styles from them. It must be called before NewModel, as the lists
+// copy the colours when they are created.
+func SetTheme(t Theme) {
+	ColorPrimary = t.Primary
+	ColorSecondary = t.Secondary
+	ColorSuccess = t.Success
+	ColorWarning = t.Warning
+	ColorError = t.Error
+	ColorMuted = t.Muted
+	ColorText = t.Text
+	ColorBg = t.Bg
+	buildStyles()
+}
+
+func init() {
+	SetTheme(Themes[config.DefaultThemeName])
+}
+
+// Colors, set by SetTheme
 var (
-	ColorPrimary   = lipgloss.Color("#7C3AED") // violet
-	ColorSecondary = lipgloss.Color("#06B6D4") // cyan
-	ColorSuccess   = lipgloss.Color("#10B981") // green
-	ColorWarning   = lipgloss.Color("#F59E0B") // amber
-	ColorError     = lipgloss.Color("#EF4444") // red
-	ColorMuted     = lipgloss.Color("#6B7280") // gray
-	ColorText      = lipgloss.Color("#E5E7EB") // light gray
-	ColorBg        = lipgloss.Color("#1F2937") // dark gray
+	ColorPrimary   lipgloss.Color
+	ColorSecondary lipgloss.Color
+	ColorSuccess   lipgloss.Color
+	ColorWarning   lipgloss.Color
+	ColorError     lipgloss.Color
+	ColorMuted     lipgloss.Color
+	ColorText      lipgloss.Color
+	ColorBg        lipgloss.Color
 )
 
-// Styles
+// Styles, built from the colours by SetTheme
 var (
 	// TitleStyle is used for the app title bar.
-	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorPrimary).
-			PaddingLeft(1)
+	TitleStyle lipgloss.Style
 
 	// SubtitleStyle is used for section subtitles.
-	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(ColorSecondary).
-			PaddingLeft(1)
+	SubtitleStyle lipgloss.Style
 
 	// MenuItemStyle is the default style for menu items.
-	MenuItemStyle = lipgloss.NewStyle().
-			PaddingLeft(2)
+	MenuItemStyle lipgloss.Style
 
 	// MenuSelectedStyle is the style for the currently selected menu item.
-	MenuSelectedStyle = lipgloss.NewStyle().
-				Foreground(ColorPrimary).
-				Bold(true).
-				PaddingLeft(1)
+	MenuSelectedStyle lipgloss.Style
 
 	// HelpStyle is used for the help bar at the bottom.
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(ColorMuted).
-			PaddingLeft(1).
-			PaddingTop(1)
+	HelpStyle lipgloss.Style
 
 	// StatusStyle is used for status messages.
-	StatusStyle = lipgloss.NewStyle().
-			Foreground(ColorSuccess).
-			PaddingLeft(1)
+	StatusStyle lipgloss.Style
 
 	// ErrorStyle is used for error messages.
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorError).
-			PaddingLeft(1)
+	ErrorStyle lipgloss.Style
+
+	// WarningStyle is used for warnings that need attention but are not
+	// errors.
+	WarningStyle lipgloss.Style
 
 	// MutedStyle is used for secondary/muted text.
-	MutedStyle = lipgloss.NewStyle().
-			Foreground(ColorMuted)
+	MutedStyle lipgloss.Style
 
 	// BorderStyle is used for bordered panels.
-	BorderStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorPrimary).
-			Padding(0, 1)
+	BorderStyle lipgloss.Style
 
 	// DetailLabelStyle is used for labels in detail views.
-	DetailLabelStyle = lipgloss.NewStyle().
-				Foreground(ColorSecondary).
-				Bold(true).
-				Width(14)
+	DetailLabelStyle lipgloss.Style
 
 	// DetailValueStyle is used for values in detail views.
-	DetailValueStyle = lipgloss.NewStyle().
-				Foreground(ColorText)
+	DetailValueStyle lipgloss.Style
 )
+
+// buildStyles sets the styles from the current colours.
+func buildStyles() {
+	TitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorPrimary).
+		PaddingLeft(1)
+
+	SubtitleStyle = lipgloss.NewStyle().
+		Foreground(ColorSecondary).
+		PaddingLeft(1)
+
+	MenuItemStyle = lipgloss.NewStyle().
+		PaddingLeft(2)
+
+	MenuSelectedStyle = lipgloss.NewStyle().
+		Foreground(ColorPrimary).
+		Bold(true).
+		PaddingLeft(1)
+
+	HelpStyle = lipgloss.NewStyle().
+		Foreground(ColorMuted).
+		PaddingLeft(1).
+		PaddingTop(1)
+
+	StatusStyle = lipgloss.NewStyle().
+		Foreground(ColorSuccess).
+		PaddingLeft(1)
+
+	ErrorStyle = lipgloss.NewStyle().
+		Foreground(ColorError).
+		PaddingLeft(1)
+
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(ColorWarning).
+		PaddingLeft(1)
+
+	MutedStyle = lipgloss.NewStyle().
+		Foreground(ColorMuted)
+
+	BorderStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorPrimary).
+		Padding(0, 1)
+
+	DetailLabelStyle = lipgloss.NewStyle().
+		Foreground(ColorSecondary).
+		Bold(true).
+		Width(14)
+
+	DetailValueStyle = lipgloss.NewStyle().
+		Foreground(ColorText)
+}
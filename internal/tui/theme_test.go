@@ -0,0 +1,18 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/acchapm1/ocmgr/internal/config"
+)
+
+func TestThemesCoverConfigNames(t *testing.T) {
+	for _, name := range config.ThemeNames() {
+		if _, ok := Themes[name]; !ok {
+			t.Errorf("no colours for theme %q", name)
+		}
+	}
+	if len(Themes) != len(config.ThemeNames()) {
+		t.Errorf("Themes has %d entries, config.ThemeNames %d", len(Themes), len(config.ThemeNames()))
+	}
+}
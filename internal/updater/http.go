@@ -0,0 +1,48 @@
+package updater
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// retryDelays are the pauses before each retry of a failed request; a
+// request is attempted len(retryDelays)+1 times in total.
+var retryDelays = []time.Duration{1 * time.Second, 3 * time.Second}
+
+var (
+	// apiClient talks to the GitHub releases API.
+	apiClient = &http.Client{Timeout: 10 * time.Second}
+	// downloadClient fetches release assets, which may be large.
+	downloadClient = &http.Client{Timeout: 5 * time.Minute}
+)
+
+// getWithRetry issues a GET for url, retrying with backoff when the
+// request fails at the network level or the server answers 429 or 5xx.
+// Any other response is returned as is for the caller to check; after
+// the last attempt the final error or response is returned. Each retry
+// is reported through the status function.
+func (u *Updater) getWithRetry(client *http.Client, url string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Get(url)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if attempt == len(retryDelays) {
+			return resp, err
+		}
+
+		reason := fmt.Sprint(err)
+		if err == nil {
+			reason = resp.Status
+			resp.Body.Close()
+		}
+		u.report("! Request failed (%s); retrying in %s...", reason, retryDelays[attempt])
+		time.Sleep(retryDelays[attempt])
+	}
+}
+
+// retryableStatus reports whether an HTTP status is likely transient.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
@@ -0,0 +1,42 @@
+package updater
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetWithRetryReportsRetries(t *testing.T) {
+	old := retryDelays
+	retryDelays = []time.Duration{0, 0}
+	t.Cleanup(func() { retryDelays = old })
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var status []string
+	u := New("v1.0.0")
+	u.SetStatus(func(msg string) { status = append(status, msg) })
+
+	resp, err := u.getWithRetry(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("status %d after %d calls, want 200 after 2", resp.StatusCode, calls)
+	}
+	if len(status) != 1 || !strings.Contains(status[0], "503") {
+		t.Errorf("status messages = %q, want one retry notice", status)
+	}
+}
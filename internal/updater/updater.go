@@ -2,17 +2,20 @@
 package updater
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
-	"time"
+
+	"github.com/acchapm1/ocmgr/internal/config"
 )
 
 const (
@@ -27,6 +30,12 @@ type Release struct {
 	Name    string  `json:"name"`
 	Assets  []Asset `json:"assets"`
 	HTMLURL string  `json:"html_url"`
+	// Body holds the release notes, in Markdown.
+	Body string `json:"body"`
+	// Prerelease is set for release candidates, betas and the like.
+	Prerelease bool `json:"prerelease"`
+	// Draft is set for unpublished releases, which are never installed.
+	Draft bool `json:"draft"`
 }
 
 // Asset represents a release asset.
@@ -39,6 +48,9 @@ type Asset struct {
 type Updater struct {
 	currentVersion string
 	installDir     string
+	skipChecksum   bool
+	prerelease     bool
+	status         func(msg string)
 }
 
 // New creates a new Updater.
@@ -48,10 +60,44 @@ func New(currentVersion string) *Updater {
 	}
 }
 
+// SetSkipChecksum disables verification of the downloaded archive against
+// the release's checksums.txt. It exists for releases published without
+// a checksum file and should not normally be used.
+func (u *Updater) SetSkipChecksum(skip bool) {
+	u.skipChecksum = skip
+}
+
+// SetPrerelease makes CheckForUpdate consider prereleases as well as
+// stable releases.
+func (u *Updater) SetPrerelease(include bool) {
+	u.prerelease = include
+}
+
+// SetStatus directs the progress messages of Update (the download,
+// checksum verification and retried requests) to f, one line per call
+// without a trailing newline. They are discarded unless it is called.
+func (u *Updater) SetStatus(f func(msg string)) {
+	u.status = f
+}
+
+// report formats a progress message and passes it to the status
+// function, if any.
+func (u *Updater) report(format string, args ...any) {
+	if u.status != nil {
+		u.status(fmt.Sprintf(format, args...))
+	}
+}
+
 // CheckForUpdate checks if a newer version is available.
 // Returns the latest release if an update is available, nil otherwise.
 func (u *Updater) CheckForUpdate() (*Release, error) {
-	latest, err := u.getLatestRelease()
+	var latest *Release
+	var err error
+	if u.prerelease {
+		latest, err = u.getNewestRelease()
+	} else {
+		latest, err = u.getLatestRelease()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("checking for updates: %w", err)
 	}
@@ -68,8 +114,7 @@ func (u *Updater) CheckForUpdate() (*Release, error) {
 func (u *Updater) GetRelease(tag string) (*Release, error) {
 	url := fmt.Sprintf("%s/releases/tags/%s", githubAPIURL, tag)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := u.getWithRetry(apiClient, url)
 	if err != nil {
 		return nil, fmt.Errorf("fetching release %s: %w", tag, err)
 	}
@@ -115,7 +160,7 @@ func (u *Updater) Update(release *Release) error {
 		return fmt.Errorf("no binary found for platform %s in release %s", platform, release.TagName)
 	}
 
-	fmt.Printf("Downloading %s...\n", asset.Name)
+	u.report("Downloading %s...", asset.Name)
 
 	// Download to temp file
 	tmpDir, err := os.MkdirTemp("", "ocmgr-update")
@@ -129,6 +174,16 @@ func (u *Updater) Update(release *Release) error {
 		return fmt.Errorf("downloading: %w", err)
 	}
 
+	// Verify the download before touching the installed binary.
+	if u.skipChecksum {
+		u.report("! Skipping checksum verification")
+	} else {
+		if err := u.verifyChecksum(release, asset.Name, tmpFile, tmpDir); err != nil {
+			return err
+		}
+		u.report("✓ Checksum verified")
+	}
+
 	// Extract the binary
 	binaryPath, err := u.extractBinary(tmpFile, tmpDir)
 	if err != nil {
@@ -140,8 +195,6 @@ func (u *Updater) Update(release *Release) error {
 		return fmt.Errorf("replacing binary: %w", err)
 	}
 
-	fmt.Printf("✓ Updated ocmgr to %s\n", release.TagName)
-
 	return nil
 }
 
@@ -149,8 +202,7 @@ func (u *Updater) Update(release *Release) error {
 func (u *Updater) getLatestRelease() (*Release, error) {
 	url := fmt.Sprintf("%s/releases/latest", githubAPIURL)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := u.getWithRetry(apiClient, url)
 	if err != nil {
 		return nil, err
 	}
@@ -168,6 +220,48 @@ func (u *Updater) getLatestRelease() (*Release, error) {
 	return &release, nil
 }
 
+// getNewestRelease fetches the published releases from GitHub and
+// returns the one with the highest version, prereleases included.
+// Unlike /releases/latest, the list is ordered by creation date, so the
+// versions are compared rather than taking the first entry.
+func (u *Updater) getNewestRelease() (*Release, error) {
+	url := fmt.Sprintf("%s/releases?per_page=50", githubAPIURL)
+
+	resp, err := u.getWithRetry(apiClient, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var releases []Release
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, err
+	}
+
+	var newest *Release
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft || parseVersion(r.TagName) == nil {
+			continue
+		}
+		if newest == nil || CompareVersions(r.TagName, newest.TagName) > 0 {
+			newest = r
+		}
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("no releases found")
+	}
+	return newest, nil
+}
+
+// devVersionRe matches versions produced by "git describe" for commits
+// after a tag (v0.2.0-3-gabc1234) and uncommitted trees (-dirty).
+var devVersionRe = regexp.MustCompile(`-\d+-g[0-9a-f]+(-dirty)?$|-dirty$`)
+
 // isNewerVersion compares version strings.
 // Returns true if the new version is newer than current.
 func (u *Updater) isNewerVersion(newVersion string) bool {
@@ -175,34 +269,96 @@ func (u *Updater) isNewerVersion(newVersion string) bool {
 	new := strings.TrimPrefix(newVersion, "v")
 
 	// Handle dev/dirty versions
-	if strings.Contains(current, "-") || strings.Contains(current, "dirty") {
+	if devVersionRe.MatchString(current) || parseVersion(current) == nil && strings.Contains(current, "-") {
 		// Development build, always consider updates available
 		return true
 	}
 
-	// Simple semver comparison
-	re := regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
-	currentMatch := re.FindStringSubmatch(current)
-	newMatch := re.FindStringSubmatch(new)
-
-	if currentMatch == nil || newMatch == nil {
+	if parseVersion(current) == nil || parseVersion(new) == nil {
 		return new != current
 	}
+	return CompareVersions(new, current) > 0
+}
 
-	// Compare major.minor.patch
-	for i := 1; i <= 3; i++ {
-		var c, n int
-		fmt.Sscanf(currentMatch[i], "%d", &c)
-		fmt.Sscanf(newMatch[i], "%d", &n)
-		if n > c {
-			return true
+// version is a parsed semantic version.
+type version struct {
+	core [3]int
+	pre  []string // dot-separated prerelease identifiers, nil for a release
+}
+
+// semverRe matches major.minor.patch with an optional prerelease and
+// build metadata, after any leading "v" has been removed.
+var semverRe = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// parseVersion parses v as a semantic version, returning nil if it is
+// not one.
+func parseVersion(v string) *version {
+	m := semverRe.FindStringSubmatch(strings.TrimPrefix(v, "v"))
+	if m == nil {
+		return nil
+	}
+	var p version
+	for i := 0; i < 3; i++ {
+		p.core[i], _ = strconv.Atoi(m[i+1])
+	}
+	if m[4] != "" {
+		p.pre = strings.Split(m[4], ".")
+	}
+	return &p
+}
+
+// CompareVersions compares two semantic versions using semver
+// precedence and returns -1, 0 or 1. A prerelease sorts before the
+// release it leads up to (1.2.0-rc.1 < 1.2.0), numeric identifiers are
+// compared as numbers and sort before alphanumeric ones, and build
+// metadata is ignored. Both versions must be valid.
+func CompareVersions(a, b string) int {
+	va, vb := parseVersion(a), parseVersion(b)
+	for i := 0; i < 3; i++ {
+		if c := cmpInt(va.core[i], vb.core[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case va.pre == nil && vb.pre == nil:
+		return 0
+	case va.pre == nil:
+		return 1
+	case vb.pre == nil:
+		return -1
+	}
+
+	for i := 0; i < len(va.pre) && i < len(vb.pre); i++ {
+		x, y := va.pre[i], vb.pre[i]
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		var c int
+		switch {
+		case xerr == nil && yerr == nil:
+			c = cmpInt(xn, yn)
+		case xerr == nil:
+			c = -1
+		case yerr == nil:
+			c = 1
+		default:
+			c = strings.Compare(x, y)
 		}
-		if n < c {
-			return false
+		if c != 0 {
+			return c
 		}
 	}
+	return cmpInt(len(va.pre), len(vb.pre))
+}
 
-	return false // Same version
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
 }
 
 // findAsset finds the matching asset for the platform.
@@ -229,11 +385,69 @@ func (u *Updater) findAsset(release *Release, platform string) *Asset {
 	return nil
 }
 
+// checksumAsset finds the checksums file of a release, e.g. checksums.txt
+// or ocmgr_0.2.0_checksums.txt.
+func (u *Updater) checksumAsset(release *Release) *Asset {
+	for _, asset := range release.Assets {
+		if asset.Name == "checksums.txt" || strings.HasSuffix(asset.Name, "_checksums.txt") {
+			return &asset
+		}
+	}
+	return nil
+}
+
+// verifyChecksum downloads the release's checksums file and checks that
+// the SHA-256 of the downloaded archive at path matches the entry for
+// assetName.
+func (u *Updater) verifyChecksum(release *Release, assetName, path, tmpDir string) error {
+	sums := u.checksumAsset(release)
+	if sums == nil {
+		return fmt.Errorf("release %s has no checksums.txt; refusing to install an unverified binary (use --skip-checksum to override)", release.TagName)
+	}
+
+	sumsPath := filepath.Join(tmpDir, sums.Name)
+	if err := u.downloadFile(sums.BrowserDownloadURL, sumsPath); err != nil {
+		return fmt.Errorf("downloading %s: %w", sums.Name, err)
+	}
+	data, err := os.ReadFile(sumsPath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", sums.Name, err)
+	}
+
+	expected := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		// Lines look like "<sha256>  <file name>"; a "*" marks binary mode.
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == assetName {
+			expected = strings.ToLower(fields[0])
+			break
+		}
+	}
+	if expected == "" {
+		return fmt.Errorf("%s has no entry for %s; refusing to install an unverified binary (use --skip-checksum to override)", sums.Name, assetName)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening download: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("hashing download: %w", err)
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s; the download may be corrupted or tampered with", assetName, expected, actual)
+	}
+	return nil
+}
+
 // downloadFile downloads a file from URL to path.
 func (u *Updater) downloadFile(url, path string) error {
-	client := &http.Client{Timeout: 5 * time.Minute}
-
-	resp, err := client.Get(url)
+	resp, err := u.getWithRetry(downloadClient, url)
 	if err != nil {
 		return err
 	}
@@ -293,10 +507,14 @@ func (u *Updater) extractBinary(archivePath, destDir string) (string, error) {
 	return binaryPath, nil
 }
 
-// replaceBinary replaces the current binary with the new one.
+// replaceBinary replaces the current binary with the new one. The
+// previous binary is kept at <currentPath>.prev and its version recorded
+// in .last-version in config.ConfigDir so that Rollback can restore it.
 func (u *Updater) replaceBinary(currentPath, newBinaryPath string) error {
-	// Create backup of current binary
-	backupPath := currentPath + ".old"
+	// Keep the current binary as the rollback target, replacing any
+	// older one.
+	backupPath := prevPath(currentPath)
+	_ = os.Remove(backupPath)
 	if err := os.Rename(currentPath, backupPath); err != nil {
 		// On Windows, we might need to delete first
 		if runtime.GOOS == "windows" {
@@ -316,12 +534,85 @@ func (u *Updater) replaceBinary(currentPath, newBinaryPath string) error {
 		return fmt.Errorf("installing new binary: %w", err)
 	}
 
-	// Remove backup
-	os.Remove(backupPath)
+	// Recording the version is best-effort; the rollback still works
+	// without it, it just cannot say what it reverted to.
+	_ = writeLastVersion(u.currentVersion)
 
 	return nil
 }
 
+// Rollback swaps the binary saved by the last update back into place and
+// returns the version it reverted to ("" if it was not recorded). The
+// binary being replaced becomes the new rollback target, so running
+// Rollback twice returns to where it started.
+func (u *Updater) Rollback() (string, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding executable: %w", err)
+	}
+	execPath, err = filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return "", fmt.Errorf("resolving executable path: %w", err)
+	}
+
+	backupPath := prevPath(execPath)
+	if _, err := os.Stat(backupPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no previous version to roll back to (%s not found)", backupPath)
+		}
+		return "", fmt.Errorf("checking %s: %w", backupPath, err)
+	}
+
+	previous := readLastVersion()
+
+	// Swap <exec> and <exec>.prev via a temporary name.
+	swapPath := execPath + ".rollback"
+	_ = os.Remove(swapPath)
+	if err := os.Rename(execPath, swapPath); err != nil {
+		return "", fmt.Errorf("moving current binary aside: %w", err)
+	}
+	if err := os.Rename(backupPath, execPath); err != nil {
+		os.Rename(swapPath, execPath)
+		return "", fmt.Errorf("restoring previous binary: %w", err)
+	}
+	if err := os.Rename(swapPath, backupPath); err != nil {
+		return "", fmt.Errorf("keeping replaced binary: %w", err)
+	}
+
+	_ = writeLastVersion(u.currentVersion)
+
+	return previous, nil
+}
+
+// prevPath returns where the previous binary is kept for rollbacks.
+func prevPath(execPath string) string {
+	return execPath + ".prev"
+}
+
+// lastVersionPath returns the file recording the version of the binary
+// saved for rollbacks.
+func lastVersionPath() string {
+	return filepath.Join(config.ConfigDir(), ".last-version")
+}
+
+// writeLastVersion records version as the version of the saved binary.
+func writeLastVersion(version string) error {
+	if err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+	return os.WriteFile(lastVersionPath(), []byte(version+"\n"), 0o644)
+}
+
+// readLastVersion returns the recorded version of the saved binary, or ""
+// if none was recorded.
+func readLastVersion() string {
+	data, err := os.ReadFile(lastVersionPath())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
 // DetectInstallMethod returns how ocmgr was installed.
 func DetectInstallMethod() string {
 	execPath, err := os.Executable()